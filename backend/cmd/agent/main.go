@@ -0,0 +1,48 @@
+// kube-admin-agent：部署在目标集群内，主动向 kube-admin 建立反向隧道，
+// 使位于 NAT / 内网之后的集群无需暴露 apiserver 即可被纳管。
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kube-admin/kube-admin/backend/pkg/tunnel"
+)
+
+func main() {
+	cfg := tunnel.AgentConfig{
+		ServerURL:          os.Getenv("KUBE_ADMIN_URL"),
+		JoinToken:          os.Getenv("KUBE_ADMIN_JOIN_TOKEN"),
+		Namespace:          getEnv("POD_NAMESPACE", "kube-admin-agent"),
+		CredentialSecret:   getEnv("AGENT_CREDENTIAL_SECRET", "kube-admin-agent-credential"),
+		InsecureSkipVerify: os.Getenv("TLS_SKIP_VERIFY") == "true",
+	}
+	if cfg.InsecureSkipVerify {
+		log.Println("[WARN] TLS_SKIP_VERIFY=true，kube-admin 证书校验已关闭，仅限开发环境")
+	}
+
+	agent, err := tunnel.NewAgent(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("kube-admin-agent starting, server=%s", cfg.ServerURL)
+	if err := agent.Run(ctx); err != nil {
+		log.Fatalf("Agent exited: %v", err)
+	}
+	log.Println("kube-admin-agent exited")
+}
+
+// getEnv 读取环境变量，缺失时返回默认值
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -13,8 +13,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/kube-admin/kube-admin/backend/config"
 	"github.com/kube-admin/kube-admin/backend/database"
+	"github.com/kube-admin/kube-admin/backend/internal/api"
 	"github.com/kube-admin/kube-admin/backend/internal/model"
 	"github.com/kube-admin/kube-admin/backend/internal/router"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
 	"github.com/kube-admin/kube-admin/backend/internal/web"
 	"github.com/kube-admin/kube-admin/backend/pkg/crypto"
 	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
@@ -45,8 +47,9 @@ func main() {
 		log.Println("Successfully connected to default Kubernetes cluster")
 	}
 
-	// 5. 设置路由（含健康检查）
-	r := router.SetupRouter(defaultK8sClient, k8sManager)
+	// 5. 设置路由（含健康检查）；备份服务的定时调度随服务器启动与关闭
+	backupService := service.NewBackupService(defaultK8sClient, k8sManager)
+	r := router.SetupRouter(defaultK8sClient, k8sManager, backupService)
 
 	// 5.1 单镜像形态：内嵌前端时注册 SPA 托管（-tags embed 构建生效；普通构建 no-op）
 	web.RegisterSPA(r)
@@ -56,9 +59,14 @@ func main() {
 		Addr:         fmt.Sprintf(":%s", cfg.Port),
 		Handler:      r,
 		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 60 * time.Second,
+		WriteTimeout: router.WriteTimeout,
 	}
 
+	// 优雅关闭时主动结束 WebSocket/SSE/agent 隧道等长连接（Shutdown 不等待已劫持的连接）
+	srv.RegisterOnShutdown(api.CloseStreams)
+
+	backupService.Start()
+
 	go func() {
 		log.Printf("Server starting on %s", srv.Addr)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -77,5 +85,7 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	// 停止定时备份，进行中的后台备份与恢复任务被取消并记为失败
+	backupService.Stop(ctx)
 	log.Println("Server exited")
 }
@@ -20,9 +20,25 @@ type Config struct {
 	EncryptKey      string        // 集群凭据加密密钥（任意长度，内部 SHA-256 派生）
 	TLSSkipVerify   bool          // 是否跳过集群 TLS 证书校验（仅开发环境）
 	K8sTimeout      time.Duration // k8s API 单次请求超时（K8S_REQUEST_TIMEOUT 秒，默认 10s，避免集群不可达时挂 30s）
+	FieldManager    string        // server-side apply 的 fieldManager 前缀（FIELD_MANAGER，默认 kube-admin），实际为 <前缀>:<用户名>
+	HelmChartDir    string        // 本地 Helm chart 仓库目录（HELM_CHART_DIR，为空时仅支持上传 chart 包）
+	KustomizeDir    string        // 本地 kustomization 目录（KUSTOMIZE_DIR，为空时仅支持上传压缩包）
+	BackupDir       string        // 命名空间备份的本地存储目录（BACKUP_DIR），配置了 BACKUP_S3_BUCKET 时改用 S3
+	BackupS3        S3Config      // 备份的 S3 兼容存储（BACKUP_S3_*）
 	GinMode         string        // gin 运行模式: debug/release/test
 }
 
+// S3Config S3 兼容对象存储（AWS S3、MinIO、OSS 等）
+type S3Config struct {
+	Endpoint  string // BACKUP_S3_ENDPOINT，如 s3.amazonaws.com、minio.example.com:9000
+	Bucket    string // BACKUP_S3_BUCKET，为空表示不使用 S3
+	Region    string // BACKUP_S3_REGION
+	AccessKey string // BACKUP_S3_ACCESS_KEY
+	SecretKey string // BACKUP_S3_SECRET_KEY
+	Prefix    string // BACKUP_S3_PREFIX，对象键前缀
+	Insecure  bool   // BACKUP_S3_INSECURE=true 时使用 HTTP
+}
+
 // App 全局配置单例，供不便通过依赖注入获取配置的包使用
 var App *Config
 
@@ -38,6 +54,19 @@ func LoadConfig() *Config {
 		EncryptKey:     getEnv("ENCRYPT_KEY", ""),
 		TLSSkipVerify:  getEnv("TLS_SKIP_VERIFY", "false") == "true",
 		K8sTimeout:     k8sTimeoutFromEnv(),
+		FieldManager:   getEnv("FIELD_MANAGER", "kube-admin"),
+		HelmChartDir:   getEnv("HELM_CHART_DIR", ""),
+		KustomizeDir:   getEnv("KUSTOMIZE_DIR", ""),
+		BackupDir:      getEnv("BACKUP_DIR", ""),
+		BackupS3: S3Config{
+			Endpoint:  getEnv("BACKUP_S3_ENDPOINT", "s3.amazonaws.com"),
+			Bucket:    getEnv("BACKUP_S3_BUCKET", ""),
+			Region:    getEnv("BACKUP_S3_REGION", ""),
+			AccessKey: getEnv("BACKUP_S3_ACCESS_KEY", ""),
+			SecretKey: getEnv("BACKUP_S3_SECRET_KEY", ""),
+			Prefix:    getEnv("BACKUP_S3_PREFIX", ""),
+			Insecure:  getEnv("BACKUP_S3_INSECURE", "false") == "true",
+		},
 		GinMode:        getEnv("GIN_MODE", "debug"),
 	}
 
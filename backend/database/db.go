@@ -27,7 +27,7 @@ func InitDB(driver, dsn, sqlitePath string) {
 	}
 
 	// 自动迁移数据库模型（模型层无数据库专属语法，跨库通用）
-	if err = DB.AutoMigrate(&model.User{}, &model.Cluster{}, &model.AuditLog{}); err != nil {
+	if err = DB.AutoMigrate(&model.User{}, &model.Cluster{}, &model.AuditLog{}, &model.ClusterJoinToken{}, &model.BackupSchedule{}, &model.BackupJob{}); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	"github.com/kube-admin/kube-admin/backend/pkg/logger"
+	"github.com/kube-admin/kube-admin/backend/pkg/tunnel"
+)
+
+// AgentAPI agent 接入API：join token 签发、agent 注册与反向隧道连接
+type AgentAPI struct {
+	agentService *service.AgentService
+	k8sManager   *k8s.Manager
+}
+
+// NewAgentAPI 创建 agent API 实例
+func NewAgentAPI(agentService *service.AgentService, k8sManager *k8s.Manager) *AgentAPI {
+	return &AgentAPI{agentService: agentService, k8sManager: k8sManager}
+}
+
+// CreateJoinToken 为 agent 模式集群签发一次性 join token（admin）
+func (a *AgentAPI) CreateJoinToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "Invalid cluster ID"))
+		return
+	}
+	var req model.JoinTokenRequest
+	// 请求体可选：空 body 使用默认有效期
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+			return
+		}
+	}
+
+	resp, err := a.agentService.CreateJoinToken(uint(id), req.TTLHours, c.GetString("username"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(resp))
+}
+
+// GetAgentStatus 查询集群 agent 隧道在线状态（admin）
+func (a *AgentAPI) GetAgentStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "Invalid cluster ID"))
+		return
+	}
+	status := model.AgentStatus{ClusterID: uint(id)}
+	connected, since, remote := a.k8sManager.TunnelStatus(uint(id))
+	if connected {
+		status.Connected = true
+		status.ConnectedAt = &since
+		status.RemoteAddr = remote
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(status))
+}
+
+// Register agent 凭一次性 join token 换取长期凭据（公开路由，token 即鉴权）
+func (a *AgentAPI) Register(c *gin.Context) {
+	var req model.AgentRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	resp, err := a.agentService.Register(req.Token, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse(401, err.Error()))
+		return
+	}
+	// 旧凭据已被覆盖，断开仍以旧凭据建立的隧道
+	a.k8sManager.CloseTunnel(resp.ClusterID)
+	a.k8sManager.RemoveClient(resp.ClusterID)
+	c.JSON(http.StatusOK, model.SuccessResponse(resp))
+}
+
+// Connect agent 建立反向隧道（WebSocket，公开路由，凭 agent 长期凭据鉴权）。
+// 握手响应头下发会话令牌，之后 WebSocket 上承载 yamux 会话直至断开。
+func (a *AgentAPI) Connect(c *gin.Context) {
+	credential := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	cluster, err := a.agentService.Authenticate(credential)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse(401, err.Error()))
+		return
+	}
+
+	sessionToken, err := a.agentService.NewSessionToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true }, // agent 非浏览器，无 Origin
+	}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, http.Header{tunnel.SessionTokenHeader: []string{sessionToken}})
+	if err != nil {
+		return
+	}
+
+	session, err := tunnel.NewServerSession(ws)
+	if err != nil {
+		ws.Close()
+		return
+	}
+	if err := a.k8sManager.RegisterTunnel(cluster.ID, session, sessionToken, c.ClientIP()); err != nil {
+		logger.Error("register agent tunnel for cluster %d: %v", cluster.ID, err)
+		session.Close()
+		return
+	}
+
+	// 阻塞至会话断开或服务关闭，隧道生命周期与本请求一致
+	ctx, cancel := streamContext(c)
+	defer cancel()
+	select {
+	case <-session.CloseChan():
+	case <-ctx.Done():
+		session.Close()
+	}
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/middleware"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+// BackupAPI 命名空间备份与恢复 API（仅 admin）
+type BackupAPI struct {
+	backupService *service.BackupService
+}
+
+// NewBackupAPI 创建备份 API
+func NewBackupAPI(backupService *service.BackupService) *BackupAPI {
+	return &BackupAPI{backupService: backupService}
+}
+
+// queryClusterID 解析 cluster_id 查询参数，缺省为默认集群（0）；非法时直接写 400 响应
+func queryClusterID(c *gin.Context) (uint, bool) {
+	v := c.Query("cluster_id")
+	if v == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的集群ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// paramScheduleID 解析路径中的计划 ID；非法时直接写 400 响应
+func paramScheduleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的计划ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// backupError 按错误类型写响应：未配置存储 501、备份或计划不存在 404，其余 500
+func backupError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrBackupStorageDisabled):
+		code = http.StatusNotImplemented
+	case service.IsBackupNotFound(err), errors.Is(err, gorm.ErrRecordNotFound):
+		code = http.StatusNotFound
+	}
+	c.JSON(code, model.ErrorResponse(code, err.Error()))
+}
+
+// ListBackups 列出备份（?cluster_id=&namespace=&schedule= 过滤），按创建时间倒序
+func (a *BackupAPI) ListBackups(c *gin.Context) {
+	var q model.BackupQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	backups, err := a.backupService.ListBackups(c.Request.Context(), q)
+	if err != nil {
+		backupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(backups))
+}
+
+// CreateBackup 在后台备份 ?cluster_id= 集群中的命名空间，返回 202 与任务
+func (a *BackupAPI) CreateBackup(c *gin.Context) {
+	clusterID, ok := queryClusterID(c)
+	if !ok {
+		return
+	}
+	var req model.BackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	job, err := a.backupService.StartBackup(clusterID, req, c.GetString("username"))
+	if err != nil {
+		backupError(c, err)
+		return
+	}
+	detail := "job " + job.ID
+	if req.IncludeSecrets {
+		detail += ", include secrets"
+	}
+	middleware.SetAuditDetail(c, detail)
+	c.JSON(http.StatusAccepted, model.SuccessResponse(job))
+}
+
+// GetBackup 获取备份元数据
+func (a *BackupAPI) GetBackup(c *gin.Context) {
+	backup, err := a.backupService.GetBackup(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		backupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(backup))
+}
+
+// DownloadBackup 下载备份归档（tar.gz），从存储流式转发
+func (a *BackupAPI) DownloadBackup(c *gin.Context) {
+	name := c.Param("name")
+	backup, archive, err := a.backupService.OpenBackup(c.Request.Context(), name)
+	if err != nil {
+		backupError(c, err)
+		return
+	}
+	defer archive.Close()
+	c.DataFromReader(http.StatusOK, backup.Size, "application/gzip", archive, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s.tar.gz"`, name),
+	})
+}
+
+// DeleteBackup 删除备份
+func (a *BackupAPI) DeleteBackup(c *gin.Context) {
+	if err := a.backupService.DeleteBackup(c.Request.Context(), c.Param("name")); err != nil {
+		backupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"message": "deleted"}))
+}
+
+// RestoreBackup 在后台将备份恢复到 ?cluster_id= 集群，返回 202 与任务，任务结果为逐个对象的结果（?dry_run=true 或请求体 dry_run 试运行）
+func (a *BackupAPI) RestoreBackup(c *gin.Context) {
+	clusterID, ok := queryClusterID(c)
+	if !ok {
+		return
+	}
+	var opts model.RestoreOptions
+	if err := c.ShouldBindJSON(&opts); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	opts.DryRun = opts.DryRun || c.Query("dry_run") == "true"
+	detail := "dry run"
+	if !opts.DryRun {
+		policy := opts.ConflictPolicy
+		if policy == "" {
+			policy = model.ConflictSkip
+		}
+		detail = "conflict policy " + policy
+		var mapping []string
+		for from, to := range opts.NamespaceMapping {
+			mapping = append(mapping, from+"->"+to)
+		}
+		if len(mapping) > 0 {
+			detail += ", namespaces " + strings.Join(mapping, ",")
+		}
+	}
+
+	username := c.GetString("username")
+	job, err := a.backupService.StartRestore(c.Request.Context(), c.Param("name"), clusterID, opts, service.FieldManagerFor(username), username)
+	if err != nil {
+		if errors.Is(err, service.ErrBackupStorageDisabled) || service.IsBackupNotFound(err) {
+			backupError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	middleware.SetAuditDetail(c, detail+", job "+job.ID)
+	c.JSON(http.StatusAccepted, model.SuccessResponse(job))
+}
+
+// ListSchedules 列出定时备份计划
+func (a *BackupAPI) ListSchedules(c *gin.Context) {
+	schedules, err := a.backupService.ListSchedules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(schedules))
+}
+
+// CreateSchedule 创建定时备份计划
+func (a *BackupAPI) CreateSchedule(c *gin.Context) {
+	var req model.BackupScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	schedule, err := a.backupService.CreateSchedule(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(schedule))
+}
+
+// UpdateSchedule 修改定时备份计划（name 不可修改）
+func (a *BackupAPI) UpdateSchedule(c *gin.Context) {
+	id, ok := paramScheduleID(c)
+	if !ok {
+		return
+	}
+	var req model.BackupScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	schedule, err := a.backupService.UpdateSchedule(id, req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			backupError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(schedule))
+}
+
+// DeleteSchedule 删除定时备份计划，已生成的备份保留
+func (a *BackupAPI) DeleteSchedule(c *gin.Context) {
+	id, ok := paramScheduleID(c)
+	if !ok {
+		return
+	}
+	if err := a.backupService.DeleteSchedule(id); err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"message": "deleted"}))
+}
+
+// RunSchedule 在后台立即按计划执行一次备份，返回 202 与任务
+func (a *BackupAPI) RunSchedule(c *gin.Context) {
+	id, ok := paramScheduleID(c)
+	if !ok {
+		return
+	}
+	job, err := a.backupService.StartSchedule(id, c.GetString("username"))
+	if err != nil {
+		backupError(c, err)
+		return
+	}
+	middleware.SetAuditDetail(c, "job "+job.ID)
+	c.JSON(http.StatusAccepted, model.SuccessResponse(job))
+}
+
+// ListJobs 最近的备份与恢复任务（不含结果详情）
+func (a *BackupAPI) ListJobs(c *gin.Context) {
+	jobs, err := a.backupService.ListJobs(100)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(jobs))
+}
+
+// GetJob 查询备份或恢复任务的状态与结果
+func (a *BackupAPI) GetJob(c *gin.Context) {
+	job, err := a.backupService.GetJob(c.Param("id"))
+	if err != nil {
+		backupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(job))
+}
@@ -7,16 +7,18 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/kube-admin/kube-admin/backend/internal/model"
 	"github.com/kube-admin/kube-admin/backend/internal/service"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
 )
 
 // ClusterAPI 集群API控制器
 type ClusterAPI struct {
 	clusterService *service.ClusterService
+	k8sManager     *k8s.Manager
 }
 
 // NewClusterAPI 创建集群API实例
-func NewClusterAPI(clusterService *service.ClusterService) *ClusterAPI {
-	return &ClusterAPI{clusterService: clusterService}
+func NewClusterAPI(clusterService *service.ClusterService, k8sManager *k8s.Manager) *ClusterAPI {
+	return &ClusterAPI{clusterService: clusterService, k8sManager: k8sManager}
 }
 
 // ListClusters 获取集群列表
@@ -63,8 +65,8 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 	}
 
 	// 验证至少提供了一种连接方式
-	if req.ConfigContent == "" && req.ConfigPath == "" && (req.ServerURL == "" || req.Token == "") {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "必须提供至少一种连接方式：1. kubeconfig内容 2. kubeconfig文件路径 3. 服务器地址和Token"))
+	if !req.HasCredential() {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "必须提供至少一种连接方式：1. kubeconfig内容 2. kubeconfig文件路径 3. 服务器地址和Token 4. agent 接入"))
 		return
 	}
 
@@ -106,6 +108,11 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
 		return
 	}
+	// 连接信息可能已变化：改为直连时断开 agent 隧道，并丢弃按旧配置缓存的客户端
+	if cluster.ConnectionType != model.ConnectionAgent {
+		a.k8sManager.CloseTunnel(uint(id))
+	}
+	a.k8sManager.RemoveClient(uint(id))
 
 	c.JSON(http.StatusOK, model.SuccessResponse(cluster))
 }
@@ -123,6 +130,8 @@ func (a *ClusterAPI) DeleteCluster(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
+	a.k8sManager.CloseTunnel(uint(id))
+	a.k8sManager.RemoveClient(uint(id))
 
 	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"message": "Cluster deleted successfully"}))
 }
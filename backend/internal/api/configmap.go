@@ -30,13 +30,17 @@ func (a *ConfigMapAPI) ListConfigMaps(c *gin.Context) {
 
 	namespace := c.DefaultQuery("namespace", "default")
 
-	configMaps, err := configMapService.(*service.ConfigMapService).ListConfigMaps(namespace)
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	configMaps, err := configMapService.(*service.ConfigMapService).ListConfigMaps(c.Request.Context(), namespace, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(configMaps))
+	respondList(c, q, configMaps)
 }
 
 // GetConfigMap 获取ConfigMap详情
@@ -51,7 +55,7 @@ func (a *ConfigMapAPI) GetConfigMap(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	configMap, err := configMapService.(*service.ConfigMapService).GetConfigMap(namespace, name)
+	configMap, err := configMapService.(*service.ConfigMapService).GetConfigMap(c.Request.Context(), namespace, name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -80,7 +84,7 @@ func (a *ConfigMapAPI) CreateConfigMap(c *gin.Context) {
 		return
 	}
 
-	err := configMapService.(*service.ConfigMapService).CreateConfigMap(req.Namespace, req.Name, req.Data)
+	err := configMapService.(*service.ConfigMapService).CreateConfigMap(c.Request.Context(), req.Namespace, req.Name, req.Data)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -110,7 +114,7 @@ func (a *ConfigMapAPI) UpdateConfigMap(c *gin.Context) {
 		return
 	}
 
-	err := configMapService.(*service.ConfigMapService).UpdateConfigMap(namespace, name, req.Data)
+	err := configMapService.(*service.ConfigMapService).UpdateConfigMap(c.Request.Context(), namespace, name, req.Data, dryRunRequested(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -131,11 +135,12 @@ func (a *ConfigMapAPI) DeleteConfigMap(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	err := configMapService.(*service.ConfigMapService).DeleteConfigMap(namespace, name)
+	dryRun := dryRunRequested(c)
+	err := configMapService.(*service.ConfigMapService).DeleteConfigMap(c.Request.Context(), namespace, name, dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
 }
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/middleware"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// CronJobAPI CronJob API
+type CronJobAPI struct {
+	// 注意：在多集群环境中，服务实例将在中间件中动态注入
+	cronJobService *service.CronJobService
+}
+
+// NewCronJobAPI 创建CronJob API
+func NewCronJobAPI(cronJobService *service.CronJobService) *CronJobAPI {
+	return &CronJobAPI{cronJobService: cronJobService}
+}
+
+// ListCronJobs 获取CronJob列表
+func (a *CronJobAPI) ListCronJobs(c *gin.Context) {
+	// 从上下文中获取服务实例
+	cronJobService, exists := c.Get("cronjob_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.DefaultQuery("namespace", "default")
+
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	cronJobs, err := cronJobService.(*service.CronJobService).ListCronJobs(c.Request.Context(), namespace, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	respondList(c, q, cronJobs)
+}
+
+// GetCronJob 获取CronJob详情
+func (a *CronJobAPI) GetCronJob(c *gin.Context) {
+	// 从上下文中获取服务实例
+	cronJobService, exists := c.Get("cronjob_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	cronJob, err := cronJobService.(*service.CronJobService).GetCronJob(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(cronJob))
+}
+
+// DeleteCronJob 删除CronJob
+func (a *CronJobAPI) DeleteCronJob(c *gin.Context) {
+	// 从上下文中获取服务实例
+	cronJobService, exists := c.Get("cronjob_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	dryRun := dryRunRequested(c)
+	err := cronJobService.(*service.CronJobService).DeleteCronJob(c.Request.Context(), namespace, name, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
+}
+
+// TriggerCronJob 立即按 jobTemplate 创建一个 Job
+func (a *CronJobAPI) TriggerCronJob(c *gin.Context) {
+	// 从上下文中获取服务实例
+	cronJobService, exists := c.Get("cronjob_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	created, err := cronJobService.(*service.CronJobService).TriggerCronJob(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	middleware.SetAuditDetail(c, fmt.Sprintf("trigger cronjob %s/%s: created job %s", namespace, name, created))
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"job": created}))
+}
+
+// SuspendCronJob 暂停CronJob调度
+func (a *CronJobAPI) SuspendCronJob(c *gin.Context) {
+	a.setSuspended(c, true)
+}
+
+// ResumeCronJob 恢复CronJob调度
+func (a *CronJobAPI) ResumeCronJob(c *gin.Context) {
+	a.setSuspended(c, false)
+}
+
+// setSuspended 暂停/恢复的公共处理
+func (a *CronJobAPI) setSuspended(c *gin.Context, suspend bool) {
+	// 从上下文中获取服务实例
+	cronJobService, exists := c.Get("cronjob_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	if err := cronJobService.(*service.CronJobService).SetSuspended(c.Request.Context(), namespace, name, suspend); err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+}
+
+// CreateCronJobFromYaml 通过YAML创建CronJob
+func (a *CronJobAPI) CreateCronJobFromYaml(c *gin.Context) {
+	// 从上下文中获取服务实例
+	cronJobService, exists := c.Get("cronjob_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	createFromYAML(c, cronJobService.(*service.CronJobService).GetK8sClient())
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// DaemonSetAPI DaemonSet API
+type DaemonSetAPI struct {
+	// 注意：在多集群环境中，服务实例将在中间件中动态注入
+	daemonSetService *service.DaemonSetService
+}
+
+// NewDaemonSetAPI 创建DaemonSet API
+func NewDaemonSetAPI(daemonSetService *service.DaemonSetService) *DaemonSetAPI {
+	return &DaemonSetAPI{daemonSetService: daemonSetService}
+}
+
+// ListDaemonSets 获取DaemonSet列表
+func (a *DaemonSetAPI) ListDaemonSets(c *gin.Context) {
+	// 从上下文中获取服务实例
+	daemonSetService, exists := c.Get("daemonset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.DefaultQuery("namespace", "default")
+
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	daemonSets, err := daemonSetService.(*service.DaemonSetService).ListDaemonSets(c.Request.Context(), namespace, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	respondList(c, q, daemonSets)
+}
+
+// GetDaemonSet 获取DaemonSet详情
+func (a *DaemonSetAPI) GetDaemonSet(c *gin.Context) {
+	// 从上下文中获取服务实例
+	daemonSetService, exists := c.Get("daemonset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	daemonSet, err := daemonSetService.(*service.DaemonSetService).GetDaemonSet(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(daemonSet))
+}
+
+// DeleteDaemonSet 删除DaemonSet
+func (a *DaemonSetAPI) DeleteDaemonSet(c *gin.Context) {
+	// 从上下文中获取服务实例
+	daemonSetService, exists := c.Get("daemonset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	dryRun := dryRunRequested(c)
+	err := daemonSetService.(*service.DaemonSetService).DeleteDaemonSet(c.Request.Context(), namespace, name, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
+}
+
+// RestartDaemonSet 重启DaemonSet
+func (a *DaemonSetAPI) RestartDaemonSet(c *gin.Context) {
+	// 从上下文中获取服务实例
+	daemonSetService, exists := c.Get("daemonset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	err := daemonSetService.(*service.DaemonSetService).RestartDaemonSet(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+}
+
+// CreateDaemonSetFromYaml 通过YAML创建DaemonSet
+func (a *DaemonSetAPI) CreateDaemonSetFromYaml(c *gin.Context) {
+	// 从上下文中获取服务实例
+	daemonSetService, exists := c.Get("daemonset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	createFromYAML(c, daemonSetService.(*service.DaemonSetService).GetK8sClient())
+}
@@ -28,7 +28,7 @@ func (a *DashboardAPI) GetStats(c *gin.Context) {
 		return
 	}
 
-	stats, err := dashboardService.(*service.DashboardService).GetDashboardStats()
+	stats, err := dashboardService.(*service.DashboardService).GetDashboardStats(c.Request.Context(), c.Query("fresh") == "true")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -1,23 +1,21 @@
 package api
 
 import (
-	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/middleware"
 	"github.com/kube-admin/kube-admin/backend/internal/model"
 	"github.com/kube-admin/kube-admin/backend/internal/service"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 // DeploymentAPI Deployment API
@@ -42,13 +40,17 @@ func (a *DeploymentAPI) ListDeployments(c *gin.Context) {
 
 	namespace := c.DefaultQuery("namespace", "default")
 
-	deployments, err := deploymentService.(*service.DeploymentService).ListDeployments(namespace)
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	deployments, err := deploymentService.(*service.DeploymentService).ListDeployments(c.Request.Context(), namespace, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(deployments))
+	respondList(c, q, deployments)
 }
 
 // GetDeployment 获取Deployment详情
@@ -63,7 +65,7 @@ func (a *DeploymentAPI) GetDeployment(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	deployment, err := deploymentService.(*service.DeploymentService).GetDeployment(namespace, name)
+	deployment, err := deploymentService.(*service.DeploymentService).GetDeployment(c.Request.Context(), namespace, name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -84,13 +86,14 @@ func (a *DeploymentAPI) DeleteDeployment(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	err := deploymentService.(*service.DeploymentService).DeleteDeployment(namespace, name)
+	dryRun := dryRunRequested(c)
+	err := deploymentService.(*service.DeploymentService).DeleteDeployment(c.Request.Context(), namespace, name, dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
 }
 
 // ScaleDeployment 扩缩容Deployment
@@ -112,7 +115,25 @@ func (a *DeploymentAPI) ScaleDeployment(c *gin.Context) {
 		return
 	}
 
-	err = deploymentService.(*service.DeploymentService).ScaleDeployment(namespace, name, int32(replicas))
+	// 副本数由 HPA 管理时需 force=true 才执行
+	force := c.Query("force") == "true"
+	warning, err := deploymentService.(*service.DeploymentService).ScaleDeployment(c.Request.Context(), namespace, name, int32(replicas), force, dryRunRequested(c))
+	respondScale(c, warning, err)
+}
+
+// RestartDeployment 重启Deployment
+func (a *DeploymentAPI) RestartDeployment(c *gin.Context) {
+	// 从上下文中获取服务实例
+	deploymentService, exists := c.Get("deployment_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	err := deploymentService.(*service.DeploymentService).RestartDeployment(c.Request.Context(), namespace, name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -121,8 +142,8 @@ func (a *DeploymentAPI) ScaleDeployment(c *gin.Context) {
 	c.JSON(http.StatusOK, model.SuccessResponse(nil))
 }
 
-// RestartDeployment 重启Deployment
-func (a *DeploymentAPI) RestartDeployment(c *gin.Context) {
+// ListRevisions 获取Deployment历史版本
+func (a *DeploymentAPI) ListRevisions(c *gin.Context) {
 	// 从上下文中获取服务实例
 	deploymentService, exists := c.Get("deployment_service")
 	if !exists {
@@ -133,13 +154,84 @@ func (a *DeploymentAPI) RestartDeployment(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	err := deploymentService.(*service.DeploymentService).RestartDeployment(namespace, name)
+	revisions, err := deploymentService.(*service.DeploymentService).ListRevisions(c.Request.Context(), namespace, name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+	c.JSON(http.StatusOK, model.SuccessResponse(revisions))
+}
+
+// DiffRevisions 比较两个版本的Pod模板（from/to 缺省为上一版本与当前版本）
+func (a *DeploymentAPI) DiffRevisions(c *gin.Context) {
+	// 从上下文中获取服务实例
+	deploymentService, exists := c.Get("deployment_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	var from, to int64
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = strconv.ParseInt(v, 10, 64); err != nil || from < 0 {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "from参数错误"))
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = strconv.ParseInt(v, 10, 64); err != nil || to < 0 {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "to参数错误"))
+			return
+		}
+	}
+
+	diff, err := deploymentService.(*service.DeploymentService).DiffRevisions(c.Request.Context(), namespace, name, from, to)
+	if err != nil {
+		// 对象或版本不存在 404，没有更早的版本 400
+		code := k8sErrorCode(err, http.StatusInternalServerError)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(diff))
+}
+
+// RollbackDeployment 回滚Deployment到指定版本（revision 为 0 或缺省时回滚到上一版本）
+func (a *DeploymentAPI) RollbackDeployment(c *gin.Context) {
+	// 从上下文中获取服务实例
+	deploymentService, exists := c.Get("deployment_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	var req struct {
+		Revision int64 `json:"revision" binding:"min=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的请求参数"))
+		return
+	}
+
+	result, err := deploymentService.(*service.DeploymentService).RollbackDeployment(c.Request.Context(), namespace, name, req.Revision)
+	if err != nil {
+		// 对象或版本不存在 404，暂停中或没有可回滚的版本 400，并发修改冲突 409
+		code := k8sErrorCode(err, http.StatusInternalServerError)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	middleware.SetAuditDetail(c, fmt.Sprintf("rollback deployment %s/%s: revision %d -> %d (skipped=%t)",
+		namespace, name, result.FromRevision, result.ToRevision, result.Skipped))
+	c.JSON(http.StatusOK, model.SuccessResponse(result))
 }
 
 // CreateDeploymentFromYaml 通过YAML创建Deployment
@@ -167,25 +259,8 @@ func (a *DeploymentAPI) CreateDeploymentFromYaml(c *gin.Context) {
 
 	ds := deploymentService.(*service.DeploymentService)
 
-	// 创建dynamic client
-	dynamicClient, err := dynamic.NewForConfig(ds.GetK8sClient().Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建Dynamic Client失败: "+err.Error()))
-		return
-	}
-
-	// 创建discovery client
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(ds.GetK8sClient().Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建Discovery Client失败: "+err.Error()))
-		return
-	}
-
-	// 创建cached discovery client
-	cachedDiscoveryClient := memory.NewMemCacheClient(discoveryClient)
-
-	// 创建rest mapper
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscoveryClient)
+	// 复用集群共享的 dynamic client 与 RESTMapper
+	k8sClient := ds.GetK8sClient()
 
 	// 解析YAML
 	decode := yamlutil.NewYAMLOrJSONDecoder(strings.NewReader(req.YAML), 4096)
@@ -208,7 +283,7 @@ func (a *DeploymentAPI) CreateDeploymentFromYaml(c *gin.Context) {
 		gvk := obj.GroupVersionKind()
 
 		// 获取mapping
-		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		mapping, err := k8sClient.RESTMapping(gvk.GroupKind(), gvk.Version)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "获取REST Mapping失败: "+err.Error()))
 			return
@@ -223,12 +298,12 @@ func (a *DeploymentAPI) CreateDeploymentFromYaml(c *gin.Context) {
 		// 创建资源
 		var dr dynamic.ResourceInterface
 		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-			dr = dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource).Namespace(namespace)
 		} else {
-			dr = dynamicClient.Resource(mapping.Resource)
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource)
 		}
 
-		_, err = dr.Create(context.TODO(), obj, metav1.CreateOptions{})
+		_, err = dr.Create(c.Request.Context(), obj, metav1.CreateOptions{})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建资源失败: "+err.Error()))
 			return
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// k8sErrorCode 按 K8s API 错误类型映射 HTTP 状态码：NotFound 404、Conflict/AlreadyExists 409、Forbidden 403、
+// Invalid/BadRequest 400，其余 apiserver 错误与连接失败、超时 500；其他错误（如参数校验）返回 fallback
+func k8sErrorCode(err error, fallback int) int {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return http.StatusInternalServerError
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return fallback
+	}
+	switch {
+	case apierrors.IsNotFound(err):
+		return http.StatusNotFound
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return http.StatusConflict
+	case apierrors.IsForbidden(err):
+		return http.StatusForbidden
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -40,11 +40,15 @@ func (a *EventAPI) ListEvents(c *gin.Context) {
 		fieldSelector = strings.Join(parts, ",")
 	}
 
-	events, err := eventService.(*service.EventService).ListEvents(namespace, fieldSelector)
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	events, err := eventService.(*service.EventService).ListEvents(c.Request.Context(), namespace, fieldSelector, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(events))
+	respondList(c, q, events)
 }
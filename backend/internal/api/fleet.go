@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// FleetAPI 多集群总览API
+type FleetAPI struct {
+	fleetService *service.FleetService
+}
+
+// NewFleetAPI 创建多集群总览API实例
+func NewFleetAPI(fleetService *service.FleetService) *FleetAPI {
+	return &FleetAPI{fleetService: fleetService}
+}
+
+// GetOverview 所有集群的并发统计与合计（不可达集群单独标注）
+func (a *FleetAPI) GetOverview(c *gin.Context) {
+	overview, err := a.fleetService.GetOverview(c.Request.Context(), c.Query("fresh") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(overview))
+}
+
+// Search 跨集群资源搜索：q 为名称前缀，label_selector 为标签选择器，image 为镜像子串，
+// kinds 为逗号分隔的 resource[.group]（默认 pods,deployments,services,configmaps）
+func (a *FleetAPI) Search(c *gin.Context) {
+	sq := service.SearchQuery{
+		Name:          strings.TrimSpace(c.Query("q")),
+		LabelSelector: c.Query("label_selector"),
+		Image:         strings.TrimSpace(c.Query("image")),
+		Fresh:         c.Query("fresh") == "true",
+	}
+	for _, kind := range strings.Split(c.Query("kinds"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			sq.Kinds = append(sq.Kinds, kind)
+		}
+	}
+	result, err := a.fleetService.Search(c.Request.Context(), sq)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(result))
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// GraphAPI 资源关系图API
+type GraphAPI struct {
+	// 注意：在多集群环境中，服务实例将在中间件中动态注入
+	graphService *service.GraphService
+}
+
+// NewGraphAPI 创建资源关系图API
+func NewGraphAPI(graphService *service.GraphService) *GraphAPI {
+	return &GraphAPI{graphService: graphService}
+}
+
+// GetGraph 获取命名空间的资源关系图（?kind=deployments&name=web 时只取该资源相关的子图）
+func (a *GraphAPI) GetGraph(c *gin.Context) {
+	// 从上下文中获取服务实例
+	graphService, exists := c.Get("graph_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "namespace参数必填"))
+		return
+	}
+
+	graph, err := graphService.(*service.GraphService).GetGraph(c.Request.Context(), namespace, c.Query("kind"), c.Query("name"))
+	if err != nil {
+		// 中心资源不存在 404
+		code := k8sErrorCode(err, http.StatusInternalServerError)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(graph))
+}
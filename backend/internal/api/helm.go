@@ -0,0 +1,260 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/kube-admin/kube-admin/backend/internal/middleware"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// HelmAPI Helm release API
+type HelmAPI struct {
+	// 注意：在多集群环境中，服务实例将在中间件中动态注入
+	helmService *service.HelmService
+}
+
+// NewHelmAPI 创建 Helm API
+func NewHelmAPI(helmService *service.HelmService) *HelmAPI {
+	return &HelmAPI{helmService: helmService}
+}
+
+// releaseNamespace release 所在命名空间，未指定时同 helm CLI 使用 default
+func releaseNamespace(c *gin.Context) string {
+	if ns := c.Query("namespace"); ns != "" {
+		return ns
+	}
+	return "default"
+}
+
+// queryRevision 解析版本号查询参数，缺省为 0；非法时直接写 400 响应
+func queryRevision(c *gin.Context, key string) (int, bool) {
+	v := c.Query(key)
+	if v == "" {
+		return 0, true
+	}
+	revision, err := strconv.Atoi(v)
+	if err != nil || revision < 0 {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, key+" 参数无效"))
+		return 0, false
+	}
+	return revision, true
+}
+
+// ListReleases 列出 release（namespace 为空表示全部命名空间，?search= 按名称过滤）
+func (a *HelmAPI) ListReleases(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	releases, err := helmService.(*service.HelmService).ListReleases(c.Query("namespace"), c.Query("search"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(releases))
+}
+
+// GetRelease 获取 release 指定版本（?revision=，缺省最新）的清单与 NOTES
+func (a *HelmAPI) GetRelease(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	revision, ok := queryRevision(c, "revision")
+	if !ok {
+		return
+	}
+
+	detail, err := helmService.(*service.HelmService).GetRelease(releaseNamespace(c), c.Param("name"), revision)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(detail))
+}
+
+// GetHistory 获取 release 的版本历史
+func (a *HelmAPI) GetHistory(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	history, err := helmService.(*service.HelmService).GetHistory(releaseNamespace(c), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(history))
+}
+
+// GetValues 获取 release 的 values（?revision=，?all=true 时包含 chart 默认值）
+func (a *HelmAPI) GetValues(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	revision, ok := queryRevision(c, "revision")
+	if !ok {
+		return
+	}
+
+	values, err := helmService.(*service.HelmService).GetValues(releaseNamespace(c), c.Param("name"), revision, c.Query("all") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(values))
+}
+
+// DiffRevisions 比较两个版本（?from=&to=，缺省为最新版本与其上一版本）
+func (a *HelmAPI) DiffRevisions(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	from, ok := queryRevision(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := queryRevision(c, "to")
+	if !ok {
+		return
+	}
+
+	diff, err := helmService.(*service.HelmService).DiffRevisions(releaseNamespace(c), c.Param("name"), from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(diff))
+}
+
+// Rollback 回滚 release（?revision=，缺省上一版本）
+func (a *HelmAPI) Rollback(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	revision, ok := queryRevision(c, "revision")
+	if !ok {
+		return
+	}
+
+	rel, err := helmService.(*service.HelmService).Rollback(releaseNamespace(c), c.Param("name"), revision, dryRunRequested(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(rel))
+}
+
+// Uninstall 卸载 release（?keep_history=true 保留版本记录）
+func (a *HelmAPI) Uninstall(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	info, err := helmService.(*service.HelmService).Uninstall(releaseNamespace(c), c.Param("name"), c.Query("keep_history") == "true", dryRunRequested(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"info": info}))
+}
+
+// InstallOrUpgrade 安装或升级 release（multipart 表单）：chart 来自上传的 chart_file（.tgz）
+// 或本地 chart 仓库中的 chart 参数
+func (a *HelmAPI) InstallOrUpgrade(c *gin.Context) {
+	helmService, exists := c.Get("helm_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxChartSize)
+	var opts model.HelmInstallOptions
+	if err := c.ShouldBindWith(&opts, binding.Form); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+
+	chrt, err := loadRequestChart(c, opts)
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, service.ErrChartDirDisabled) {
+			code = http.StatusNotImplemented
+		}
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	opts.DryRun = opts.DryRun || c.Query("dry_run") == "true"
+	if opts.DryRun {
+		middleware.SetAuditDetail(c, "dry run")
+	} else {
+		middleware.SetAuditDetail(c, fmt.Sprintf("chart %s %s", chrt.Metadata.Name, chrt.Metadata.Version))
+	}
+	detail, err := helmService.(*service.HelmService).InstallOrUpgrade(c.Request.Context(), releaseNamespace(c), c.Param("name"), chrt, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(detail))
+}
+
+// loadRequestChart 优先读取上传的 chart_file，未上传时从本地 chart 仓库加载
+func loadRequestChart(c *gin.Context, opts model.HelmInstallOptions) (*chart.Chart, error) {
+	file, err := c.FormFile("chart_file")
+	if errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart) {
+		return service.LoadLocalChart(opts.Chart, opts.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return service.LoadChartArchive(f)
+}
+
+// ListCharts 列出本地 chart 仓库（HELM_CHART_DIR）中可安装的 chart
+func (a *HelmAPI) ListCharts(c *gin.Context) {
+	charts, err := service.ListLocalCharts()
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, service.ErrChartDirDisabled) {
+			code = http.StatusNotImplemented
+		}
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(charts))
+}
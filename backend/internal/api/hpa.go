@@ -0,0 +1,162 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// HPAAPI HorizontalPodAutoscaler API
+type HPAAPI struct {
+	// 注意：在多集群环境中，服务实例将在中间件中动态注入
+	hpaService *service.HPAService
+}
+
+// NewHPAAPI 创建HPA API
+func NewHPAAPI(hpaService *service.HPAService) *HPAAPI {
+	return &HPAAPI{hpaService: hpaService}
+}
+
+// ListHPAs 获取HPA列表（含当前值与目标值）
+func (a *HPAAPI) ListHPAs(c *gin.Context) {
+	// 从上下文中获取服务实例
+	hpaService, exists := c.Get("hpa_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.DefaultQuery("namespace", "default")
+
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	hpas, err := hpaService.(*service.HPAService).ListHPAs(c.Request.Context(), namespace, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	respondList(c, q, hpas)
+}
+
+// GetHPA 获取HPA详情（含扩缩容事件）
+func (a *HPAAPI) GetHPA(c *gin.Context) {
+	// 从上下文中获取服务实例
+	hpaService, exists := c.Get("hpa_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	hpa, err := hpaService.(*service.HPAService).GetHPA(c.Request.Context(), namespace, name)
+	if err != nil {
+		// 不存在 404，apiserver 其他错误 500
+		code := k8sErrorCode(err, http.StatusBadRequest)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(hpa))
+}
+
+// CreateHPA 按表单为 Deployment/StatefulSet 创建HPA
+func (a *HPAAPI) CreateHPA(c *gin.Context) {
+	// 从上下文中获取服务实例
+	hpaService, exists := c.Get("hpa_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.DefaultQuery("namespace", "default")
+
+	var form model.HPAForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的请求参数"))
+		return
+	}
+
+	hpa, err := hpaService.(*service.HPAService).CreateHPA(c.Request.Context(), namespace, form)
+	if err != nil {
+		// 表单校验不通过 400；已存在 409，apiserver 其他错误 500
+		code := k8sErrorCode(err, http.StatusBadRequest)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(hpa))
+}
+
+// UpdateHPA 修改HPA的最小/最大副本数与使用率目标
+func (a *HPAAPI) UpdateHPA(c *gin.Context) {
+	// 从上下文中获取服务实例
+	hpaService, exists := c.Get("hpa_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	var form model.HPAForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的请求参数"))
+		return
+	}
+
+	hpa, err := hpaService.(*service.HPAService).UpdateHPA(c.Request.Context(), namespace, name, form)
+	if err != nil {
+		// 表单校验不通过 400；不存在 404、并发修改冲突 409，apiserver 其他错误 500
+		code := k8sErrorCode(err, http.StatusBadRequest)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(hpa))
+}
+
+// DeleteHPA 删除HPA
+func (a *HPAAPI) DeleteHPA(c *gin.Context) {
+	// 从上下文中获取服务实例
+	hpaService, exists := c.Get("hpa_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	dryRun := dryRunRequested(c)
+	err := hpaService.(*service.HPAService).DeleteHPA(c.Request.Context(), namespace, name, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
+}
+
+// respondScale 扩缩容结果：HPA 管理副本数时返回 409，强制执行时在 data.warning 中给出提示
+func respondScale(c *gin.Context, warning string, err error) {
+	var conflict *service.HPAConflictError
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, model.ErrorResponse(409, err.Error()))
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+	case warning != "":
+		c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"warning": warning}))
+	default:
+		c.JSON(http.StatusOK, model.SuccessResponse(nil))
+	}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/middleware"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// JobAPI Job API
+type JobAPI struct {
+	// 注意：在多集群环境中，服务实例将在中间件中动态注入
+	jobService *service.JobService
+}
+
+// NewJobAPI 创建Job API
+func NewJobAPI(jobService *service.JobService) *JobAPI {
+	return &JobAPI{jobService: jobService}
+}
+
+// ListJobs 获取Job列表
+func (a *JobAPI) ListJobs(c *gin.Context) {
+	// 从上下文中获取服务实例
+	jobService, exists := c.Get("job_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.DefaultQuery("namespace", "default")
+
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	jobs, err := jobService.(*service.JobService).ListJobs(c.Request.Context(), namespace, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	respondList(c, q, jobs)
+}
+
+// GetJob 获取Job详情
+func (a *JobAPI) GetJob(c *gin.Context) {
+	// 从上下文中获取服务实例
+	jobService, exists := c.Get("job_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	job, err := jobService.(*service.JobService).GetJob(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(job))
+}
+
+// DeleteJob 删除Job
+func (a *JobAPI) DeleteJob(c *gin.Context) {
+	// 从上下文中获取服务实例
+	jobService, exists := c.Get("job_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	dryRun := dryRunRequested(c)
+	err := jobService.(*service.JobService).DeleteJob(c.Request.Context(), namespace, name, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
+}
+
+// RetryJob 以失败 Job 的规格重新创建一个 Job
+func (a *JobAPI) RetryJob(c *gin.Context) {
+	// 从上下文中获取服务实例
+	jobService, exists := c.Get("job_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	created, err := jobService.(*service.JobService).RetryJob(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	middleware.SetAuditDetail(c, fmt.Sprintf("retry job %s/%s as %s", namespace, name, created))
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"job": created}))
+}
+
+// CleanupJobs 删除已结束的 Job（?status=complete|failed 缺省为全部，?cronjob= 只清理指定 CronJob 的）
+func (a *JobAPI) CleanupJobs(c *gin.Context) {
+	// 从上下文中获取服务实例
+	jobService, exists := c.Get("job_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "namespace参数必填"))
+		return
+	}
+	cronJob := c.Query("cronjob")
+
+	deleted, err := jobService.(*service.JobService).DeleteFinishedJobs(c.Request.Context(), namespace, c.Query("status"), cronJob)
+	if len(deleted) > 0 {
+		middleware.SetAuditDetail(c, fmt.Sprintf("cleanup jobs in %s (cronjob=%q): %s", namespace, cronJob, strings.Join(deleted, ", ")))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"deleted": deleted}))
+}
+
+// CreateJobFromYaml 通过YAML创建Job
+func (a *JobAPI) CreateJobFromYaml(c *gin.Context) {
+	// 从上下文中获取服务实例
+	jobService, exists := c.Get("job_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	createFromYAML(c, jobService.(*service.JobService).GetK8sClient())
+}
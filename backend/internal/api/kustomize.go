@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/kube-admin/kube-admin/backend/internal/middleware"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// KustomizeAPI kustomize 构建与 apply API
+type KustomizeAPI struct{}
+
+// NewKustomizeAPI 创建 kustomize API
+func NewKustomizeAPI() *KustomizeAPI { return &KustomizeAPI{} }
+
+// kustomizeRequest 表单参数：上传 archive 时 path 为包内目录，否则为 KUSTOMIZE_DIR 内的相对路径
+type kustomizeRequest struct {
+	Path string `form:"path"`
+	model.ApplyOptions
+}
+
+// Build 渲染 kustomization 并返回预览（多文档 YAML 与对象列表），不修改集群
+func (a *KustomizeAPI) Build(c *gin.Context) {
+	var req kustomizeRequest
+	objs, ok := bindKustomize(c, &req)
+	if !ok {
+		return
+	}
+	result, err := service.KustomizeResult(objs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(result))
+}
+
+// Apply 渲染 kustomization 后按依赖顺序 server-side apply，返回逐个对象的结果（同 /resources/apply）
+func (a *KustomizeAPI) Apply(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	var req kustomizeRequest
+	objs, ok := bindKustomize(c, &req)
+	if !ok {
+		return
+	}
+	req.FieldManager = service.FieldManagerFor(c.GetString("username"))
+	switch {
+	case req.DryRun:
+		middleware.SetAuditDetail(c, "dry run")
+	case req.Force:
+		middleware.SetAuditDetail(c, "force apply as "+req.FieldManager)
+	}
+	respondApply(c, rs.(*service.ResourceService).ApplyObjects(c.Request.Context(), objs, req.ApplyOptions))
+}
+
+// bindKustomize 解析表单并执行 kustomize build：优先使用上传的 archive（tar、tar.gz 或 zip），否则构建 KUSTOMIZE_DIR 内的 path。
+// 失败时直接写响应
+func bindKustomize(c *gin.Context, req *kustomizeRequest) ([]*unstructured.Unstructured, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxKustomizeArchiveSize)
+	if err := c.ShouldBindWith(req, binding.Form); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return nil, false
+	}
+
+	var objs []*unstructured.Unstructured
+	file, err := c.FormFile("archive")
+	switch {
+	case errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart):
+		objs, err = service.KustomizeBuildLocal(req.Path)
+	case err == nil:
+		var data []byte
+		if data, err = readFormFile(file); err == nil {
+			objs, err = service.KustomizeBuildArchive(data, req.Path)
+		}
+	}
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, service.ErrKustomizeDirDisabled) {
+			code = http.StatusNotImplemented
+		}
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return nil, false
+	}
+	return objs, true
+}
+
+// readFormFile 读取上传文件的全部内容
+func readFormFile(file *multipart.FileHeader) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// bindListQuery 解析并校验列表查询参数，失败时直接写 400 响应
+func bindListQuery(c *gin.Context) (model.ListQuery, bool) {
+	var q model.ListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return q, false
+	}
+	if _, err := labels.Parse(q.LabelSelector); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "label_selector 无效: "+err.Error()))
+		return q, false
+	}
+	if _, err := fields.ParseSelector(q.FieldSelector); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "field_selector 无效: "+err.Error()))
+		return q, false
+	}
+	return q, true
+}
+
+// respondList 输出列表：请求分页时返回 PageResponse，否则仅返回数组（兼容旧调用方）
+func respondList(c *gin.Context, q model.ListQuery, page *model.PageResponse) {
+	if q.Paged() {
+		c.JSON(http.StatusOK, model.SuccessResponse(page))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(page.Items))
+}
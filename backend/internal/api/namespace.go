@@ -28,13 +28,17 @@ func (a *NamespaceAPI) ListNamespaces(c *gin.Context) {
 		return
 	}
 
-	namespaces, err := namespaceService.(*service.NamespaceService).ListNamespaces()
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	namespaces, err := namespaceService.(*service.NamespaceService).ListNamespaces(c.Request.Context(), q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(namespaces))
+	respondList(c, q, namespaces)
 }
 
 // CreateNamespace 创建Namespace
@@ -55,7 +59,7 @@ func (a *NamespaceAPI) CreateNamespace(c *gin.Context) {
 		return
 	}
 
-	err := namespaceService.(*service.NamespaceService).CreateNamespace(req.Name)
+	err := namespaceService.(*service.NamespaceService).CreateNamespace(c.Request.Context(), req.Name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -75,7 +79,7 @@ func (a *NamespaceAPI) DeleteNamespace(c *gin.Context) {
 
 	name := c.Param("name")
 
-	err := namespaceService.(*service.NamespaceService).DeleteNamespace(name)
+	err := namespaceService.(*service.NamespaceService).DeleteNamespace(c.Request.Context(), name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -92,7 +96,7 @@ func (a *NamespaceAPI) FinalizeNamespace(c *gin.Context) {
 		return
 	}
 	name := c.Param("name")
-	if err := namespaceService.(*service.NamespaceService).FinalizeNamespace(name); err != nil {
+	if err := namespaceService.(*service.NamespaceService).FinalizeNamespace(c.Request.Context(), name); err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
@@ -106,7 +110,7 @@ func (a *NamespaceAPI) ListUnavailableAPIServices(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
 		return
 	}
-	list, err := namespaceService.(*service.NamespaceService).ListUnavailableAPIServices()
+	list, err := namespaceService.(*service.NamespaceService).ListUnavailableAPIServices(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -122,7 +126,7 @@ func (a *NamespaceAPI) DeleteAPIService(c *gin.Context) {
 		return
 	}
 	name := c.Param("name")
-	if err := namespaceService.(*service.NamespaceService).DeleteAPIService(name); err != nil {
+	if err := namespaceService.(*service.NamespaceService).DeleteAPIService(c.Request.Context(), name); err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
@@ -28,13 +28,17 @@ func (a *NodeAPI) ListNodes(c *gin.Context) {
 		return
 	}
 
-	nodes, err := nodeService.(*service.NodeService).ListNodes()
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	nodes, err := nodeService.(*service.NodeService).ListNodes(c.Request.Context(), q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(nodes))
+	respondList(c, q, nodes)
 }
 
 // GetNode 获取Node详情
@@ -48,7 +52,7 @@ func (a *NodeAPI) GetNode(c *gin.Context) {
 
 	name := c.Param("name")
 
-	node, err := nodeService.(*service.NodeService).GetNode(name)
+	node, err := nodeService.(*service.NodeService).GetNode(c.Request.Context(), name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
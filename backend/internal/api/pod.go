@@ -1,7 +1,6 @@
 package api
 
 import (
-	"context"
 	"io"
 	"log"
 	"net/http"
@@ -16,10 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 // PodAPI Pod API
@@ -44,13 +40,17 @@ func (a *PodAPI) ListPods(c *gin.Context) {
 
 	namespace := c.DefaultQuery("namespace", "default")
 
-	pods, err := podService.(*service.PodService).ListPods(namespace)
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	pods, err := podService.(*service.PodService).ListPods(c.Request.Context(), namespace, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(pods))
+	respondList(c, q, pods)
 }
 
 // GetPod 获取Pod详情
@@ -65,7 +65,7 @@ func (a *PodAPI) GetPod(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	pod, err := podService.(*service.PodService).GetPod(namespace, name)
+	pod, err := podService.(*service.PodService).GetPod(c.Request.Context(), namespace, name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -86,13 +86,14 @@ func (a *PodAPI) DeletePod(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	err := podService.(*service.PodService).DeletePod(namespace, name)
+	dryRun := dryRunRequested(c)
+	err := podService.(*service.PodService).DeletePod(c.Request.Context(), namespace, name, dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
 }
 
 // GetPodLogs 获取Pod日志
@@ -110,7 +111,7 @@ func (a *PodAPI) GetPodLogs(c *gin.Context) {
 	tailLines := c.DefaultQuery("tail_lines", "100")
 
 	lines, _ := strconv.ParseInt(tailLines, 10, 64)
-	logs, err := podService.(*service.PodService).GetPodLogs(namespace, name, container, lines)
+	logs, err := podService.(*service.PodService).GetPodLogs(c.Request.Context(), namespace, name, container, lines)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -144,7 +145,9 @@ func (a *PodAPI) LogsStream(c *gin.Context) {
 	tailLines, _ := strconv.ParseInt(c.DefaultQuery("tail_lines", "1000"), 10, 64)
 	sinceSeconds, _ := strconv.ParseInt(c.DefaultQuery("since_seconds", "0"), 10, 64)
 
-	err = podService.(*service.PodService).StreamLogs(namespace, name, container, follow, previous, tailLines, sinceSeconds, wsConn)
+	ctx, cancel := streamContext(c)
+	defer cancel()
+	err = podService.(*service.PodService).StreamLogs(ctx, namespace, name, container, follow, previous, tailLines, sinceSeconds, wsConn)
 	if err != nil {
 		if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 			log.Printf("logs stream error: %v", err)
@@ -183,7 +186,7 @@ func (a *PodAPI) ExecCommand(c *gin.Context) {
 	}
 
 	// 执行命令
-	stdout, stderr, err := podService.(*service.PodService).ExecCommand(namespace, podName, container, req.Command)
+	stdout, stderr, err := podService.(*service.PodService).ExecCommand(c.Request.Context(), namespace, podName, container, req.Command)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "执行命令失败: "+err.Error()))
 		return
@@ -227,8 +230,10 @@ func (a *PodAPI) ExecTerminal(c *gin.Context) {
 	podName := c.Param("name")
 	container := c.Query("container")
 
-	// 执行终端连接
-	err = podService.(*service.PodService).ExecTerminal(namespace, podName, container, wsConn)
+	// 执行终端连接（服务关闭时随 ctx 结束）
+	ctx, cancel := streamContext(c)
+	defer cancel()
+	err = podService.(*service.PodService).ExecTerminal(ctx, namespace, podName, container, wsConn)
 	if err != nil {
 		// 只有在连接仍然活跃时才发送错误信息
 		if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
@@ -264,25 +269,8 @@ func (a *PodAPI) CreatePodFromYaml(c *gin.Context) {
 
 	ps := podService.(*service.PodService)
 
-	// 创建dynamic client
-	dynamicClient, err := dynamic.NewForConfig(ps.GetK8sClient().Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建Dynamic Client失败: "+err.Error()))
-		return
-	}
-
-	// 创建discovery client
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(ps.GetK8sClient().Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建Discovery Client失败: "+err.Error()))
-		return
-	}
-
-	// 创建cached discovery client
-	cachedDiscoveryClient := memory.NewMemCacheClient(discoveryClient)
-
-	// 创建rest mapper
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscoveryClient)
+	// 复用集群共享的 dynamic client 与 RESTMapper
+	k8sClient := ps.GetK8sClient()
 
 	// 解析YAML
 	decode := yamlutil.NewYAMLOrJSONDecoder(strings.NewReader(req.YAML), 4096)
@@ -305,7 +293,7 @@ func (a *PodAPI) CreatePodFromYaml(c *gin.Context) {
 		gvk := obj.GroupVersionKind()
 
 		// 获取mapping
-		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		mapping, err := k8sClient.RESTMapping(gvk.GroupKind(), gvk.Version)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "获取REST Mapping失败: "+err.Error()))
 			return
@@ -320,12 +308,12 @@ func (a *PodAPI) CreatePodFromYaml(c *gin.Context) {
 		// 创建资源
 		var dr dynamic.ResourceInterface
 		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-			dr = dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource).Namespace(namespace)
 		} else {
-			dr = dynamicClient.Resource(mapping.Resource)
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource)
 		}
 
-		_, err = dr.Create(context.TODO(), obj, metav1.CreateOptions{})
+		_, err = dr.Create(c.Request.Context(), obj, metav1.CreateOptions{})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建资源失败: "+err.Error()))
 			return
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/middleware"
 	"github.com/kube-admin/kube-admin/backend/internal/model"
 	"github.com/kube-admin/kube-admin/backend/internal/service"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -20,6 +22,7 @@ func NewResourceAPI() *ResourceAPI { return &ResourceAPI{} }
 // applyRequest apply 请求体
 type applyRequest struct {
 	YAML string `json:"yaml" binding:"required"`
+	model.ApplyOptions
 }
 
 // parseGVR 从查询参数解析 GVR 与 namespace
@@ -31,6 +34,23 @@ func parseGVR(c *gin.Context) (schema.GroupVersionResource, string) {
 	}, c.Query("namespace")
 }
 
+// dryRunRequested 读取 dry_run=true 查询参数；试运行的写请求在审计中单独标注，便于与真实变更区分
+func dryRunRequested(c *gin.Context) bool {
+	if c.Query("dry_run") != "true" {
+		return false
+	}
+	middleware.SetAuditDetail(c, "dry run")
+	return true
+}
+
+// dryRunResult 类型化删除接口的响应 data：试运行时标明 dry_run，否则保持原来的 null
+func dryRunResult(dryRun bool) gin.H {
+	if !dryRun {
+		return nil
+	}
+	return gin.H{"dry_run": true}
+}
+
 // validateGVR 校验 GVR 必填
 func validateGVR(gvr schema.GroupVersionResource) bool {
 	return gvr.Resource != "" && gvr.Version != ""
@@ -48,12 +68,45 @@ func (a *ResourceAPI) List(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
 		return
 	}
-	list, err := rs.(*service.ResourceService).List(gvr, ns)
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	list, total, err := rs.(*service.ResourceService).List(c.Request.Context(), gvr, ns, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	// 未分页时保持原始 List 结构（apiVersion/kind/items），兼容旧调用方
+	if !q.Paged() {
+		c.JSON(http.StatusOK, model.SuccessResponse(list))
+		return
+	}
+	page := model.PageResponse{
+		Total:              total,
+		Items:              list.Items,
+		Continue:           list.GetContinue(),
+		RemainingItemCount: list.GetRemainingItemCount(),
+	}
+	if !q.Chunked() {
+		page.Page, page.PageSize = q.PageBounds()
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(page))
+}
+
+// APIResources 列出集群可用资源类型（GVR、Kind、作用域与 verbs），供通用资源浏览器使用
+func (a *ResourceAPI) APIResources(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	resources, err := rs.(*service.ResourceService).APIResources(c.Query("refresh") == "true")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, model.SuccessResponse(list))
+	c.JSON(http.StatusOK, model.SuccessResponse(resources))
 }
 
 // Get 通用获取
@@ -68,7 +121,7 @@ func (a *ResourceAPI) Get(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
 		return
 	}
-	obj, err := rs.(*service.ResourceService).Get(gvr, ns, c.Param("name"))
+	obj, err := rs.(*service.ResourceService).Get(c.Request.Context(), gvr, ns, c.Param("name"))
 	if err != nil {
 		c.JSON(http.StatusNotFound, model.ErrorResponse(404, err.Error()))
 		return
@@ -88,14 +141,15 @@ func (a *ResourceAPI) Delete(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
 		return
 	}
-	if err := rs.(*service.ResourceService).Delete(gvr, ns, c.Param("name")); err != nil {
+	dryRun := dryRunRequested(c)
+	if err := rs.(*service.ResourceService).Delete(c.Request.Context(), gvr, ns, c.Param("name"), dryRun); err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"message": "deleted"}))
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"message": "deleted", "dry_run": dryRun}))
 }
 
-// Apply 从 YAML 创建或更新任意资源
+// Apply 从 YAML（可含多个文档或 List）按依赖顺序创建或更新任意资源，返回逐个对象的结果
 func (a *ResourceAPI) Apply(c *gin.Context) {
 	rs, exists := c.Get("resource_service")
 	if !exists {
@@ -107,15 +161,45 @@ func (a *ResourceAPI) Apply(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
 		return
 	}
-	obj, err := rs.(*service.ResourceService).ApplyFromYAML(req.YAML)
+	req.FieldManager = service.FieldManagerFor(c.GetString("username"))
+	req.DryRun = req.DryRun || c.Query("dry_run") == "true"
+	switch {
+	case req.DryRun:
+		middleware.SetAuditDetail(c, "dry run")
+	case req.Force:
+		middleware.SetAuditDetail(c, "force apply as "+req.FieldManager)
+	}
+	result, err := rs.(*service.ResourceService).ApplyFromYAML(c.Request.Context(), req.YAML, req.ApplyOptions)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, model.SuccessResponse(obj))
+	respondApply(c, result)
+}
+
+// Diff 预览 apply 会产生的变更：对 YAML 中每个对象做服务端试运行，返回与线上对象的 unified diff 和字段级变更
+func (a *ResourceAPI) Diff(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	var req applyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	req.FieldManager = service.FieldManagerFor(c.GetString("username"))
+	middleware.SetAuditDetail(c, "dry run")
+	result, err := rs.(*service.ResourceService).DiffFromYAML(c.Request.Context(), req.YAML, req.ApplyOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(result))
 }
 
-// Patch 通用补丁
+// Patch 通用补丁，dry_run=true 时返回补丁后的对象但不持久化
 func (a *ResourceAPI) Patch(c *gin.Context) {
 	rs, exists := c.Get("resource_service")
 	if !exists {
@@ -139,7 +223,7 @@ func (a *ResourceAPI) Patch(c *gin.Context) {
 	if pt == "" {
 		pt = types.StrategicMergePatchType
 	}
-	obj, err := rs.(*service.ResourceService).Patch(gvr, ns, c.Param("name"), pt, []byte(req.Data))
+	obj, err := rs.(*service.ResourceService).Patch(c.Request.Context(), gvr, ns, c.Param("name"), pt, []byte(req.Data), dryRunRequested(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
 		return
@@ -164,15 +248,41 @@ func (a *ResourceAPI) ScaleResource(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "replicas 参数无效"))
 		return
 	}
-	if err := rs.(*service.ResourceService).Scale(gvr, ns, c.Param("name"), int32(replicas)); err != nil {
+	warning, err := rs.(*service.ResourceService).Scale(c.Request.Context(), gvr, ns, c.Param("name"), int32(replicas), c.Query("force") == "true", dryRunRequested(c))
+	respondScale(c, warning, err)
+}
+
+// RestartResource 通用滚动重启（workload）
+func (a *ResourceAPI) RestartResource(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	gvr, ns := parseGVR(c)
+	if !validateGVR(gvr) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
+		return
+	}
+	if err := rs.(*service.ResourceService).Restart(c.Request.Context(), gvr, ns, c.Param("name")); err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, model.SuccessResponse(nil))
 }
 
-// RestartResource 通用滚动重启（workload）
-func (a *ResourceAPI) RestartResource(c *gin.Context) {
+// PauseResource 暂停 rollout（仅 Deployment），暂停期间的修改在恢复后一次性滚动
+func (a *ResourceAPI) PauseResource(c *gin.Context) {
+	a.setPaused(c, true)
+}
+
+// ResumeResource 恢复 rollout（仅 Deployment）
+func (a *ResourceAPI) ResumeResource(c *gin.Context) {
+	a.setPaused(c, false)
+}
+
+// setPaused 暂停/恢复的公共处理
+func (a *ResourceAPI) setPaused(c *gin.Context, paused bool) {
 	rs, exists := c.Get("resource_service")
 	if !exists {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
@@ -183,9 +293,138 @@ func (a *ResourceAPI) RestartResource(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
 		return
 	}
-	if err := rs.(*service.ResourceService).Restart(gvr, ns, c.Param("name")); err != nil {
+	if err := rs.(*service.ResourceService).SetPaused(c.Request.Context(), gvr, ns, c.Param("name"), paused); err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, model.SuccessResponse(nil))
 }
+
+// GetRolloutStrategy 获取滚动更新参数（workload：Deployment/StatefulSet/DaemonSet）
+func (a *ResourceAPI) GetRolloutStrategy(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	gvr, ns := parseGVR(c)
+	if !validateGVR(gvr) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
+		return
+	}
+	strategy, err := rs.(*service.ResourceService).GetRolloutStrategy(c.Request.Context(), gvr, ns, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(strategy))
+}
+
+// UpdateRolloutStrategy 修改滚动更新参数（未提供的字段保持不变），校验不通过返回 400
+func (a *ResourceAPI) UpdateRolloutStrategy(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	gvr, ns := parseGVR(c)
+	if !validateGVR(gvr) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
+		return
+	}
+	var req model.RolloutStrategy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的请求参数: "+err.Error()))
+		return
+	}
+	strategy, err := rs.(*service.ResourceService).UpdateRolloutStrategy(c.Request.Context(), gvr, ns, c.Param("name"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(strategy))
+}
+
+// GetContainers 获取工作负载 Pod 模板中的容器配置（镜像、环境变量、资源、探针）
+func (a *ResourceAPI) GetContainers(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	gvr, ns := parseGVR(c)
+	if !validateGVR(gvr) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
+		return
+	}
+	containers, err := rs.(*service.ResourceService).GetContainers(c.Request.Context(), gvr, ns, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(containers))
+}
+
+// GetOwners 获取对象到顶层控制器的归属链（中途查询失败时返回已解析部分并标记 incomplete）
+func (a *ResourceAPI) GetOwners(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	gvr, ns := parseGVR(c)
+	if !validateGVR(gvr) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
+		return
+	}
+	owners, err := rs.(*service.ResourceService).GetOwners(c.Request.Context(), gvr, ns, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(owners))
+}
+
+// UpdateContainers 结构化修改容器配置，以最小 strategic merge patch 提交并记录 change-cause
+func (a *ResourceAPI) UpdateContainers(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	gvr, ns := parseGVR(c)
+	if !validateGVR(gvr) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填"))
+		return
+	}
+	var req model.ContainerSpecUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的请求参数: "+err.Error()))
+		return
+	}
+	result, err := rs.(*service.ResourceService).UpdateContainers(c.Request.Context(), gvr, ns, c.Param("name"), req)
+	if err != nil {
+		// 校验不通过 400；对象不存在 404、并发修改冲突 409，apiserver 其他错误 500
+		code := k8sErrorCode(err, http.StatusBadRequest)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+	if result.Changed {
+		middleware.SetAuditDetail(c, fmt.Sprintf("update containers %s %s/%s: %s", gvr.Resource, ns, c.Param("name"), result.ChangeCause))
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(result))
+}
+
+// respondApply 全部成功返回 200；有字段归属冲突时返回 409，其他失败返回 400，
+// message 汇总失败原因，data 仍为逐个对象的结果（冲突对象含 conflicts）
+func respondApply(c *gin.Context, result *model.ApplyResult) {
+	if err := service.ApplyResultError(result); err != nil {
+		code := http.StatusBadRequest
+		if result.Summary["conflict"] > 0 {
+			code = http.StatusConflict
+		}
+		c.JSON(code, model.Response{Code: code, Message: err.Error(), Data: result})
+		return
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse(result))
+}
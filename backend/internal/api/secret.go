@@ -30,13 +30,17 @@ func (a *SecretAPI) ListSecrets(c *gin.Context) {
 
 	namespace := c.DefaultQuery("namespace", "default")
 
-	secrets, err := secretService.(*service.SecretService).ListSecrets(namespace)
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	secrets, err := secretService.(*service.SecretService).ListSecrets(c.Request.Context(), namespace, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(secrets))
+	respondList(c, q, secrets)
 }
 
 // GetSecret 获取Secret详情
@@ -52,7 +56,7 @@ func (a *SecretAPI) GetSecret(c *gin.Context) {
 	name := c.Param("name")
 	decode := c.DefaultQuery("decode", "false") == "true"
 
-	secret, err := secretService.(*service.SecretService).GetSecret(namespace, name, decode)
+	secret, err := secretService.(*service.SecretService).GetSecret(c.Request.Context(), namespace, name, decode)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -86,7 +90,7 @@ func (a *SecretAPI) CreateSecret(c *gin.Context) {
 		req.Type = "Opaque"
 	}
 
-	err := secretService.(*service.SecretService).CreateSecret(req.Namespace, req.Name, req.Type, req.Data)
+	err := secretService.(*service.SecretService).CreateSecret(c.Request.Context(), req.Namespace, req.Name, req.Type, req.Data)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -116,7 +120,7 @@ func (a *SecretAPI) UpdateSecret(c *gin.Context) {
 		return
 	}
 
-	err := secretService.(*service.SecretService).UpdateSecret(namespace, name, req.Data)
+	err := secretService.(*service.SecretService).UpdateSecret(c.Request.Context(), namespace, name, req.Data, dryRunRequested(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
@@ -137,11 +141,12 @@ func (a *SecretAPI) DeleteSecret(c *gin.Context) {
 	namespace := c.Query("namespace")
 	name := c.Param("name")
 
-	err := secretService.(*service.SecretService).DeleteSecret(namespace, name)
+	dryRun := dryRunRequested(c)
+	err := secretService.(*service.SecretService).DeleteSecret(c.Request.Context(), namespace, name, dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
 }
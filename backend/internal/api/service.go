@@ -1,7 +1,6 @@
 package api
 
 import (
-	"context"
 	"io"
 	"net/http"
 	"strings"
@@ -13,10 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 // ServiceAPI Service API处理器
@@ -41,13 +37,17 @@ func (a *ServiceAPI) ListServices(c *gin.Context) {
 
 	namespace := c.DefaultQuery("namespace", "default")
 
-	services, err := serviceService.(*service.ServiceService).ListServices(namespace)
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	services, err := serviceService.(*service.ServiceService).ListServices(c.Request.Context(), namespace, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(services))
+	respondList(c, q, services)
 }
 
 // GetService 获取Service详情
@@ -62,7 +62,7 @@ func (a *ServiceAPI) GetService(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.DefaultQuery("namespace", "default")
 
-	svc, err := serviceService.(*service.ServiceService).GetService(namespace, name)
+	svc, err := serviceService.(*service.ServiceService).GetService(c.Request.Context(), namespace, name)
 	if err != nil {
 		c.JSON(http.StatusNotFound, model.ErrorResponse(404, err.Error()))
 		return
@@ -83,13 +83,14 @@ func (a *ServiceAPI) DeleteService(c *gin.Context) {
 	name := c.Param("name")
 	namespace := c.DefaultQuery("namespace", "default")
 
-	err := serviceService.(*service.ServiceService).DeleteService(namespace, name)
+	dryRun := dryRunRequested(c)
+	err := serviceService.(*service.ServiceService).DeleteService(c.Request.Context(), namespace, name, dryRun)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"message": "Service deleted successfully"}))
+	c.JSON(http.StatusOK, model.SuccessResponse(gin.H{"message": "Service deleted successfully", "dry_run": dryRun}))
 }
 
 // CreateServiceFromYaml 通过YAML创建Service
@@ -117,25 +118,8 @@ func (a *ServiceAPI) CreateServiceFromYaml(c *gin.Context) {
 
 	ss := serviceService.(*service.ServiceService)
 
-	// 创建dynamic client
-	dynamicClient, err := dynamic.NewForConfig(ss.GetK8sClient().Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建Dynamic Client失败: "+err.Error()))
-		return
-	}
-
-	// 创建discovery client
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(ss.GetK8sClient().Config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建Discovery Client失败: "+err.Error()))
-		return
-	}
-
-	// 创建cached discovery client
-	cachedDiscoveryClient := memory.NewMemCacheClient(discoveryClient)
-
-	// 创建rest mapper
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscoveryClient)
+	// 复用集群共享的 dynamic client 与 RESTMapper
+	k8sClient := ss.GetK8sClient()
 
 	// 解析YAML
 	decode := yamlutil.NewYAMLOrJSONDecoder(strings.NewReader(req.YAML), 4096)
@@ -158,7 +142,7 @@ func (a *ServiceAPI) CreateServiceFromYaml(c *gin.Context) {
 		gvk := obj.GroupVersionKind()
 
 		// 获取mapping
-		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		mapping, err := k8sClient.RESTMapping(gvk.GroupKind(), gvk.Version)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "获取REST Mapping失败: "+err.Error()))
 			return
@@ -173,12 +157,12 @@ func (a *ServiceAPI) CreateServiceFromYaml(c *gin.Context) {
 		// 创建资源
 		var dr dynamic.ResourceInterface
 		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-			dr = dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource).Namespace(namespace)
 		} else {
-			dr = dynamicClient.Resource(mapping.Resource)
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource)
 		}
 
-		_, err = dr.Create(context.TODO(), obj, metav1.CreateOptions{})
+		_, err = dr.Create(c.Request.Context(), obj, metav1.CreateOptions{})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建资源失败: "+err.Error()))
 			return
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// StatefulSetAPI StatefulSet API
+type StatefulSetAPI struct {
+	// 注意：在多集群环境中，服务实例将在中间件中动态注入
+	statefulSetService *service.StatefulSetService
+}
+
+// NewStatefulSetAPI 创建StatefulSet API
+func NewStatefulSetAPI(statefulSetService *service.StatefulSetService) *StatefulSetAPI {
+	return &StatefulSetAPI{statefulSetService: statefulSetService}
+}
+
+// ListStatefulSets 获取StatefulSet列表
+func (a *StatefulSetAPI) ListStatefulSets(c *gin.Context) {
+	// 从上下文中获取服务实例
+	statefulSetService, exists := c.Get("statefulset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.DefaultQuery("namespace", "default")
+
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	statefulSets, err := statefulSetService.(*service.StatefulSetService).ListStatefulSets(c.Request.Context(), namespace, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	respondList(c, q, statefulSets)
+}
+
+// GetStatefulSet 获取StatefulSet详情
+func (a *StatefulSetAPI) GetStatefulSet(c *gin.Context) {
+	// 从上下文中获取服务实例
+	statefulSetService, exists := c.Get("statefulset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	statefulSet, err := statefulSetService.(*service.StatefulSetService).GetStatefulSet(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(statefulSet))
+}
+
+// DeleteStatefulSet 删除StatefulSet
+func (a *StatefulSetAPI) DeleteStatefulSet(c *gin.Context) {
+	// 从上下文中获取服务实例
+	statefulSetService, exists := c.Get("statefulset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	dryRun := dryRunRequested(c)
+	err := statefulSetService.(*service.StatefulSetService).DeleteStatefulSet(c.Request.Context(), namespace, name, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(dryRunResult(dryRun)))
+}
+
+// ScaleStatefulSet 扩缩容StatefulSet
+func (a *StatefulSetAPI) ScaleStatefulSet(c *gin.Context) {
+	// 从上下文中获取服务实例
+	statefulSetService, exists := c.Get("statefulset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+	replicasStr := c.Query("replicas")
+
+	replicas, err := strconv.ParseInt(replicasStr, 10, 32)
+	if err != nil || replicas < 0 {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "replicas参数错误"))
+		return
+	}
+
+	// 副本数由 HPA 管理时需 force=true 才执行
+	force := c.Query("force") == "true"
+	warning, err := statefulSetService.(*service.StatefulSetService).ScaleStatefulSet(c.Request.Context(), namespace, name, int32(replicas), force, dryRunRequested(c))
+	respondScale(c, warning, err)
+}
+
+// RestartStatefulSet 重启StatefulSet
+func (a *StatefulSetAPI) RestartStatefulSet(c *gin.Context) {
+	// 从上下文中获取服务实例
+	statefulSetService, exists := c.Get("statefulset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Param("name")
+
+	err := statefulSetService.(*service.StatefulSetService).RestartStatefulSet(c.Request.Context(), namespace, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+}
+
+// CreateStatefulSetFromYaml 通过YAML创建StatefulSet
+func (a *StatefulSetAPI) CreateStatefulSetFromYaml(c *gin.Context) {
+	// 从上下文中获取服务实例
+	statefulSetService, exists := c.Get("statefulset_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+
+	createFromYAML(c, statefulSetService.(*service.StatefulSetService).GetK8sClient())
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamsCtx 长连接（WebSocket / SSE / agent 隧道）的公共根 context，服务优雅关闭时取消。
+// http.Server.Shutdown 不跟踪已劫持的 WebSocket 连接，需借此主动结束会话。
+var streamsCtx, cancelStreams = context.WithCancel(context.Background())
+
+// CloseStreams 结束所有进行中的长连接会话（注册到 http.Server.RegisterOnShutdown）
+func CloseStreams() {
+	cancelStreams()
+}
+
+// streamContext 派生长连接 context：请求结束或服务关闭任一发生即取消
+func streamContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	stop := context.AfterFunc(streamsCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
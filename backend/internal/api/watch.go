@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+	"github.com/kube-admin/kube-admin/backend/pkg/logger"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// watchKeepalive 空闲心跳间隔，防止代理/负载均衡断开空闲连接
+	watchKeepalive = 30 * time.Second
+	// watchWriteTimeout 单条推送写超时，慢客户端不拖住 watch
+	watchWriteTimeout = 10 * time.Second
+)
+
+// Watch 资源实时 watch（WebSocket 或 SSE）。
+// resource 为内置类型（pods/deployments/services/...）且未给 version 时推送与列表接口一致的结构，
+// 否则按 group/version/resource 推送原始对象。支持 label_selector/field_selector；
+// resource_version（SSE 亦可用 Last-Event-ID）用于断线续接。
+// 连接的 namespace 范围在建连时确定（命名空间须存在），之后推送只限于该范围；可见范围与列表接口一致。
+func (a *ResourceAPI) Watch(c *gin.Context) {
+	rs, exists := c.Get("resource_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return
+	}
+	gvr, ns := parseGVR(c)
+	if ns == "all" {
+		ns = ""
+	}
+	typed := false
+	if gvr.Version == "" {
+		if typedGVR, ok := service.TypedWatchGVR(gvr.Resource); ok {
+			gvr, typed = typedGVR, true
+			if gvr.Resource == "nodes" || gvr.Resource == "namespaces" {
+				ns = "" // 集群级资源忽略 namespace
+			}
+		}
+	}
+	if !validateGVR(gvr) {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "version 和 resource 参数必填（或使用内置资源名）"))
+		return
+	}
+	q, ok := bindListQuery(c)
+	if !ok {
+		return
+	}
+	// 连接的命名空间在建连时校验一次，之后只推送该范围内的对象
+	if err := rs.(*service.ResourceService).ValidateWatchNamespace(c.Request.Context(), ns); err != nil {
+		code := k8sErrorCode(err, http.StatusInternalServerError)
+		c.JSON(code, model.ErrorResponse(code, err.Error()))
+		return
+	}
+	resourceVersion := c.Query("resource_version")
+	if resourceVersion == "" {
+		resourceVersion = c.GetHeader("Last-Event-ID") // EventSource 自动重连
+	}
+
+	watchFn := func(ctx context.Context, send func(model.WatchEvent) error) error {
+		return rs.(*service.ResourceService).Watch(ctx, gvr, ns, q, resourceVersion, typed, send)
+	}
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		watchWebSocket(c, watchFn)
+		return
+	}
+	watchSSE(c, resourceVersion != "", watchFn)
+}
+
+// watchWebSocket 以 WebSocket 文本帧（每帧一个 JSON 事件）推送
+func watchWebSocket(c *gin.Context, watchFn func(context.Context, func(model.WatchEvent) error) error) {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	ctx, cancel := streamContext(c)
+	defer cancel()
+	// 读循环仅用于感知客户端断开
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	send := func(ev model.WatchEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ws.SetWriteDeadline(time.Now().Add(watchWriteTimeout))
+		return ws.WriteJSON(ev)
+	}
+	go func() {
+		ticker := time.NewTicker(watchKeepalive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				mu.Lock()
+				err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(watchWriteTimeout))
+				mu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	if err := watchFn(ctx, send); err != nil && ctx.Err() == nil {
+		logger.Error("watch: %v", err)
+		_ = send(model.WatchEvent{Type: string(watch.Error), Object: map[string]string{"message": err.Error()}})
+	}
+	mu.Lock()
+	_ = ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	mu.Unlock()
+}
+
+// watchSSE 以 Server-Sent Events 推送。初始同步完成后的事件带 id（resourceVersion），
+// 浏览器 EventSource 重连时经 Last-Event-ID 续接；初始 LIST 阶段不带 id，避免以旧对象版本续接。
+func watchSSE(c *gin.Context, resumed bool, watchFn func(context.Context, func(model.WatchEvent) error) error) {
+	// 长连接不受 http.Server WriteTimeout 限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx, cancel := streamContext(c)
+	defer cancel()
+	synced := resumed
+	var mu sync.Mutex
+	send := func(ev model.WatchEvent) error {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if ev.Type == string(watch.Bookmark) {
+			synced = true
+		}
+		if synced && ev.ResourceVersion != "" {
+			fmt.Fprintf(c.Writer, "id: %s\n", ev.ResourceVersion)
+		}
+		if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	// 心跳须在 handler 返回前退出，之后不能再写 ResponseWriter
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(watchKeepalive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				mu.Lock()
+				fmt.Fprint(c.Writer, ": ping\n\n")
+				c.Writer.Flush()
+				mu.Unlock()
+			}
+		}
+	}()
+
+	if err := watchFn(ctx, send); err != nil && ctx.Err() == nil {
+		logger.Error("watch: %v", err)
+		_ = send(model.WatchEvent{Type: string(watch.Error), Object: map[string]string{"message": err.Error()}})
+	}
+	cancel()
+	wg.Wait()
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/internal/service"
+)
+
+// WorkloadAPI 工作负载（Deployment/StatefulSet/DaemonSet）rollout API
+type WorkloadAPI struct {
+	workloadService *service.WorkloadService
+}
+
+// NewWorkloadAPI 创建工作负载API实例
+func NewWorkloadAPI(workloadService *service.WorkloadService) *WorkloadAPI {
+	return &WorkloadAPI{workloadService: workloadService}
+}
+
+// workloadParams 取出服务实例并校验 kind，失败时直接写响应
+func workloadParams(c *gin.Context) (*service.WorkloadService, string, bool) {
+	workloadService, exists := c.Get("workload_service")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "服务未初始化"))
+		return nil, "", false
+	}
+	kind := c.Param("kind")
+	if _, ok := service.WorkloadKinds[kind]; !ok {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "kind 仅支持 deployments、statefulsets、daemonsets"))
+		return nil, "", false
+	}
+	return workloadService.(*service.WorkloadService), kind, true
+}
+
+// RolloutStatus 获取滚动更新状态
+func (a *WorkloadAPI) RolloutStatus(c *gin.Context) {
+	ws, kind, ok := workloadParams(c)
+	if !ok {
+		return
+	}
+
+	status, err := ws.RolloutStatus(c.Request.Context(), kind, c.Query("namespace"), c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(status))
+}
+
+// WatchRolloutStatus 流式推送滚动更新状态（WebSocket 或 SSE），状态变化时推送 STATUS 事件，
+// rollout 完成或超出进度期限后服务端结束连接
+func (a *WorkloadAPI) WatchRolloutStatus(c *gin.Context) {
+	ws, kind, ok := workloadParams(c)
+	if !ok {
+		return
+	}
+	namespace, name := c.Query("namespace"), c.Param("name")
+
+	watchFn := func(ctx context.Context, send func(model.WatchEvent) error) error {
+		return ws.WatchRolloutStatus(ctx, kind, namespace, name, func(status *model.RolloutStatus) error {
+			return send(model.WatchEvent{Type: "STATUS", Object: status})
+		})
+	}
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		watchWebSocket(c, watchFn)
+		return
+	}
+	watchSSE(c, false, watchFn)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// createFromYAML 解析请求体中的 YAML（可含多个文档）并逐个创建资源，
+// 供 StatefulSet、DaemonSet 等新增类型的 /yaml 创建接口共用
+func createFromYAML(c *gin.Context, k8sClient *k8s.Client) {
+	var req struct {
+		YAML string `json:"yaml"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "无效的请求参数"))
+		return
+	}
+
+	if req.YAML == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "YAML内容不能为空"))
+		return
+	}
+
+	// 解析YAML
+	decode := yamlutil.NewYAMLOrJSONDecoder(strings.NewReader(req.YAML), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decode.Decode(obj)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "解析YAML失败: "+err.Error()))
+			return
+		}
+
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		// 获取GVK
+		gvk := obj.GroupVersionKind()
+
+		// 获取mapping
+		mapping, err := k8sClient.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse(400, "获取REST Mapping失败: "+err.Error()))
+			return
+		}
+
+		// 获取namespace
+		namespace, _, err := unstructured.NestedString(obj.Object, "metadata", "namespace")
+		if err != nil || namespace == "" {
+			namespace = "default"
+		}
+
+		// 创建资源
+		var dr dynamic.ResourceInterface
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource).Namespace(namespace)
+		} else {
+			dr = k8sClient.DynamicClient.Resource(mapping.Resource)
+		}
+
+		_, err = dr.Create(c.Request.Context(), obj, metav1.CreateOptions{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, model.ErrorResponse(500, "创建资源失败: "+err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse(nil))
+}
@@ -8,6 +8,14 @@ import (
 	"github.com/kube-admin/kube-admin/backend/internal/model"
 )
 
+// auditDetailKey 处理函数通过 SetAuditDetail 写入的审计补充说明
+const auditDetailKey = "audit_detail"
+
+// SetAuditDetail 为本次请求的审计日志附加业务说明（如回滚的源/目标版本）
+func SetAuditDetail(c *gin.Context, detail string) {
+	c.Set(auditDetailKey, detail)
+}
+
 // AuditMiddleware 审计中间件：在请求处理后记录写操作到数据库。
 // 仅记录 POST/PUT/DELETE/PATCH，读操作不记录。同步写入保证顺序与可靠性。
 func AuditMiddleware() gin.HandlerFunc {
@@ -29,6 +37,7 @@ func AuditMiddleware() gin.HandlerFunc {
 			Status:    c.Writer.Status(),
 			IP:        c.ClientIP(),
 			UserAgent: c.Request.UserAgent(),
+			Detail:    c.GetString(auditDetailKey),
 			CreatedAt: time.Now(),
 		}
 		// 写入失败不影响主流程，仅记录日志
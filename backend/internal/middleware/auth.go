@@ -8,12 +8,20 @@ import (
 	"github.com/kube-admin/kube-admin/backend/internal/model"
 )
 
+// sseQueryTokenRoutes 允许经 ?token= 鉴权的 SSE 路由（浏览器 EventSource 无法设置请求头）。
+// 仅限 watch 类长连接，其余请求的令牌不应出现在 URL 与访问日志中
+var sseQueryTokenRoutes = map[string]bool{
+	"/api/v1/resources/watch":                            true,
+	"/api/v1/workloads/:kind/:name/rollout-status/watch": true,
+}
+
 // AuthMiddleware 认证中间件
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 检查是否是WebSocket升级请求
-		if c.GetHeader("Upgrade") == "websocket" {
-			// 对于WebSocket请求，尝试从查询参数获取token
+		// 检查是否是WebSocket升级或 watch 路由的SSE请求（浏览器 EventSource 同样无法设置请求头）
+		sse := strings.Contains(c.GetHeader("Accept"), "text/event-stream") && sseQueryTokenRoutes[c.FullPath()]
+		if c.GetHeader("Upgrade") == "websocket" || sse {
+			// 对于WebSocket/SSE请求，尝试从查询参数获取token
 			tokenString := c.Query("token")
 			if tokenString == "" {
 				// 如果查询参数中没有token，尝试从Cookie获取
@@ -39,6 +39,14 @@ func ClusterMiddleware(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) gi
 			dashboardService := service.NewDashboardService(defaultK8sClient)
 			eventService := service.NewEventService(defaultK8sClient)
 			resourceService := service.NewResourceService(defaultK8sClient)
+			workloadService := service.NewWorkloadService(defaultK8sClient)
+			statefulSetService := service.NewStatefulSetService(defaultK8sClient)
+			daemonSetService := service.NewDaemonSetService(defaultK8sClient)
+			jobService := service.NewJobService(defaultK8sClient)
+			cronJobService := service.NewCronJobService(defaultK8sClient)
+			hpaService := service.NewHPAService(defaultK8sClient)
+			graphService := service.NewGraphService(defaultK8sClient)
+			helmService := service.NewHelmService(defaultK8sClient)
 
 			c.Set("pod_service", podService)
 			c.Set("deployment_service", deploymentService)
@@ -50,6 +58,14 @@ func ClusterMiddleware(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) gi
 			c.Set("dashboard_service", dashboardService)
 			c.Set("event_service", eventService)
 			c.Set("resource_service", resourceService)
+			c.Set("workload_service", workloadService)
+			c.Set("statefulset_service", statefulSetService)
+			c.Set("daemonset_service", daemonSetService)
+			c.Set("job_service", jobService)
+			c.Set("cronjob_service", cronJobService)
+			c.Set("hpa_service", hpaService)
+			c.Set("graph_service", graphService)
+			c.Set("helm_service", helmService)
 
 			c.Next()
 			return
@@ -92,6 +108,14 @@ func ClusterMiddleware(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) gi
 		dashboardService := service.NewDashboardService(k8sClient)
 		eventService := service.NewEventService(k8sClient)
 		resourceService := service.NewResourceService(k8sClient)
+		workloadService := service.NewWorkloadService(k8sClient)
+		statefulSetService := service.NewStatefulSetService(k8sClient)
+		daemonSetService := service.NewDaemonSetService(k8sClient)
+		jobService := service.NewJobService(k8sClient)
+		cronJobService := service.NewCronJobService(k8sClient)
+		hpaService := service.NewHPAService(k8sClient)
+		graphService := service.NewGraphService(k8sClient)
+		helmService := service.NewHelmService(k8sClient)
 
 		c.Set("pod_service", podService)
 		c.Set("deployment_service", deploymentService)
@@ -103,6 +127,14 @@ func ClusterMiddleware(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) gi
 		c.Set("dashboard_service", dashboardService)
 		c.Set("event_service", eventService)
 		c.Set("resource_service", resourceService)
+		c.Set("workload_service", workloadService)
+		c.Set("statefulset_service", statefulSetService)
+		c.Set("daemonset_service", daemonSetService)
+		c.Set("job_service", jobService)
+		c.Set("cronjob_service", cronJobService)
+		c.Set("hpa_service", hpaService)
+		c.Set("graph_service", graphService)
+		c.Set("helm_service", helmService)
 
 		c.Next()
 	}
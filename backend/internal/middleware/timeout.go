@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeout 为普通请求的 context 设置整体期限，使一次操作内的多次 K8s 调用（如 Dashboard 统计）
+// 在期限到达或客户端断开时一并取消。WebSocket 与 SSE 长连接不设期限，由连接生命周期控制。
+func RequestTimeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Upgrade") == "websocket" || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// ClusterJoinToken agent 一次性注册令牌。
+// 明文仅在签发时返回一次，数据库只存 SHA-256 摘要；注册成功后写入 UsedAt 作废。
+type ClusterJoinToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	ClusterID uint       `json:"cluster_id" gorm:"index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;size:64"`
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// JoinTokenRequest 签发 join token 请求
+type JoinTokenRequest struct {
+	TTLHours int `json:"ttl_hours"` // 有效期（小时），默认 24，最长 168
+}
+
+// JoinTokenResponse 签发 join token 响应（明文 token 仅此一次可见）
+type JoinTokenResponse struct {
+	ClusterID uint      `json:"cluster_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AgentRegisterRequest agent 注册请求
+type AgentRegisterRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// AgentRegisterResponse agent 注册响应：长期凭据格式为 "<cluster_id>.<secret>"
+type AgentRegisterResponse struct {
+	ClusterID  uint   `json:"cluster_id"`
+	Credential string `json:"credential"`
+}
+
+// AgentStatus agent 隧道连接状态
+type AgentStatus struct {
+	ClusterID   uint       `json:"cluster_id"`
+	Connected   bool       `json:"connected"`
+	ConnectedAt *time.Time `json:"connected_at,omitempty"`
+	RemoteAddr  string     `json:"remote_addr,omitempty"`
+}
@@ -12,5 +12,6 @@ type AuditLog struct {
 	Status    int       `json:"status"`
 	IP        string    `json:"ip"`
 	UserAgent string    `json:"user_agent"`
+	Detail    string    `json:"detail,omitempty" gorm:"type:text"` // 业务补充说明，如回滚的源/目标版本
 	CreatedAt time.Time `json:"created_at" gorm:"index"`
 }
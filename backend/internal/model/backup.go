@@ -0,0 +1,126 @@
+package model
+
+import "time"
+
+// BackupSchedule 命名空间定时备份计划
+type BackupSchedule struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	Name           string     `json:"name" gorm:"uniqueIndex;not null"`            // 备份名以此为前缀
+	ClusterID      uint       `json:"cluster_id"`                                  // 0 表示默认集群
+	Namespaces     []string   `json:"namespaces" gorm:"serializer:json;type:text"` // 要备份的命名空间
+	Kinds          []string   `json:"kinds" gorm:"serializer:json;type:text"`      // 资源类型过滤，为空表示全部可导出资源
+	Schedule       string     `json:"schedule" gorm:"not null"`                    // 标准 5 段 cron 表达式或 @daily 等，可加 CRON_TZ= 前缀
+	Keep           int        `json:"keep"`                                        // 保留该计划最近的 N 份备份，0 表示不清理
+	Suspend        bool       `json:"suspend"`                                     // 暂停定时执行（仍可手动触发）
+	IncludeSecrets bool       `json:"include_secrets"`                             // 备份中包含 Secret（默认不含，归档不加密）
+	ClaimedAt      *time.Time `json:"-"`                                           // 最近一次被某个副本认领的触发时间，多副本部署时去重
+	LastRunAt      *time.Time `json:"last_run_at"`
+	LastBackup     string     `json:"last_backup"`                 // 最近一次成功的备份名
+	LastError      string     `json:"last_error" gorm:"type:text"` // 最近一次执行的错误，成功时清空
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// BackupScheduleRequest 创建或修改定时备份计划（修改时 name 不可变）
+type BackupScheduleRequest struct {
+	Name           string   `json:"name"`
+	ClusterID      uint     `json:"cluster_id"`
+	Namespaces     []string `json:"namespaces" binding:"required,min=1"`
+	Kinds          []string `json:"kinds"`
+	Schedule       string   `json:"schedule" binding:"required"`
+	Keep           int      `json:"keep" binding:"min=0"`
+	Suspend        bool     `json:"suspend"`
+	IncludeSecrets bool     `json:"include_secrets"`
+}
+
+// BackupQuery 备份列表过滤条件
+type BackupQuery struct {
+	ClusterID *uint  `form:"cluster_id"`
+	Namespace string `form:"namespace"`
+	Schedule  string `form:"schedule"`
+}
+
+// BackupRequest 手动备份请求
+type BackupRequest struct {
+	Namespaces     []string `json:"namespaces" binding:"required,min=1"`
+	Kinds          []string `json:"kinds"`           // 同命名空间导出的 kinds
+	IncludeOwned   bool     `json:"include_owned"`   // 包含由控制器生成的对象
+	IncludeSecrets bool     `json:"include_secrets"` // 包含 Secret（以明文 YAML 写入归档，默认跳过）
+}
+
+// Backup 备份元数据，与归档一同保存在存储中（<name>.json）
+type Backup struct {
+	Name           string         `json:"name"`
+	ClusterID      uint           `json:"cluster_id"`
+	ClusterName    string         `json:"cluster_name"`
+	Namespaces     []string       `json:"namespaces"`
+	Kinds          []string       `json:"kinds,omitempty"`
+	IncludeSecrets bool           `json:"include_secrets"`      // 归档中是否含 Secret
+	Schedule       string         `json:"schedule,omitempty"`   // 定时计划名，手动备份为空
+	CreatedBy      string         `json:"created_by,omitempty"` // 手动备份的用户
+	CreatedAt      time.Time      `json:"created_at"`
+	Objects        int            `json:"objects"`            // 对象总数
+	Summary        map[string]int `json:"summary"`            // 各 Kind 的对象数
+	Size           int64          `json:"size"`               // 归档字节数
+	Warnings       []string       `json:"warnings,omitempty"` // 部分资源读取失败的提示
+}
+
+// 恢复时已存在对象的处理方式
+const (
+	ConflictSkip      = "skip"      // 保留线上对象不变
+	ConflictOverwrite = "overwrite" // 以备份内容强制 server-side apply
+)
+
+// RestoreOptions 从备份恢复的选项
+type RestoreOptions struct {
+	Namespaces       []string          `json:"namespaces"`        // 只恢复备份中的这些命名空间，为空表示全部
+	NamespaceMapping map[string]string `json:"namespace_mapping"` // 源命名空间 → 目标命名空间
+	IncludeKinds     []string          `json:"include_kinds"`     // 只恢复这些类型（resource[.group]、Kind 或简称）
+	ExcludeKinds     []string          `json:"exclude_kinds"`     // 不恢复这些类型
+	LabelSelector    string            `json:"label_selector"`    // 只恢复匹配的对象（不作用于 Namespace）
+	ConflictPolicy   string            `json:"conflict_policy"`   // skip（默认）| overwrite
+	DryRun           bool              `json:"dry_run"`
+}
+
+// RestoreResult 恢复结果：逐个对象的 apply 结果
+type RestoreResult struct {
+	Backup string `json:"backup"`
+	ApplyResult
+}
+
+// 后台任务类型
+const (
+	BackupJobBackup   = "backup"   // 手动备份
+	BackupJobRestore  = "restore"  // 从备份恢复
+	BackupJobSchedule = "schedule" // 立即执行定时计划
+)
+
+// 后台任务状态
+const (
+	BackupJobRunning   = "running"
+	BackupJobSucceeded = "succeeded"
+	BackupJobFailed    = "failed"
+)
+
+// BackupJob 在后台执行的备份或恢复任务。接口创建任务后立即返回，通过 GET /backup-jobs/:id 查询结果；
+// 任务记录在数据库中，多副本部署时任一副本都能查询
+type BackupJob struct {
+	ID         string           `json:"id" gorm:"primaryKey;size:32"`
+	Type       string           `json:"type" gorm:"size:16;not null"`
+	Status     string           `json:"status" gorm:"size:16;index;not null"`
+	ClusterID  uint             `json:"cluster_id"`
+	Backup     string           `json:"backup,omitempty"`   // 恢复的源备份；备份成功后为生成的备份名
+	Schedule   string           `json:"schedule,omitempty"` // 执行的计划名
+	DryRun     bool             `json:"dry_run,omitempty"`
+	Result     *BackupJobResult `json:"result,omitempty" gorm:"serializer:json;size:16777216"` // 成功时的结果；size 使 MySQL 使用 mediumtext
+	Error      string           `json:"error,omitempty" gorm:"type:text"`
+	CreatedBy  string           `json:"created_by"`
+	CreatedAt  time.Time        `json:"created_at" gorm:"index"`
+	FinishedAt *time.Time       `json:"finished_at"`
+}
+
+// BackupJobResult 任务成功时的结果，按任务类型只有其中一项
+type BackupJobResult struct {
+	Backup  *Backup        `json:"backup,omitempty"`  // 备份与执行计划生成的备份
+	Restore *RestoreResult `json:"restore,omitempty"` // 恢复的逐个对象结果
+}
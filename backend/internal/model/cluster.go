@@ -11,16 +11,30 @@ import (
 // Token/ConfigContent 在写入数据库前由 BeforeSave 钩子加密，
 // 读取时由 AfterFind 钩子解密，业务层始终操作明文。
 type Cluster struct {
-	ID            uint      `json:"id" gorm:"primaryKey"`
-	Name          string    `json:"name" gorm:"uniqueIndex;not null"`
-	Description   string    `json:"description"`
-	ServerURL     string    `json:"server_url"`
-	Token         string    `json:"-" gorm:"column:token"`           // 加密存储，不序列化输出
-	ConfigPath    string    `json:"config_path"`
-	ConfigContent string    `json:"-" gorm:"column:config_content"`  // 加密存储，不序列化输出
-	Status        string    `json:"status" gorm:"default:'active'"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uint   `json:"id" gorm:"primaryKey"`
+	Name          string `json:"name" gorm:"uniqueIndex;not null"`
+	Description   string `json:"description"`
+	ServerURL     string `json:"server_url"`
+	Token         string `json:"-" gorm:"column:token"` // 加密存储，不序列化输出
+	ConfigPath    string `json:"config_path"`
+	ConfigContent string `json:"-" gorm:"column:config_content"` // 加密存储，不序列化输出
+	Status        string `json:"status" gorm:"default:'active'"`
+	// ConnectionType 连接方式：direct（默认，后端直连 apiserver）| agent（集群内 agent 反向隧道）
+	ConnectionType  string    `json:"connection_type" gorm:"default:'direct'"`
+	AgentCredential string    `json:"-" gorm:"column:agent_credential"` // agent 长期凭据的 SHA-256 摘要，不存明文
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// 集群连接方式
+const (
+	ConnectionDirect = "direct"
+	ConnectionAgent  = "agent"
+)
+
+// IsAgent 是否通过 agent 反向隧道接入
+func (c *Cluster) IsAgent() bool {
+	return c.ConnectionType == ConnectionAgent
 }
 
 // BeforeSave 写入前加密敏感字段
@@ -67,6 +81,7 @@ func (c *Cluster) ToResponse() ClusterResponse {
 		ConfigPath:       c.ConfigPath,
 		HasConfigContent: c.ConfigContent != "",
 		HasToken:         c.Token != "",
+		ConnectionType:   c.ConnectionType,
 		Status:           c.Status,
 		CreatedAt:        c.CreatedAt,
 		UpdatedAt:        c.UpdatedAt,
@@ -74,13 +89,21 @@ func (c *Cluster) ToResponse() ClusterResponse {
 }
 
 // ClusterRequest 创建/更新集群请求。更新时 Token/ConfigContent 留空表示不修改。
+// ConnectionType=agent 时无需凭据，由集群内 agent 凭一次性 join token 注册接入。
 type ClusterRequest struct {
-	Name          string `json:"name" binding:"required"`
-	Description   string `json:"description"`
-	ServerURL     string `json:"server_url"`
-	Token         string `json:"token"`
-	ConfigPath    string `json:"config_path"`
-	ConfigContent string `json:"config_content"`
+	Name           string `json:"name" binding:"required"`
+	Description    string `json:"description"`
+	ServerURL      string `json:"server_url"`
+	Token          string `json:"token"`
+	ConfigPath     string `json:"config_path"`
+	ConfigContent  string `json:"config_content"`
+	ConnectionType string `json:"connection_type" binding:"omitempty,oneof=direct agent"`
+}
+
+// HasCredential 是否提供了至少一种直连方式，或选择了 agent 接入
+func (r *ClusterRequest) HasCredential() bool {
+	return r.ConnectionType == ConnectionAgent ||
+		r.ConfigContent != "" || r.ConfigPath != "" || (r.ServerURL != "" && r.Token != "")
 }
 
 // ClusterResponse 集群响应（脱敏，不含 Token 与 ConfigContent 明文）
@@ -92,6 +115,7 @@ type ClusterResponse struct {
 	ConfigPath       string    `json:"config_path"`
 	HasConfigContent bool      `json:"has_config_content"`
 	HasToken         bool      `json:"has_token"`
+	ConnectionType   string    `json:"connection_type"`
 	Status           string    `json:"status"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
@@ -0,0 +1,49 @@
+package model
+
+// HelmRelease Helm release 概要（最新版本）
+type HelmRelease struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Revision     int    `json:"revision"`
+	Status       string `json:"status"` // deployed / failed / pending-install / pending-upgrade / superseded / uninstalled 等
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chart_version"`
+	AppVersion   string `json:"app_version"`
+	Updated      string `json:"updated"`
+	Description  string `json:"description"`
+}
+
+// HelmReleaseDetail 某个版本的完整信息
+type HelmReleaseDetail struct {
+	HelmRelease
+	Notes    string `json:"notes,omitempty"`
+	Manifest string `json:"manifest"` // 渲染后的 YAML
+}
+
+// HelmRevisionDiff 两个版本间渲染清单与用户 values 的差异（unified diff，为空表示一致）
+type HelmRevisionDiff struct {
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Manifest string `json:"manifest"`
+	Values   string `json:"values"`
+}
+
+// HelmChart 本地 chart 仓库目录中的 chart
+type HelmChart struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"app_version"`
+	Description string `json:"description"`
+	Path        string `json:"path"` // 相对 HELM_CHART_DIR 的路径，安装时作为 chart 参数
+}
+
+// HelmInstallOptions 安装或升级选项（multipart 表单，chart 包以 chart_file 上传）
+type HelmInstallOptions struct {
+	Chart           string `form:"chart"`   // 本地 chart 仓库中的路径，或 index.yaml 中的 chart 名；上传 chart_file 时忽略
+	Version         string `form:"version"` // 期望的 chart 版本，为空不校验（index.yaml 中取最新）
+	Values          string `form:"values"`  // YAML 格式的 values
+	ReuseValues     bool   `form:"reuse_values"`
+	CreateNamespace bool   `form:"create_namespace"`
+	DryRun          bool   `form:"dry_run"`
+	Description     string `form:"description"`
+}
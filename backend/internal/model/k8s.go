@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
 
 // K8sResource K8s资源通用模型
 type K8sResource struct {
@@ -14,8 +19,17 @@ type K8sResource struct {
 
 // OwnerRef 资源的顶层归属（如 Deployment/StatefulSet/DaemonSet）
 type OwnerRef struct {
-	Kind string `json:"kind"`
-	Name string `json:"name"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"api_version,omitempty"`
+}
+
+// OwnerChain 对象的归属链，从直接 owner 到顶层控制器
+type OwnerChain struct {
+	Owners     []OwnerRef `json:"owners"`
+	Top        *OwnerRef  `json:"top,omitempty"`        // 顶层控制器，无 owner 时为空
+	Incomplete bool       `json:"incomplete,omitempty"` // 中途查询失败（如无权限），链不完整
+	Message    string     `json:"message,omitempty"`
 }
 
 // PodInfo Pod信息
@@ -78,6 +92,318 @@ type DeploymentInfo struct {
 	Strategy          string `json:"strategy"`
 }
 
+// StatefulSetInfo StatefulSet信息
+type StatefulSetInfo struct {
+	K8sResource
+	Replicas          int32    `json:"replicas"`
+	ReadyReplicas     int32    `json:"ready_replicas"`
+	CurrentReplicas   int32    `json:"current_replicas"`
+	UpdatedReplicas   int32    `json:"updated_replicas"`
+	AvailableReplicas int32    `json:"available_replicas"`
+	ServiceName       string   `json:"service_name"`
+	UpdateStrategy    string   `json:"update_strategy"`
+	CurrentRevision   string   `json:"current_revision"`
+	UpdateRevision    string   `json:"update_revision"`
+	Images            []string `json:"images"`
+}
+
+// StatefulSetDetail StatefulSet详情：按序号列出副本及其 PVC
+type StatefulSetDetail struct {
+	StatefulSetInfo
+	PodManagementPolicy  string           `json:"pod_management_policy"`
+	Partition            *int32           `json:"partition,omitempty"`
+	VolumeClaimTemplates []string         `json:"volume_claim_templates"`
+	Pods                 []StatefulSetPod `json:"pods"`
+}
+
+// StatefulSetPod StatefulSet 的一个序号副本（Pod 可能尚未创建）
+type StatefulSetPod struct {
+	Ordinal  int              `json:"ordinal"`
+	Name     string           `json:"name"`
+	Exists   bool             `json:"exists"`
+	Phase    string           `json:"phase"`
+	Ready    bool             `json:"ready"`
+	Revision string           `json:"revision"` // controller-revision-hash
+	Updated  bool             `json:"updated"`  // 是否已是 update_revision
+	Node     string           `json:"node"`
+	Claims   []StatefulSetPVC `json:"claims"`
+}
+
+// StatefulSetPVC 副本按 volumeClaimTemplate 生成的 PVC
+type StatefulSetPVC struct {
+	Name         string `json:"name"`
+	Template     string `json:"template"`
+	Status       string `json:"status"` // Bound/Pending/Lost，未创建为 Missing
+	Capacity     string `json:"capacity"`
+	StorageClass string `json:"storage_class"`
+}
+
+// DaemonSetInfo DaemonSet信息
+type DaemonSetInfo struct {
+	K8sResource
+	DesiredNumberScheduled int32             `json:"desired_number_scheduled"`
+	CurrentNumberScheduled int32             `json:"current_number_scheduled"`
+	NumberReady            int32             `json:"number_ready"`
+	UpdatedNumberScheduled int32             `json:"updated_number_scheduled"`
+	NumberAvailable        int32             `json:"number_available"`
+	NumberMisscheduled     int32             `json:"number_misscheduled"`
+	UpdateStrategy         string            `json:"update_strategy"`
+	NodeSelector           map[string]string `json:"node_selector,omitempty"`
+	Images                 []string          `json:"images"`
+}
+
+// DaemonSetDetail DaemonSet详情：逐节点覆盖情况
+type DaemonSetDetail struct {
+	DaemonSetInfo
+	CoveredNodes int                   `json:"covered_nodes"` // 应运行且已有 Pod 的节点数
+	MissingNodes int                   `json:"missing_nodes"` // 应运行但没有 Pod 的节点数
+	Nodes        []DaemonSetNodeStatus `json:"nodes"`
+}
+
+// DaemonSetNodeStatus DaemonSet 在单个节点上的覆盖情况
+type DaemonSetNodeStatus struct {
+	Node         string `json:"node"`
+	Eligible     bool   `json:"eligible"` // 按 nodeSelector/节点亲和/污点容忍估算是否应运行
+	Reason       string `json:"reason,omitempty"`
+	Pod          string `json:"pod,omitempty"`
+	Phase        string `json:"phase,omitempty"`
+	Ready        bool   `json:"ready"`
+	Misscheduled bool   `json:"misscheduled"` // 有 Pod 但节点不应运行
+}
+
+// JobInfo Job信息
+type JobInfo struct {
+	K8sResource
+	Completions    *int32    `json:"completions,omitempty"` // 为空表示工作队列模式
+	Parallelism    int32     `json:"parallelism"`
+	Succeeded      int32     `json:"succeeded"`
+	Failed         int32     `json:"failed"`
+	Active         int32     `json:"active"`
+	Status         string    `json:"status"` // Running/Complete/Failed/Suspended
+	StartTime      string    `json:"start_time,omitempty"`
+	CompletionTime string    `json:"completion_time,omitempty"`
+	Duration       string    `json:"duration,omitempty"` // 结束的 Job 为总耗时，运行中为已运行时长
+	Owner          *OwnerRef `json:"owner,omitempty"`    // 所属 CronJob
+	Images         []string  `json:"images"`
+}
+
+// JobDetail Job详情：失败原因与所属 Pod
+type JobDetail struct {
+	JobInfo
+	BackoffLimit          int32    `json:"backoff_limit"`
+	ActiveDeadlineSeconds *int64   `json:"active_deadline_seconds,omitempty"`
+	FailureReason         string   `json:"failure_reason,omitempty"` // 如 BackoffLimitExceeded、DeadlineExceeded
+	FailureMessage        string   `json:"failure_message,omitempty"`
+	Pods                  []JobPod `json:"pods"`
+}
+
+// JobPod Job 创建的 Pod
+type JobPod struct {
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	Node      string `json:"node,omitempty"`
+	Restarts  int32  `json:"restarts"`
+	Reason    string `json:"reason,omitempty"` // 容器等待/终止原因，如 Error、OOMKilled
+	StartTime string `json:"start_time,omitempty"`
+}
+
+// CronJobInfo CronJob信息
+type CronJobInfo struct {
+	K8sResource
+	Schedule           string   `json:"schedule"`
+	TimeZone           string   `json:"time_zone,omitempty"`
+	Suspend            bool     `json:"suspend"`
+	ConcurrencyPolicy  string   `json:"concurrency_policy"`
+	Active             int      `json:"active"` // 运行中的 Job 数
+	LastScheduleTime   string   `json:"last_schedule_time,omitempty"`
+	LastSuccessfulTime string   `json:"last_successful_time,omitempty"`
+	NextScheduleTime   string   `json:"next_schedule_time,omitempty"` // 按 cron 表达式计算，暂停时为空
+	ScheduleError      string   `json:"schedule_error,omitempty"`     // cron 表达式或时区无效
+	Images             []string `json:"images"`
+}
+
+// CronJobDetail CronJob详情：历史保留策略与已创建的 Job
+type CronJobDetail struct {
+	CronJobInfo
+	StartingDeadlineSeconds    *int64    `json:"starting_deadline_seconds,omitempty"`
+	SuccessfulJobsHistoryLimit *int32    `json:"successful_jobs_history_limit,omitempty"`
+	FailedJobsHistoryLimit     *int32    `json:"failed_jobs_history_limit,omitempty"`
+	ActiveJobs                 []string  `json:"active_jobs"`
+	Jobs                       []JobInfo `json:"jobs"` // 按创建时间降序
+}
+
+// HPAInfo HorizontalPodAutoscaler（autoscaling/v2）信息
+type HPAInfo struct {
+	K8sResource
+	TargetKind      string         `json:"target_kind"`
+	TargetName      string         `json:"target_name"`
+	MinReplicas     int32          `json:"min_replicas"`
+	MaxReplicas     int32          `json:"max_replicas"`
+	CurrentReplicas int32          `json:"current_replicas"`
+	DesiredReplicas int32          `json:"desired_replicas"`
+	Metrics         []HPAMetric    `json:"metrics"`
+	Conditions      []PodCondition `json:"conditions"` // AbleToScale/ScalingActive/ScalingLimited
+	LastScaleTime   string         `json:"last_scale_time,omitempty"`
+}
+
+// HPAMetric 单个指标的目标值与当前值
+type HPAMetric struct {
+	Type    string `json:"type"`    // Resource/ContainerResource/Pods/Object/External
+	Name    string `json:"name"`    // 资源名（cpu/memory）或自定义指标名
+	Target  string `json:"target"`  // 如 80%、500Mi
+	Current string `json:"current"` // 尚未采集到时为空
+}
+
+// HPADetail HPA详情：含扩缩容事件历史
+type HPADetail struct {
+	HPAInfo
+	Events []EventInfo `json:"events"` // 按时间降序
+}
+
+// HPAForm 创建/编辑 HPA 的简单表单。
+// 编辑时各字段为空表示不变；cpu_utilization/memory_utilization 为 0 表示移除该指标。
+type HPAForm struct {
+	Name              string `json:"name"`        // 创建时缺省为目标名
+	TargetKind        string `json:"target_kind"` // Deployment/StatefulSet
+	TargetName        string `json:"target_name"`
+	MinReplicas       *int32 `json:"min_replicas"`
+	MaxReplicas       *int32 `json:"max_replicas"`
+	CPUUtilization    *int32 `json:"cpu_utilization"`    // 平均 CPU 使用率目标（占 request 的百分比）
+	MemoryUtilization *int32 `json:"memory_utilization"` // 平均内存使用率目标
+}
+
+// DeploymentRevision Deployment 的一个历史版本（对应一个 ReplicaSet）
+type DeploymentRevision struct {
+	Revision          int64    `json:"revision"`
+	ReplicaSet        string   `json:"replica_set"`
+	ChangeCause       string   `json:"change_cause"`
+	Images            []string `json:"images"`
+	Replicas          int32    `json:"replicas"`
+	CreationTimestamp string   `json:"creation_timestamp"`
+	Current           bool     `json:"current"` // 是否为当前版本
+}
+
+// RevisionDiff 两个版本间 Pod 模板的差异（unified diff）
+type RevisionDiff struct {
+	From int64  `json:"from"`
+	To   int64  `json:"to"`
+	Diff string `json:"diff"` // 为空表示模板一致
+}
+
+// RollbackResult 回滚结果
+type RollbackResult struct {
+	FromRevision int64  `json:"from_revision"`
+	ToRevision   int64  `json:"to_revision"`
+	Skipped      bool   `json:"skipped"` // 当前模板已与目标版本一致，未做变更
+	Message      string `json:"message"`
+}
+
+// RolloutStatus 工作负载（Deployment/StatefulSet/DaemonSet）的滚动更新状态
+type RolloutStatus struct {
+	Kind                     string        `json:"kind"`
+	Namespace                string        `json:"namespace"`
+	Name                     string        `json:"name"`
+	Generation               int64         `json:"generation"`
+	ObservedGeneration       int64         `json:"observed_generation"`
+	Replicas                 int32         `json:"replicas"` // 期望副本数（DaemonSet 为应调度节点数）
+	UpdatedReplicas          int32         `json:"updated_replicas"`
+	ReadyReplicas            int32         `json:"ready_replicas"`
+	AvailableReplicas        int32         `json:"available_replicas"`
+	Done                     bool          `json:"done"`
+	ProgressDeadlineExceeded bool          `json:"progress_deadline_exceeded"` // 仅 Deployment
+	Message                  string        `json:"message"`                    // 与 kubectl rollout status 一致的进度描述
+	BlockingPods             []BlockingPod `json:"blocking_pods"`              // 未就绪或终止中的 Pod，完成时为空
+	ResourceVersion          string        `json:"resource_version"`
+}
+
+// BlockingPod 阻塞滚动更新的 Pod 及其最近事件
+type BlockingPod struct {
+	Name     string      `json:"name"`
+	Phase    string      `json:"phase"`
+	Reason   string      `json:"reason"` // 容器等待/终止原因，如 ImagePullBackOff、CrashLoopBackOff、Terminating
+	Message  string      `json:"message"`
+	Restarts int32       `json:"restarts"`
+	Node     string      `json:"node"`
+	Events   []EventInfo `json:"events"`
+}
+
+// RolloutStrategy 工作负载的滚动更新参数。更新时字段为空表示不修改；
+// max_surge/max_unavailable 可为整数或百分比字符串（如 "25%"）
+type RolloutStrategy struct {
+	Kind                    string              `json:"kind,omitempty"`                      // 只读
+	Type                    string              `json:"type,omitempty"`                      // Deployment: RollingUpdate/Recreate；StatefulSet/DaemonSet: RollingUpdate/OnDelete
+	MaxSurge                *intstr.IntOrString `json:"max_surge,omitempty"`                 // Deployment、DaemonSet
+	MaxUnavailable          *intstr.IntOrString `json:"max_unavailable,omitempty"`           // Deployment、DaemonSet、StatefulSet（需开启 MaxUnavailableStatefulSet）
+	MinReadySeconds         *int32              `json:"min_ready_seconds,omitempty"`         // 全部
+	ProgressDeadlineSeconds *int32              `json:"progress_deadline_seconds,omitempty"` // Deployment
+	Partition               *int32              `json:"partition,omitempty"`                 // StatefulSet
+	Paused                  bool                `json:"paused"`                              // 只读，Deployment 是否暂停 rollout
+}
+
+// WorkloadContainer 工作负载 Pod 模板中的容器配置（env/resources/probe 内部字段沿用 K8s 原生结构）
+type WorkloadContainer struct {
+	Name           string                      `json:"name"`
+	Init           bool                        `json:"init"` // 是否为 initContainer
+	Image          string                      `json:"image"`
+	Env            []corev1.EnvVar             `json:"env"`
+	Resources      corev1.ResourceRequirements `json:"resources"`
+	LivenessProbe  *corev1.Probe               `json:"liveness_probe,omitempty"`
+	ReadinessProbe *corev1.Probe               `json:"readiness_probe,omitempty"`
+	StartupProbe   *corev1.Probe               `json:"startup_probe,omitempty"`
+}
+
+// ContainerUpdate 单个容器的修改，字段为空表示不修改
+type ContainerUpdate struct {
+	Name           string                       `json:"name" binding:"required"`
+	Init           bool                         `json:"init"`
+	Image          string                       `json:"image"`
+	Env            []corev1.EnvVar              `json:"env"`           // 按名称新增或替换，支持 valueFrom
+	RemoveEnv      []string                     `json:"remove_env"`    // 按名称删除
+	Resources      *corev1.ResourceRequirements `json:"resources"`     // 整体替换 requests/limits
+	LivenessProbe  *corev1.Probe                `json:"liveness_probe"`
+	ReadinessProbe *corev1.Probe                `json:"readiness_probe"`
+	StartupProbe   *corev1.Probe                `json:"startup_probe"`
+	RemoveProbes   []string                     `json:"remove_probes"` // liveness/readiness/startup
+}
+
+// ContainerSpecUpdate 批量修改容器配置；change_cause 为空时按修改内容自动生成
+type ContainerSpecUpdate struct {
+	Containers  []ContainerUpdate `json:"containers" binding:"required,min=1,dive"`
+	ChangeCause string            `json:"change_cause"`
+}
+
+// ContainerSpecResult 修改结果
+type ContainerSpecResult struct {
+	Changed     bool                `json:"changed"`
+	ChangeCause string              `json:"change_cause,omitempty"`
+	Patch       string              `json:"patch,omitempty"` // 实际提交的 strategic merge patch
+	Containers  []WorkloadContainer `json:"containers"`
+}
+
+// GraphNode 资源关系图节点
+type GraphNode struct {
+	ID        string `json:"id"` // Kind/namespace/name，集群级资源为 Kind/name
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Status    string `json:"status"` // Healthy / Warning / Error / Unknown
+	Message   string `json:"message,omitempty"`
+}
+
+// GraphEdge 资源关系图的有向边
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"` // owns / scheduled / mounts / references / endpoints / targets / routes / scales / protects
+}
+
+// ResourceGraph 工作负载或命名空间的资源关系图
+type ResourceGraph struct {
+	Nodes    []GraphNode `json:"nodes"`
+	Edges    []GraphEdge `json:"edges"`
+	Warnings []string    `json:"warnings,omitempty"` // 部分资源读取失败（如缺少权限）时的提示
+}
+
 // ServiceInfo Service信息
 type ServiceInfo struct {
 	K8sResource
@@ -165,3 +491,97 @@ type SecretInfo struct {
 	Type string            `json:"type"`
 	Data map[string]string `json:"data"` // base64 encoded
 }
+
+// WatchEvent 资源 watch 推送事件（WebSocket / SSE 共用）
+type WatchEvent struct {
+	Type            string      `json:"type"` // ADDED / MODIFIED / DELETED / BOOKMARK / ERROR
+	Object          interface{} `json:"object,omitempty"`
+	ResourceVersion string      `json:"resource_version,omitempty"`
+}
+
+// APIResourceInfo 集群可用资源类型（等价 kubectl api-resources）
+type APIResourceInfo struct {
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Resource   string   `json:"resource"`
+	Kind       string   `json:"kind"`
+	Namespaced bool     `json:"namespaced"`
+	Verbs      []string `json:"verbs"`
+	ShortNames []string `json:"short_names,omitempty"`
+}
+
+// ApplyOptions 多文档 apply 选项
+type ApplyOptions struct {
+	ContinueOnError bool   `json:"continue_on_error" form:"continue_on_error"` // 默认遇到第一个失败即停止，其余对象标记为 skipped
+	Namespace       string `json:"namespace" form:"namespace"`                 // 未指定 namespace 的命名空间级对象使用，缺省为 default
+	Force           bool   `json:"force" form:"force"`                         // 字段归属冲突时强制接管
+	DryRun          bool   `json:"dry_run" form:"dry_run"`                     // 服务端试运行（DryRun=All），不持久化任何对象
+	FieldManager    string `json:"-" form:"-"`                                 // server-side apply 的 fieldManager，由接口按当前用户填充
+	SkipExisting    bool   `json:"-" form:"-"`                                 // 已存在的对象不做修改（记为 exists），用于从备份恢复
+}
+
+// ApplyObjectResult 单个对象的 apply 结果
+type ApplyObjectResult struct {
+	APIVersion string          `json:"api_version"`
+	Kind       string          `json:"kind"`
+	Namespace  string          `json:"namespace,omitempty"`
+	Name       string          `json:"name"`
+	Action     string          `json:"action"` // created / configured / unchanged / exists / conflict / failed / skipped
+	Error      string          `json:"error,omitempty"`
+	Conflicts  []ApplyConflict `json:"conflicts,omitempty"`
+}
+
+// ApplyConflict server-side apply 的字段归属冲突：字段当前由其他 manager 以不同的值管理
+type ApplyConflict struct {
+	Manager string `json:"manager"` // 如 kube-controller-manager、kubectl-client-side-apply
+	Field   string `json:"field"`   // 如 .spec.replicas
+	Message string `json:"message"`
+}
+
+// ApplyResult 多文档 apply 结果（按实际执行顺序）
+type ApplyResult struct {
+	Results []ApplyObjectResult `json:"results"`
+	Summary map[string]int      `json:"summary"` // 各 action 的对象数
+	DryRun  bool                `json:"dry_run,omitempty"`
+}
+
+// KustomizeBuildResult kustomize build 的渲染结果
+type KustomizeBuildResult struct {
+	YAML    string           `json:"yaml"` // 多文档 YAML，可直接提交 /resources/apply
+	Objects []ManifestObject `json:"objects"`
+}
+
+// ManifestObject 清单中的对象标识
+type ManifestObject struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// ResourceDiff 单个对象线上状态与服务端 apply 试运行结果的差异（均已去掉 managedFields、status 等服务端字段）
+type ResourceDiff struct {
+	APIVersion string          `json:"api_version"`
+	Kind       string          `json:"kind"`
+	Namespace  string          `json:"namespace,omitempty"`
+	Name       string          `json:"name"`
+	Action     string          `json:"action"`         // created / configured / unchanged / conflict / failed
+	Diff       string          `json:"diff,omitempty"` // YAML 形式的 unified diff
+	Changes    []FieldChange   `json:"changes,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Conflicts  []ApplyConflict `json:"conflicts,omitempty"`
+}
+
+// FieldChange 结构化的字段变更
+type FieldChange struct {
+	Path string      `json:"path"` // 如 .spec.replicas、.spec.template.spec.containers[0].image
+	Op   string      `json:"op"`   // add / remove / replace
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// DiffResult 多文档 diff 结果（按 apply 顺序）
+type DiffResult struct {
+	Results []ResourceDiff `json:"results"`
+	Summary map[string]int `json:"summary"` // 各 action 的对象数
+}
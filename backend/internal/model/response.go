@@ -19,6 +19,54 @@ type PageResponse struct {
 	Page     int         `json:"page"`
 	PageSize int         `json:"page_size"`
 	Items    interface{} `json:"items"`
+	// Continue K8s 分块续取令牌（limit 模式），为空表示已取完
+	Continue string `json:"continue,omitempty"`
+	// RemainingItemCount K8s 分块模式下 apiserver 估算的剩余条数（可能缺省）
+	RemainingItemCount *int64 `json:"remaining_item_count,omitempty"`
+}
+
+// TotalUnknown 总数未知：分块模式下名称搜索只作用于当前块，无法得知全部匹配条数
+const TotalUnknown int64 = -1
+
+// DefaultPageSize 列表偏移分页的默认每页条数
+const DefaultPageSize = 50
+
+// ListQuery K8s 列表查询参数：选择器透传、名称搜索、排序与分页。
+// 分页二选一：page/page_size 为偏移分页（可走缓存，可全局排序）；
+// limit/continue 为 K8s 原生分块（直连 apiserver，排序仅在当前块内生效）。
+type ListQuery struct {
+	LabelSelector string `form:"label_selector"`
+	FieldSelector string `form:"field_selector"`
+	Search        string `form:"search"` // 名称子串，忽略大小写
+	SortBy        string `form:"sort_by" binding:"omitempty,oneof=name namespace created"`
+	Order         string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	PageSize      int    `form:"page_size" binding:"omitempty,min=1,max=500"`
+	Limit         int64  `form:"limit" binding:"omitempty,min=1"`
+	Continue      string `form:"continue"`
+	Fresh         bool   `form:"fresh"` // 绕过 informer 缓存
+}
+
+// Paged 是否请求了分页；未分页时接口仍返回原有的数组结构，兼容旧调用方
+func (q ListQuery) Paged() bool {
+	return q.Page > 0 || q.PageSize > 0 || q.Limit > 0 || q.Continue != ""
+}
+
+// PageBounds 归一化后的页码与每页条数（仅传 page 时每页默认 DefaultPageSize 条）
+func (q ListQuery) PageBounds() (int, int) {
+	page, size := q.Page, q.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = DefaultPageSize
+	}
+	return page, size
+}
+
+// Chunked 是否为 K8s 原生分块模式
+func (q ListQuery) Chunked() bool {
+	return q.Limit > 0 || q.Continue != ""
 }
 
 // SuccessResponse 成功响应
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/kube-admin/kube-admin/backend/internal/api"
 	"github.com/kube-admin/kube-admin/backend/internal/middleware"
@@ -8,8 +10,19 @@ import (
 	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
 )
 
-// SetupRouter 设置路由
-func SetupRouter(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) *gin.Engine {
+const (
+	// requestTimeout 普通 K8s 接口的单次操作期限
+	requestTimeout = 30 * time.Second
+	// longRequestTimeout Helm 安装/升级/回滚、多对象 apply、kustomize 与命名空间导出等耗时操作的期限，
+	// 中途取消可能留下半完成的 release 或 apply
+	longRequestTimeout = 5 * time.Minute
+)
+
+// WriteTimeout http.Server 写超时：须大于所有路由的请求期限，留出写响应的余量
+const WriteTimeout = longRequestTimeout + 30*time.Second
+
+// SetupRouter 设置路由。backupService 的定时调度由调用方随服务器生命周期启动与停止
+func SetupRouter(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager, backupService *service.BackupService) *gin.Engine {
 	r := gin.Default()
 
 	// 中间件
@@ -29,19 +42,28 @@ func SetupRouter(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) *gin.Eng
 	clusterService := service.NewClusterService()
 	userService := service.NewUserService()
 	auditService := service.NewAuditService()
+	agentService := service.NewAgentService()
+	fleetService := service.NewFleetService(defaultK8sClient, k8sManager)
 
 	// 创建API层
 	authAPI := api.NewAuthAPI(userService)
-	clusterAPI := api.NewClusterAPI(clusterService)
+	clusterAPI := api.NewClusterAPI(clusterService, k8sManager)
 	userAPI := api.NewUserAPI(userService)
 	auditAPI := api.NewAuditAPI(auditService)
 	eventAPI := api.NewEventAPI()
 	resourceAPI := api.NewResourceAPI()
+	agentAPI := api.NewAgentAPI(agentService, k8sManager)
+	fleetAPI := api.NewFleetAPI(fleetService)
+	backupAPI := api.NewBackupAPI(backupService)
 
 	// 公开路由
 	public := r.Group("/api/v1")
 	{
 		public.POST("/auth/login", authAPI.Login)
+
+		// agent 接入（集群内 agent 调用，凭 join token / agent 凭据鉴权，不走 JWT）
+		public.POST("/agent/register", agentAPI.Register)
+		public.GET("/agent/connect", agentAPI.Connect)
 	}
 
 	// 需要认证的路由
@@ -70,15 +92,46 @@ func SetupRouter(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) *gin.Eng
 			adminGroup.DELETE("/clusters/:id", clusterAPI.DeleteCluster)
 			adminGroup.POST("/clusters/test-connection", clusterAPI.TestConnection)
 			adminGroup.POST("/clusters/:id/test-connection", clusterAPI.TestConnectionByID)
+			// agent 接入：签发一次性 join token、查询隧道状态
+			adminGroup.POST("/clusters/:id/join-token", agentAPI.CreateJoinToken)
+			adminGroup.GET("/clusters/:id/agent", agentAPI.GetAgentStatus)
+			// 多集群总览与跨集群搜索（各集群并发处理，不依赖 cluster_id；与集群列表同为 admin）
+			adminGroup.GET("/fleet/overview", fleetAPI.GetOverview)
+			adminGroup.GET("/search", fleetAPI.Search)
 
 			// 审计日志查询（仅 admin）
 			adminGroup.GET("/audit/logs", auditAPI.ListAuditLogs)
+
+			// 命名空间备份与恢复（归档存于 BACKUP_DIR 或 S3，cluster_id 指定集群）。
+			// 备份、恢复与立即执行计划在后台运行，返回任务；列表需逐个读取元数据、下载需转发整个归档，使用较长期限
+			backupGroup := adminGroup.Group("")
+			backupGroup.Use(middleware.RequestTimeout(longRequestTimeout))
+			backupGroup.GET("/backups", backupAPI.ListBackups)
+			backupGroup.POST("/backups", backupAPI.CreateBackup)
+			backupGroup.GET("/backups/:name", backupAPI.GetBackup)
+			backupGroup.GET("/backups/:name/download", backupAPI.DownloadBackup)
+			backupGroup.DELETE("/backups/:name", backupAPI.DeleteBackup)
+			backupGroup.POST("/backups/:name/restore", backupAPI.RestoreBackup)
+			backupGroup.GET("/backup-schedules", backupAPI.ListSchedules)
+			backupGroup.POST("/backup-schedules", backupAPI.CreateSchedule)
+			backupGroup.PUT("/backup-schedules/:id", backupAPI.UpdateSchedule)
+			backupGroup.DELETE("/backup-schedules/:id", backupAPI.DeleteSchedule)
+			backupGroup.POST("/backup-schedules/:id/run", backupAPI.RunSchedule)
+			backupGroup.GET("/backup-jobs", backupAPI.ListJobs)
+			backupGroup.GET("/backup-jobs/:id", backupAPI.GetJob)
 		}
 
 		// 创建需要集群参数的API组
+		clusterMiddleware := middleware.ClusterMiddleware(defaultK8sClient, k8sManager)
 		k8sGroup := protected.Group("")
-		k8sGroup.Use(middleware.WriteAuth()) // 写操作需 admin/operator/user 角色
-		k8sGroup.Use(middleware.ClusterMiddleware(defaultK8sClient, k8sManager))
+		k8sGroup.Use(middleware.WriteAuth())                    // 写操作需 admin/operator/user 角色
+		k8sGroup.Use(middleware.RequestTimeout(requestTimeout)) // 单次操作期限（须小于 http.Server WriteTimeout）
+		k8sGroup.Use(clusterMiddleware)
+		// 耗时操作单独分组，使用更长的期限
+		longGroup := protected.Group("")
+		longGroup.Use(middleware.WriteAuth())
+		longGroup.Use(middleware.RequestTimeout(longRequestTimeout))
+		longGroup.Use(clusterMiddleware)
 		{
 			// Dashboard
 			dashboardAPI := api.NewDashboardAPI(nil) // 将在中间件中注入正确的客户端
@@ -87,15 +140,40 @@ func SetupRouter(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) *gin.Eng
 			// Event
 			k8sGroup.GET("/events", eventAPI.ListEvents)
 
+			// 集群可用资源类型（api-resources）
+			k8sGroup.GET("/api-resources", resourceAPI.APIResources)
+
 			// 通用资源管理（任意 GVR：list/get/delete/apply/patch）
 			k8sGroup.GET("/resources", resourceAPI.List)
+			// 实时 watch（WebSocket/SSE，内置类型或任意 GVR）
+			k8sGroup.GET("/resources/watch", resourceAPI.Watch)
 			k8sGroup.GET("/resources/:name", resourceAPI.Get)
 			k8sGroup.DELETE("/resources/:name", resourceAPI.Delete)
-			k8sGroup.POST("/resources/apply", resourceAPI.Apply)
+			longGroup.POST("/resources/apply", resourceAPI.Apply)
+			// 变更预览：线上对象与 server-side apply 试运行结果的差异
+			longGroup.POST("/resources/diff", resourceAPI.Diff)
 			k8sGroup.PATCH("/resources/:name", resourceAPI.Patch)
 			// 通用 workload 扩缩容/滚动重启（Deployment/StatefulSet/DaemonSet/ReplicaSet）
 			k8sGroup.PUT("/resources/:name/scale", resourceAPI.ScaleResource)
 			k8sGroup.PUT("/resources/:name/restart", resourceAPI.RestartResource)
+			// rollout 暂停/恢复（Deployment）与滚动更新参数（Deployment/StatefulSet/DaemonSet）
+			k8sGroup.PUT("/resources/:name/pause", resourceAPI.PauseResource)
+			k8sGroup.PUT("/resources/:name/resume", resourceAPI.ResumeResource)
+			k8sGroup.GET("/resources/:name/strategy", resourceAPI.GetRolloutStrategy)
+			k8sGroup.PUT("/resources/:name/strategy", resourceAPI.UpdateRolloutStrategy)
+			// 结构化修改容器镜像/环境变量/资源/探针（含 Pod 模板的内置工作负载）
+			k8sGroup.GET("/resources/:name/containers", resourceAPI.GetContainers)
+			k8sGroup.PUT("/resources/:name/containers", resourceAPI.UpdateContainers)
+			// 归属链（沿 ownerReferences 到顶层控制器，任意 GVR）
+			k8sGroup.GET("/resources/:name/owners", resourceAPI.GetOwners)
+			// 导出可直接 apply 的清理后 YAML（单个对象或整个命名空间，多文档 YAML 或 zip）
+			k8sGroup.GET("/resources/:name/export", resourceAPI.Export)
+			longGroup.GET("/namespaces/:name/export", resourceAPI.ExportNamespace)
+
+			// 工作负载 rollout（kind: deployments/statefulsets/daemonsets）
+			workloadAPI := api.NewWorkloadAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/workloads/:kind/:name/rollout-status", workloadAPI.RolloutStatus)
+			k8sGroup.GET("/workloads/:kind/:name/rollout-status/watch", workloadAPI.WatchRolloutStatus)
 
 			// Namespace
 			namespaceAPI := api.NewNamespaceAPI(nil) // 将在中间件中注入正确的客户端
@@ -131,8 +209,76 @@ func SetupRouter(defaultK8sClient *k8s.Client, k8sManager *k8s.Manager) *gin.Eng
 			k8sGroup.DELETE("/deployments/:name", deploymentAPI.DeleteDeployment)
 			k8sGroup.PUT("/deployments/:name/scale", deploymentAPI.ScaleDeployment)
 			k8sGroup.PUT("/deployments/:name/restart", deploymentAPI.RestartDeployment)
+			k8sGroup.GET("/deployments/:name/revisions", deploymentAPI.ListRevisions)
+			k8sGroup.GET("/deployments/:name/revisions/diff", deploymentAPI.DiffRevisions)
+			k8sGroup.POST("/deployments/:name/rollback", deploymentAPI.RollbackDeployment)
 			k8sGroup.POST("/deployments/yaml", deploymentAPI.CreateDeploymentFromYaml)
 
+			// StatefulSet
+			statefulSetAPI := api.NewStatefulSetAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/statefulsets", statefulSetAPI.ListStatefulSets)
+			k8sGroup.GET("/statefulsets/:name", statefulSetAPI.GetStatefulSet)
+			k8sGroup.DELETE("/statefulsets/:name", statefulSetAPI.DeleteStatefulSet)
+			k8sGroup.PUT("/statefulsets/:name/scale", statefulSetAPI.ScaleStatefulSet)
+			k8sGroup.PUT("/statefulsets/:name/restart", statefulSetAPI.RestartStatefulSet)
+			k8sGroup.POST("/statefulsets/yaml", statefulSetAPI.CreateStatefulSetFromYaml)
+
+			// DaemonSet
+			daemonSetAPI := api.NewDaemonSetAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/daemonsets", daemonSetAPI.ListDaemonSets)
+			k8sGroup.GET("/daemonsets/:name", daemonSetAPI.GetDaemonSet)
+			k8sGroup.DELETE("/daemonsets/:name", daemonSetAPI.DeleteDaemonSet)
+			k8sGroup.PUT("/daemonsets/:name/restart", daemonSetAPI.RestartDaemonSet)
+			k8sGroup.POST("/daemonsets/yaml", daemonSetAPI.CreateDaemonSetFromYaml)
+
+			// Job
+			jobAPI := api.NewJobAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/jobs", jobAPI.ListJobs)
+			k8sGroup.GET("/jobs/:name", jobAPI.GetJob)
+			k8sGroup.DELETE("/jobs/:name", jobAPI.DeleteJob)
+			k8sGroup.POST("/jobs/:name/retry", jobAPI.RetryJob)
+			k8sGroup.POST("/jobs/cleanup", jobAPI.CleanupJobs)
+			k8sGroup.POST("/jobs/yaml", jobAPI.CreateJobFromYaml)
+
+			// CronJob
+			cronJobAPI := api.NewCronJobAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/cronjobs", cronJobAPI.ListCronJobs)
+			k8sGroup.GET("/cronjobs/:name", cronJobAPI.GetCronJob)
+			k8sGroup.DELETE("/cronjobs/:name", cronJobAPI.DeleteCronJob)
+			k8sGroup.POST("/cronjobs/:name/trigger", cronJobAPI.TriggerCronJob)
+			k8sGroup.PUT("/cronjobs/:name/suspend", cronJobAPI.SuspendCronJob)
+			k8sGroup.PUT("/cronjobs/:name/resume", cronJobAPI.ResumeCronJob)
+			k8sGroup.POST("/cronjobs/yaml", cronJobAPI.CreateCronJobFromYaml)
+
+			// HorizontalPodAutoscaler（autoscaling/v2）
+			hpaAPI := api.NewHPAAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/hpas", hpaAPI.ListHPAs)
+			k8sGroup.GET("/hpas/:name", hpaAPI.GetHPA)
+			k8sGroup.POST("/hpas", hpaAPI.CreateHPA)
+			k8sGroup.PUT("/hpas/:name", hpaAPI.UpdateHPA)
+			k8sGroup.DELETE("/hpas/:name", hpaAPI.DeleteHPA)
+
+			// 资源关系图
+			graphAPI := api.NewGraphAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/graph", graphAPI.GetGraph)
+
+			// Helm release（与 helm CLI 共用 Secret 存储）
+			helmAPI := api.NewHelmAPI(nil) // 将在中间件中注入正确的客户端
+			k8sGroup.GET("/helm/releases", helmAPI.ListReleases)
+			k8sGroup.GET("/helm/releases/:name", helmAPI.GetRelease)
+			longGroup.PUT("/helm/releases/:name", helmAPI.InstallOrUpgrade)
+			longGroup.DELETE("/helm/releases/:name", helmAPI.Uninstall)
+			k8sGroup.GET("/helm/releases/:name/history", helmAPI.GetHistory)
+			k8sGroup.GET("/helm/releases/:name/values", helmAPI.GetValues)
+			k8sGroup.GET("/helm/releases/:name/diff", helmAPI.DiffRevisions)
+			longGroup.POST("/helm/releases/:name/rollback", helmAPI.Rollback)
+			k8sGroup.GET("/helm/charts", helmAPI.ListCharts)
+
+			// Kustomize：上传压缩包或 KUSTOMIZE_DIR 内的目录，进程内 build 后预览或 apply
+			kustomizeAPI := api.NewKustomizeAPI()
+			longGroup.POST("/kustomize/build", kustomizeAPI.Build)
+			longGroup.POST("/kustomize/apply", kustomizeAPI.Apply)
+
 			// Service
 			serviceAPI := api.NewServiceAPI(nil) // 将在中间件中注入正确的客户端
 			k8sGroup.GET("/services", serviceAPI.ListServices)
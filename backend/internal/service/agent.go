@@ -0,0 +1,153 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/database"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+)
+
+const (
+	defaultJoinTokenTTL = 24 * time.Hour
+	maxJoinTokenTTL     = 7 * 24 * time.Hour
+)
+
+// errInvalidAgentCredential agent 凭据无效（格式错误、集群不存在或摘要不匹配统一返回，避免探测）
+var errInvalidAgentCredential = errors.New("无效的 agent 凭据")
+
+// AgentService agent 接入服务：签发一次性 join token、agent 注册换取长期凭据、隧道连接鉴权
+type AgentService struct{}
+
+// NewAgentService 创建 agent 服务实例
+func NewAgentService() *AgentService { return &AgentService{} }
+
+// CreateJoinToken 为 agent 模式集群签发一次性 join token，明文仅返回这一次
+func (s *AgentService) CreateJoinToken(clusterID uint, ttlHours int, createdBy string) (*model.JoinTokenResponse, error) {
+	var cluster model.Cluster
+	if err := database.DB.First(&cluster, clusterID).Error; err != nil {
+		return nil, fmt.Errorf("集群不存在: %v", err)
+	}
+	if !cluster.IsAgent() {
+		return nil, fmt.Errorf("集群 %s 不是 agent 接入模式", cluster.Name)
+	}
+
+	ttl := defaultJoinTokenTTL
+	if ttlHours > 0 {
+		ttl = time.Duration(ttlHours) * time.Hour
+	}
+	if ttl > maxJoinTokenTTL {
+		ttl = maxJoinTokenTTL
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	record := model.ClusterJoinToken{
+		ClusterID: clusterID,
+		TokenHash: hashSecret(token),
+		CreatedBy: createdBy,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := database.DB.Create(&record).Error; err != nil {
+		return nil, err
+	}
+	return &model.JoinTokenResponse{ClusterID: clusterID, Token: token, ExpiresAt: record.ExpiresAt}, nil
+}
+
+// Register agent 凭 join token 注册：作废 token，生成新的长期凭据（覆盖该集群旧凭据）。
+// 返回凭据格式 "<cluster_id>.<secret>"，服务端只保存 secret 的摘要。
+func (s *AgentService) Register(token, ip string) (*model.AgentRegisterResponse, error) {
+	var record model.ClusterJoinToken
+	err := database.DB.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashSecret(token), time.Now()).
+		First(&record).Error
+	if err != nil {
+		return nil, fmt.Errorf("join token 无效、已使用或已过期")
+	}
+
+	// 条件更新保证一次性：并发注册只有一个能把 used_at 从 NULL 改掉
+	now := time.Now()
+	res := database.DB.Model(&model.ClusterJoinToken{}).
+		Where("id = ? AND used_at IS NULL", record.ID).
+		Update("used_at", now)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("join token 无效、已使用或已过期")
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	// UpdateColumn 跳过 BeforeSave 钩子，避免重复加密其他凭据字段
+	if err := database.DB.Model(&model.Cluster{}).Where("id = ?", record.ClusterID).
+		UpdateColumn("agent_credential", hashSecret(secret)).Error; err != nil {
+		return nil, err
+	}
+
+	// 注册走公开路由（不经 AuditMiddleware），单独落审计
+	_ = database.DB.Create(&model.AuditLog{
+		Username:  "agent",
+		Method:    "POST",
+		Path:      fmt.Sprintf("/api/v1/agent/register (cluster %d)", record.ClusterID),
+		Status:    200,
+		IP:        ip,
+		CreatedAt: now,
+	}).Error
+
+	return &model.AgentRegisterResponse{
+		ClusterID:  record.ClusterID,
+		Credential: fmt.Sprintf("%d.%s", record.ClusterID, secret),
+	}, nil
+}
+
+// Authenticate 校验 agent 长期凭据，返回对应集群
+func (s *AgentService) Authenticate(credential string) (*model.Cluster, error) {
+	idStr, secret, ok := strings.Cut(credential, ".")
+	if !ok || secret == "" {
+		return nil, errInvalidAgentCredential
+	}
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return nil, errInvalidAgentCredential
+	}
+	var cluster model.Cluster
+	if err := database.DB.First(&cluster, uint(id)).Error; err != nil {
+		return nil, errInvalidAgentCredential
+	}
+	if !cluster.IsAgent() || cluster.AgentCredential == "" ||
+		subtle.ConstantTimeCompare([]byte(cluster.AgentCredential), []byte(hashSecret(secret))) != 1 {
+		return nil, errInvalidAgentCredential
+	}
+	return &cluster, nil
+}
+
+// NewSessionToken 生成隧道会话令牌
+func (s *AgentService) NewSessionToken() (string, error) {
+	return randomHex(32)
+}
+
+// hashSecret 高熵随机串直接用 SHA-256 摘要存储即可，无需 bcrypt
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex 生成 n 字节随机数的十六进制串
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/config"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// apply 结果中的对象动作
+const (
+	applyCreated    = "created"
+	applyConfigured = "configured"
+	applyUnchanged  = "unchanged"
+	applyExists     = "exists"
+	applyConflict   = "conflict"
+	applyFailed     = "failed"
+	applySkipped    = "skipped"
+)
+
+// applyKindOrder 依赖顺序（与 Helm 安装顺序一致，CRD 提前到 Namespace 之后），未列出的类型（如自定义资源）排在最后
+var applyKindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+}
+
+// conflictManagerPattern 从冲突原因中取出 manager，如 `conflict with "kube-controller-manager" using apps/v1`
+var conflictManagerPattern = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// crdWaitTimeout 同批次刚创建的 CRD 出现在 discovery 中的最长等待时间
+const crdWaitTimeout = 10 * time.Second
+
+// ApplyFromYAML 解析 YAML（支持 --- 分隔的多文档与 List 类型）并按依赖顺序逐个创建或更新，
+// 返回每个对象的结果。YAML 无法解析时不做任何修改直接报错。
+func (s *ResourceService) ApplyFromYAML(ctx context.Context, yamlStr string, opts model.ApplyOptions) (*model.ApplyResult, error) {
+	objs, err := decodeManifests(yamlStr)
+	if err != nil {
+		return nil, err
+	}
+	return s.ApplyObjects(ctx, objs, opts), nil
+}
+
+// ApplyObjects 按依赖顺序以 server-side apply 提交一组对象。默认遇到失败或冲突即停止（其余标记为 skipped），
+// ContinueOnError 时继续处理后续对象。DryRun 时不会真正创建 Namespace/CRD，依赖它们的后续对象可能报不存在。
+func (s *ResourceService) ApplyObjects(ctx context.Context, objs []*unstructured.Unstructured, opts model.ApplyOptions) *model.ApplyResult {
+	sortManifests(objs)
+	result := &model.ApplyResult{Results: make([]model.ApplyObjectResult, 0, len(objs)), Summary: map[string]int{}, DryRun: opts.DryRun}
+	stopped := false
+	crdApplied := false
+	for _, obj := range objs {
+		r := model.ApplyObjectResult{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			Action:     applySkipped,
+		}
+		if !stopped {
+			action, applied, err := s.applyObject(ctx, obj, opts, crdApplied)
+			r.Action = action
+			if applied != nil {
+				r.Namespace, r.Name = applied.GetNamespace(), applied.GetName()
+			}
+			if err != nil {
+				r.Error = err.Error()
+				if r.Conflicts = applyConflicts(err); len(r.Conflicts) > 0 {
+					r.Action = applyConflict
+				}
+				stopped = !opts.ContinueOnError
+			} else if obj.GetKind() == "CustomResourceDefinition" {
+				crdApplied = true
+			}
+		}
+		result.Results = append(result.Results, r)
+		result.Summary[r.Action]++
+	}
+	return result
+}
+
+// applyObject 以 server-side apply 提交对象，只声明 YAML 中写出的字段，其他 manager 管理的字段（如 HPA 调整的副本数）不受影响。
+// 字段与其他 manager 的值冲突时失败，Force 时强制接管。提交前先读取线上对象以区分 created/configured/unchanged；
+// DryRun 时服务端不写入、resourceVersion 不变，改为比较去掉易变字段后的内容。
+func (s *ResourceService) applyObject(ctx context.Context, obj *unstructured.Unstructured, opts model.ApplyOptions, waitCRD bool) (string, *unstructured.Unstructured, error) {
+	existing, applied, err := s.serverSideApply(ctx, obj, opts, waitCRD)
+	switch {
+	case err != nil:
+		return applyFailed, nil, err
+	case existing == nil:
+		return applyCreated, applied, nil
+	case applied == nil:
+		return applyExists, existing, nil
+	case opts.DryRun && reflect.DeepEqual(diffableObject(existing), diffableObject(applied)):
+		return applyUnchanged, applied, nil
+	case !opts.DryRun && applied.GetResourceVersion() == existing.GetResourceVersion():
+		return applyUnchanged, applied, nil
+	}
+	return applyConfigured, applied, nil
+}
+
+// serverSideApply 读取线上对象（不存在时为 nil）后提交 apply，返回提交前的线上对象与 apply 结果；
+// SkipExisting 且对象已存在时不提交，apply 结果为 nil
+func (s *ResourceService) serverSideApply(ctx context.Context, obj *unstructured.Unstructured, opts model.ApplyOptions, waitCRD bool) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	iface, err := s.applyTarget(ctx, obj, opts.Namespace, waitCRD)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 只有 generateName 时无法 apply（apply 需要名称），直接创建
+	if obj.GetName() == "" {
+		if obj.GetGenerateName() == "" {
+			return nil, nil, fmt.Errorf("缺少 metadata.name")
+		}
+		created, err := iface.Create(ctx, obj, metav1.CreateOptions{FieldManager: opts.FieldManager, DryRun: dryRunOption(opts.DryRun)})
+		return nil, created, err
+	}
+
+	existing, err := iface.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		existing = nil
+	case err != nil:
+		return nil, nil, err
+	case opts.SkipExisting:
+		return existing, nil, nil
+	}
+
+	data, err := applyPatchData(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	force := opts.Force
+	applied, err := iface.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: opts.FieldManager,
+		Force:        &force,
+		DryRun:       dryRunOption(opts.DryRun),
+	})
+	return existing, applied, err
+}
+
+// applyPatchData 序列化为 apply patch。粘贴的 kubectl get -o yaml 输出带有服务端字段：managedFields 会被 apply 拒绝，
+// 过期的 resourceVersion/uid 会作为前置条件导致冲突，status 与删除标记不应由 apply 提交，一并去掉（同 diffableObject）
+func applyPatchData(obj *unstructured.Unstructured) ([]byte, error) {
+	out := &unstructured.Unstructured{Object: diffableObject(obj)}
+	if metadata, ok := out.Object["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "deletionTimestamp")
+		delete(metadata, "deletionGracePeriodSeconds")
+	}
+	return out.MarshalJSON()
+}
+
+// applyConflicts 从 apply 的 409 错误中取出字段归属冲突
+func applyConflicts(err error) []model.ApplyConflict {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || !apierrors.IsConflict(err) {
+		return nil
+	}
+	details := status.Status().Details
+	if details == nil {
+		return nil
+	}
+	var conflicts []model.ApplyConflict
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		c := model.ApplyConflict{Field: cause.Field, Message: cause.Message}
+		if m := conflictManagerPattern.FindStringSubmatch(cause.Message); m != nil {
+			c.Manager = m[1]
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts
+}
+
+// FieldManagerFor 返回用户的 server-side apply fieldManager：<前缀>:<用户名>
+func FieldManagerFor(username string) string {
+	prefix := "kube-admin"
+	if config.App != nil && config.App.FieldManager != "" {
+		prefix = config.App.FieldManager
+	}
+	if username == "" {
+		return prefix
+	}
+	return prefix + ":" + username
+}
+
+// applyTarget 解析对象的 REST 资源并补全 namespace（命名空间级对象未指定时用 defaultNamespace，缺省 default）
+func (s *ResourceService) applyTarget(ctx context.Context, obj *unstructured.Unstructured, defaultNamespace string, waitCRD bool) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return nil, fmt.Errorf("缺少 apiVersion 或 kind")
+	}
+	mapping, err := s.applyMapping(ctx, gvk, waitCRD)
+	if err != nil {
+		return nil, fmt.Errorf("无法识别资源类型 %s: %w", gvk.String(), err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		obj.SetNamespace("")
+		return s.k8sClient.DynamicClient.Resource(mapping.Resource), nil
+	}
+	if obj.GetNamespace() == "" {
+		if defaultNamespace == "" {
+			defaultNamespace = "default"
+		}
+		obj.SetNamespace(defaultNamespace)
+	}
+	return s.k8sClient.DynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+}
+
+// applyMapping 解析 REST 映射；同批次刚创建了 CRD 时，未知类型可能尚未出现在 discovery 中，短暂轮询等待
+func (s *ResourceService) applyMapping(ctx context.Context, gvk schema.GroupVersionKind, waitCRD bool) (*meta.RESTMapping, error) {
+	mapping, err := s.k8sClient.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err == nil || !waitCRD || !meta.IsNoMatchError(err) {
+		return mapping, err
+	}
+	deadline := time.Now().Add(crdWaitTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+		if mapping, err = s.k8sClient.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil || !meta.IsNoMatchError(err) {
+			return mapping, err
+		}
+	}
+	return nil, err
+}
+
+// decodeManifests 解析多文档 YAML/JSON，跳过空文档，展开 List 类型（如 kubectl get -o yaml 的输出）
+func decodeManifests(yamlStr string) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(yamlStr), 4096)
+	var objs []*unstructured.Unstructured
+	for doc := 1; ; doc++ {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("解析第 %d 个 YAML 文档失败: %w", doc, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if !obj.IsList() {
+			objs = append(objs, obj)
+			continue
+		}
+		list, err := obj.ToList()
+		if err != nil {
+			return nil, fmt.Errorf("解析第 %d 个 YAML 文档失败: %w", doc, err)
+		}
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("YAML 中没有任何资源对象")
+	}
+	return objs, nil
+}
+
+// sortManifests 按 applyKindOrder 稳定排序，同类对象保持原顺序
+func sortManifests(objs []*unstructured.Unstructured) {
+	rank := make(map[string]int, len(applyKindOrder))
+	for i, kind := range applyKindOrder {
+		rank[kind] = i
+	}
+	order := func(obj *unstructured.Unstructured) int {
+		if r, ok := rank[obj.GetKind()]; ok {
+			return r
+		}
+		return len(applyKindOrder)
+	}
+	sort.SliceStable(objs, func(i, j int) bool { return order(objs[i]) < order(objs[j]) })
+}
+
+// ApplyResultError 汇总失败的对象作为整体错误信息，全部成功时返回 nil
+func ApplyResultError(result *model.ApplyResult) error {
+	var failed []string
+	for _, r := range result.Results {
+		if r.Action == applyFailed || r.Action == applyConflict {
+			failed = append(failed, fmt.Sprintf("%s %s: %s", r.Kind, r.Name, r.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d 个对象 apply 失败: %s", len(failed), strings.Join(failed, "; "))
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestDecodeManifests 解析多文档 YAML：展开 List、跳过空文档，出错时指出第几个文档
+func TestDecodeManifests(t *testing.T) {
+	manifest := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+# 只有注释的文档
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: web
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+`
+	objs, err := decodeManifests(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, obj := range objs {
+		kinds = append(kinds, obj.GetKind())
+	}
+	if got := strings.Join(kinds, ","); got != "Deployment,Service,ConfigMap,Namespace" {
+		t.Errorf("decoded kinds = %s", got)
+	}
+
+	sortManifests(objs)
+	kinds = kinds[:0]
+	for _, obj := range objs {
+		kinds = append(kinds, obj.GetKind())
+	}
+	if got := strings.Join(kinds, ","); got != "Namespace,ConfigMap,Service,Deployment" {
+		t.Errorf("sorted kinds = %s", got)
+	}
+
+	if _, err := decodeManifests("---\n# empty\n"); err == nil {
+		t.Error("expected error for manifest without objects")
+	}
+	if _, err := decodeManifests("kind: A\n---\nkind: [\n"); err == nil || !strings.Contains(err.Error(), "第 2 个") {
+		t.Errorf("expected error on document 2, got %v", err)
+	}
+}
+
+// TestSortManifestsCRDFirst Namespace 与 CRD 排在自定义资源之前
+func TestSortManifestsCRDFirst(t *testing.T) {
+	objs, err := decodeManifests(`
+apiVersion: example.com/v1
+kind: Widget
+metadata: {name: a}
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata: {name: widgets.example.com}
+---
+apiVersion: v1
+kind: Namespace
+metadata: {name: shop}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortManifests(objs)
+	if objs[0].GetKind() != "Namespace" || objs[1].GetKind() != "CustomResourceDefinition" || objs[2].GetKind() != "Widget" {
+		t.Errorf("unexpected order %s, %s, %s", objs[0].GetKind(), objs[1].GetKind(), objs[2].GetKind())
+	}
+}
+
+// TestApplyConflicts 从 409 错误中提取字段管理器冲突
+func TestApplyConflicts(t *testing.T) {
+	err := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   409,
+		Reason: metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{
+			{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kube-controller-manager" using apps/v1`, Field: ".spec.replicas"},
+			{Type: metav1.CauseTypeFieldValueInvalid, Message: "ignored"},
+		}},
+	}}
+	conflicts := applyConflicts(fmt.Errorf("wrapped: %w", err))
+	if len(conflicts) != 1 || conflicts[0].Manager != "kube-controller-manager" || conflicts[0].Field != ".spec.replicas" {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+	if applyConflicts(errors.New("boom")) != nil {
+		t.Error("plain error should have no conflicts")
+	}
+}
+
+// kubectlGetDeployment kubectl get deployment web -o yaml 的原样输出
+const kubectlGetDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    deployment.kubernetes.io/revision: "3"
+  creationTimestamp: "2026-09-01T08:12:45Z"
+  generation: 5
+  labels:
+    app: web
+  managedFields:
+  - apiVersion: apps/v1
+    fieldsType: FieldsV1
+    fieldsV1:
+      f:spec:
+        f:replicas: {}
+    manager: kubectl-client-side-apply
+    operation: Update
+    time: "2026-09-01T08:12:45Z"
+  name: web
+  namespace: shop
+  resourceVersion: "184467"
+  uid: 6f1c2a9e-3b7d-4c55-9d0e-1a2b3c4d5e6f
+spec:
+  progressDeadlineSeconds: 600
+  replicas: 2
+  revisionHistoryLimit: 10
+  selector:
+    matchLabels:
+      app: web
+  strategy:
+    rollingUpdate:
+      maxSurge: 25%
+      maxUnavailable: 25%
+    type: RollingUpdate
+  template:
+    metadata:
+      creationTimestamp: null
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: nginx:1.27
+        imagePullPolicy: IfNotPresent
+        name: nginx
+        resources: {}
+        terminationMessagePath: /dev/termination-log
+        terminationMessagePolicy: File
+      dnsPolicy: ClusterFirst
+      restartPolicy: Always
+      schedulerName: default-scheduler
+      securityContext: {}
+      terminationGracePeriodSeconds: 30
+status:
+  availableReplicas: 2
+  conditions:
+  - lastTransitionTime: "2026-09-01T08:12:50Z"
+    lastUpdateTime: "2026-09-01T08:12:50Z"
+    message: Deployment has minimum availability.
+    reason: MinimumReplicasAvailable
+    status: "True"
+    type: Available
+  observedGeneration: 5
+  readyReplicas: 2
+  replicas: 2
+  updatedReplicas: 2
+`
+
+// TestApplyPatchDataStripsServerFields apply 的 patch 去掉 status 与服务端元数据，不修改原对象
+func TestApplyPatchDataStripsServerFields(t *testing.T) {
+	objs, err := decodeManifests(kubectlGetDeployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := applyPatchData(objs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var patch map[string]interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := patch["status"]; ok {
+		t.Error("status not stripped")
+	}
+	metadata := patch["metadata"].(map[string]interface{})
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation"} {
+		if _, ok := metadata[field]; ok {
+			t.Errorf("metadata.%s not stripped", field)
+		}
+	}
+	if metadata["name"] != "web" || metadata["namespace"] != "shop" {
+		t.Errorf("identity lost: %v", metadata)
+	}
+	if spec := patch["spec"].(map[string]interface{}); spec["replicas"] != float64(2) {
+		t.Errorf("spec changed: %v", spec)
+	}
+	if len(objs[0].GetManagedFields()) != 1 || objs[0].GetResourceVersion() != "184467" {
+		t.Error("original object should be left untouched")
+	}
+	if got := FieldManagerFor("alice"); got != "kube-admin:alice" {
+		t.Errorf("FieldManagerFor = %s", got)
+	}
+}
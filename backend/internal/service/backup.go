@@ -0,0 +1,866 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/database"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	"github.com/robfig/cron/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// 备份在存储中的文件：<name>.tar.gz 为归档，<name>.json 为元数据（最后写入，存在即表示备份完整）
+const (
+	backupArchiveSuffix = ".tar.gz"
+	backupMetaSuffix    = ".json"
+)
+
+// 归档内的布局：backup.json 元数据，resources/ 下每个对象一个 YAML（路径同导出 zip）
+const (
+	backupMetaFile    = "backup.json"
+	backupResourceDir = "resources/"
+)
+
+// 后台任务（定时备份与接口发起的备份、恢复）
+const (
+	backupJobTimeout   = 30 * time.Minute   // 单个任务的执行期限
+	backupJobRetention = 7 * 24 * time.Hour // 已结束任务的保留时间
+)
+
+// 流式读取归档时的上限：解压总量防止压缩炸弹，单个条目限制内存占用
+const (
+	maxBackupExtracted = 1 << 30
+	maxBackupEntrySize = 16 << 20
+)
+
+// errJobInterrupted 服务关闭时取消的任务记录的错误
+var errJobInterrupted = errors.New("服务关闭，任务中断")
+
+// backupNamePattern 备份名与计划名：小写字母、数字与 -
+var backupNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// BackupService 命名空间备份与恢复：导出命名空间内的资源为归档保存到本地目录或 S3，按 cron 计划定时执行。
+// 与集群无关，作为单例创建，按 cluster_id 获取客户端（0 为默认集群）
+type BackupService struct {
+	defaultClient  *k8s.Client
+	k8sManager     *k8s.Manager
+	clusterService *ClusterService
+
+	cron    *cron.Cron
+	mu      sync.Mutex
+	entries map[uint]cron.EntryID // 计划 ID → 调度条目
+
+	// 后台任务使用的 context，Stop 时取消
+	ctx    context.Context
+	cancel context.CancelFunc
+	jobs   sync.WaitGroup
+}
+
+// NewBackupService 创建备份服务，调用 Start 后开始执行定时计划，随服务器关闭调用 Stop
+func NewBackupService(defaultClient *k8s.Client, k8sManager *k8s.Manager) *BackupService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BackupService{
+		defaultClient:  defaultClient,
+		k8sManager:     k8sManager,
+		clusterService: NewClusterService(),
+		// 同一计划上一次尚未结束时跳过本次
+		cron:    cron.New(cron.WithChain(cron.Recover(cron.DefaultLogger), cron.SkipIfStillRunning(cron.DefaultLogger))),
+		entries: map[uint]cron.EntryID{},
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start 加载数据库中的定时计划并启动调度。超过执行期限仍为 running 的任务所在实例已退出，记为失败
+func (s *BackupService) Start() {
+	stale := time.Now().Add(-backupJobTimeout)
+	if err := database.DB.Model(&model.BackupJob{}).Where("status = ? AND created_at < ?", model.BackupJobRunning, stale).
+		Updates(map[string]interface{}{"status": model.BackupJobFailed, "error": "任务所在实例已退出"}).Error; err != nil {
+		log.Printf("[WARN] 清理中断的备份任务失败: %v", err)
+	}
+	var schedules []model.BackupSchedule
+	if err := database.DB.Find(&schedules).Error; err != nil {
+		log.Printf("[WARN] 加载备份计划失败: %v", err)
+	}
+	for i := range schedules {
+		if err := s.register(&schedules[i]); err != nil {
+			log.Printf("[WARN] 备份计划 %s 无法调度: %v", schedules[i].Name, err)
+		}
+	}
+	s.cron.Start()
+}
+
+// Stop 停止调度并取消进行中的任务（记为失败），等待它们退出直到 ctx 结束
+func (s *BackupService) Stop(ctx context.Context) {
+	cronDone := s.cron.Stop()
+	s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		<-cronDone.Done()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("[WARN] 等待备份任务退出超时")
+	}
+}
+
+// register 按计划当前配置（重新）登记调度，暂停的计划只移除
+func (s *BackupService) register(schedule *model.BackupSchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.entries[schedule.ID]; ok {
+		s.cron.Remove(id)
+		delete(s.entries, schedule.ID)
+	}
+	if schedule.Suspend {
+		return nil
+	}
+	scheduleID, expr := schedule.ID, schedule.Schedule
+	id, err := s.cron.AddFunc(expr, func() {
+		// 每个副本都会触发，只有认领到本次触发时间的副本执行。
+		// 其他副本修改、暂停或删除计划后，本副本内存中的旧条目认领不到，不会按旧配置执行
+		claimed, err := claimScheduledRun(scheduleID, expr, time.Now())
+		if err != nil {
+			log.Printf("[WARN] 认领定时备份失败（计划 %d）: %v", scheduleID, err)
+			return
+		}
+		if !claimed {
+			return
+		}
+		ctx, cancel := context.WithTimeout(s.ctx, backupJobTimeout)
+		defer cancel()
+		if _, err := s.RunSchedule(ctx, scheduleID); err != nil {
+			log.Printf("[WARN] 定时备份失败（计划 %d）: %v", scheduleID, err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	s.entries[schedule.ID] = id
+	return nil
+}
+
+// claimScheduledRun 以触发时间（精确到分钟，各副本按各自时钟计算出同一值）做条件更新认领本次执行，
+// 更新成功的副本才执行，数据库保证同一次触发只有一个副本备份。
+// 计划已删除、已暂停或 cron 表达式已不是 expr 时认领失败
+func claimScheduledRun(scheduleID uint, expr string, now time.Time) (bool, error) {
+	tick := now.UTC().Truncate(time.Minute)
+	res := database.DB.Model(&model.BackupSchedule{}).
+		Where("id = ? AND suspend = ? AND schedule = ? AND (claimed_at IS NULL OR claimed_at < ?)", scheduleID, false, expr, tick).
+		UpdateColumn("claimed_at", tick)
+	return res.RowsAffected == 1, res.Error
+}
+
+// unregister 移除计划的调度
+func (s *BackupService) unregister(scheduleID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.entries[scheduleID]; ok {
+		s.cron.Remove(id)
+		delete(s.entries, scheduleID)
+	}
+}
+
+// client 获取集群客户端与集群名，clusterID 为 0 时为默认集群
+func (s *BackupService) client(clusterID uint) (*k8s.Client, string, error) {
+	if clusterID == 0 {
+		if s.defaultClient == nil {
+			return nil, "", fmt.Errorf("默认集群未连接")
+		}
+		return s.defaultClient, "default", nil
+	}
+	cluster, err := s.clusterService.GetCluster(clusterID)
+	if err != nil {
+		return nil, "", fmt.Errorf("集群 %d 不存在: %w", clusterID, err)
+	}
+	client, err := s.k8sManager.GetClient(clusterID, cluster)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, cluster.Name, nil
+}
+
+// StartBackup 在后台备份集群中的命名空间，存储与集群在返回前校验
+func (s *BackupService) StartBackup(clusterID uint, req model.BackupRequest, username string) (*model.BackupJob, error) {
+	if _, err := openBackupStore(); err != nil {
+		return nil, err
+	}
+	if _, _, err := s.client(clusterID); err != nil {
+		return nil, err
+	}
+	job := &model.BackupJob{Type: model.BackupJobBackup, ClusterID: clusterID, CreatedBy: username}
+	return s.startJob(job, func(ctx context.Context) (*model.BackupJobResult, error) {
+		backup, err := s.createBackup(ctx, clusterID, req, "", username)
+		if err != nil {
+			return nil, err
+		}
+		job.Backup = backup.Name
+		return &model.BackupJobResult{Backup: backup}, nil
+	})
+}
+
+// StartSchedule 在后台按计划立即执行一次备份
+func (s *BackupService) StartSchedule(id uint, username string) (*model.BackupJob, error) {
+	var schedule model.BackupSchedule
+	if err := database.DB.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+	if _, err := openBackupStore(); err != nil {
+		return nil, err
+	}
+	job := &model.BackupJob{Type: model.BackupJobSchedule, ClusterID: schedule.ClusterID, Schedule: schedule.Name, CreatedBy: username}
+	return s.startJob(job, func(ctx context.Context) (*model.BackupJobResult, error) {
+		backup, err := s.RunSchedule(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		job.Backup = backup.Name
+		return &model.BackupJobResult{Backup: backup}, nil
+	})
+}
+
+// StartRestore 在后台将备份恢复到 clusterID 指定的集群（见 Restore），选项、备份与集群在返回前校验
+func (s *BackupService) StartRestore(ctx context.Context, name string, clusterID uint, opts model.RestoreOptions, fieldManager, username string) (*model.BackupJob, error) {
+	if _, err := normalizeRestoreOptions(&opts); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetBackup(ctx, name); err != nil {
+		return nil, err
+	}
+	if _, _, err := s.client(clusterID); err != nil {
+		return nil, err
+	}
+	job := &model.BackupJob{Type: model.BackupJobRestore, ClusterID: clusterID, Backup: name, DryRun: opts.DryRun, CreatedBy: username}
+	return s.startJob(job, func(ctx context.Context) (*model.BackupJobResult, error) {
+		result, err := s.Restore(ctx, name, clusterID, opts, fieldManager)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BackupJobResult{Restore: result}, nil
+	})
+}
+
+// startJob 记录任务后在后台执行 run，结束时保存结果或错误。任务使用服务自身的 context，不受请求期限影响；
+// 返回的是创建时的任务副本
+func (s *BackupService) startJob(job *model.BackupJob, run func(ctx context.Context) (*model.BackupJobResult, error)) (*model.BackupJob, error) {
+	id := make([]byte, 8)
+	if _, err := crand.Read(id); err != nil {
+		return nil, err
+	}
+	job.ID = hex.EncodeToString(id)
+	job.Status = model.BackupJobRunning
+	if err := database.DB.Create(job).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Where("status <> ? AND finished_at < ?", model.BackupJobRunning, time.Now().Add(-backupJobRetention)).
+		Delete(&model.BackupJob{}).Error; err != nil {
+		log.Printf("[WARN] 清理过期备份任务失败: %v", err)
+	}
+	created := *job
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		ctx, cancel := context.WithTimeout(s.ctx, backupJobTimeout)
+		defer cancel()
+		result, err := run(ctx)
+
+		now := time.Now()
+		job.FinishedAt = &now
+		if err != nil {
+			if s.ctx.Err() != nil {
+				err = errJobInterrupted
+			}
+			job.Status, job.Error = model.BackupJobFailed, err.Error()
+		} else {
+			job.Status, job.Result = model.BackupJobSucceeded, result
+		}
+		if err := database.DB.Save(job).Error; err != nil {
+			log.Printf("[WARN] 记录备份任务 %s 结果失败: %v", job.ID, err)
+		}
+	}()
+	return &created, nil
+}
+
+// GetJob 获取后台任务
+func (s *BackupService) GetJob(id string) (*model.BackupJob, error) {
+	var job model.BackupJob
+	if err := database.DB.First(&job, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListJobs 最近的后台任务（按创建时间倒序，最多 limit 个），列表不含结果详情
+func (s *BackupService) ListJobs(limit int) ([]model.BackupJob, error) {
+	var jobs []model.BackupJob
+	err := database.DB.Omit("result").Order("created_at desc").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// createBackup 导出各命名空间（同命名空间导出：清理服务端字段、默认跳过控制器生成的对象），打包后写入存储。
+// 命名空间不存在时记入 warnings，全部没有对象时报错
+func (s *BackupService) createBackup(ctx context.Context, clusterID uint, req model.BackupRequest, scheduleName, username string) (*model.Backup, error) {
+	store, err := openBackupStore()
+	if err != nil {
+		return nil, err
+	}
+	client, clusterName, err := s.client(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	rs := NewResourceService(client)
+
+	prefix := scheduleName
+	if prefix == "" {
+		prefix = "manual"
+	}
+	backup := &model.Backup{
+		Name:           newBackupName(prefix, time.Now()),
+		ClusterID:      clusterID,
+		ClusterName:    clusterName,
+		Namespaces:     req.Namespaces,
+		Kinds:          req.Kinds,
+		IncludeSecrets: req.IncludeSecrets,
+		Schedule:       scheduleName,
+		CreatedBy:      username,
+		CreatedAt:      time.Now().UTC(),
+		Summary:        map[string]int{},
+	}
+	var objs []*unstructured.Unstructured
+	for _, ns := range req.Namespaces {
+		exported, err := rs.ExportNamespace(ctx, ns, req.Kinds, req.IncludeOwned)
+		if apierrors.IsNotFound(err) {
+			backup.Warnings = append(backup.Warnings, fmt.Sprintf("命名空间 %s 不存在", ns))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("命名空间 %s: %w", ns, err)
+		}
+		for _, w := range exported.Warnings {
+			backup.Warnings = append(backup.Warnings, ns+": "+w)
+		}
+		objs = append(objs, exported.Objects...)
+	}
+	// Secret 以明文写入归档，需显式开启
+	if !req.IncludeSecrets {
+		kept := objs[:0]
+		for _, obj := range objs {
+			if obj.GetKind() != "Secret" || obj.GroupVersionKind().Group != "" {
+				kept = append(kept, obj)
+			}
+		}
+		if skipped := len(objs) - len(kept); skipped > 0 {
+			backup.Warnings = append(backup.Warnings, fmt.Sprintf("未包含 %d 个 Secret（include_secrets 未开启）", skipped))
+		}
+		objs = kept
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("没有可备份的对象: %s", strings.Join(backup.Warnings, "; "))
+	}
+	for _, obj := range objs {
+		backup.Summary[obj.GetKind()]++
+	}
+	backup.Objects = len(objs)
+
+	// 归档边打包边写入存储，不在内存中保留整个归档
+	pr, pw := io.Pipe()
+	archive := &countingWriter{w: pw}
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		pw.CloseWithError(writeBackupArchive(archive, backup, objs))
+	}()
+	err = store.put(ctx, backup.Name+backupArchiveSuffix, pr)
+	pr.Close() // 存储提前返回时让打包退出
+	<-written
+	if err != nil {
+		return nil, fmt.Errorf("写入备份失败: %w", err)
+	}
+	backup.Size = archive.n
+	meta, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.put(ctx, backup.Name+backupMetaSuffix, bytes.NewReader(meta)); err != nil {
+		return nil, fmt.Errorf("写入备份失败: %w", err)
+	}
+	return backup, nil
+}
+
+// newBackupName 备份名：<前缀>-<UTC 时间>-<随机后缀>，按名称排序即按时间排序
+func newBackupName(prefix string, now time.Time) string {
+	return fmt.Sprintf("%s-%s-%04x", prefix, now.UTC().Format("20060102-150405"), rand.Intn(0x10000))
+}
+
+// ListBackups 列出存储中的备份（按创建时间倒序），可按集群、命名空间与计划过滤
+func (s *BackupService) ListBackups(ctx context.Context, q model.BackupQuery) ([]model.Backup, error) {
+	store, err := openBackupStore()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := store.list(ctx, backupMetaSuffix)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]model.Backup, 0, len(keys))
+	for _, key := range keys {
+		backup, err := readBackupMeta(ctx, store, strings.TrimSuffix(key, backupMetaSuffix))
+		if err != nil {
+			log.Printf("[WARN] 跳过无法读取的备份元数据 %s: %v", key, err)
+			continue
+		}
+		if q.ClusterID != nil && backup.ClusterID != *q.ClusterID {
+			continue
+		}
+		if q.Schedule != "" && backup.Schedule != q.Schedule {
+			continue
+		}
+		if q.Namespace != "" && !slices.Contains(backup.Namespaces, q.Namespace) {
+			continue
+		}
+		backups = append(backups, *backup)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// GetBackup 获取备份元数据
+func (s *BackupService) GetBackup(ctx context.Context, name string) (*model.Backup, error) {
+	store, err := openBackupStore()
+	if err != nil {
+		return nil, err
+	}
+	if !backupNamePattern.MatchString(name) {
+		return nil, errBackupNotFound
+	}
+	return readBackupMeta(ctx, store, name)
+}
+
+// OpenBackup 打开备份归档（tar.gz）供流式读取，同时返回元数据，调用方负责关闭
+func (s *BackupService) OpenBackup(ctx context.Context, name string) (*model.Backup, io.ReadCloser, error) {
+	backup, err := s.GetBackup(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := openBackupStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := store.open(ctx, name+backupArchiveSuffix)
+	if err != nil {
+		return nil, nil, err
+	}
+	return backup, rc, nil
+}
+
+// DeleteBackup 删除备份（先删元数据，使其立即从列表中消失）
+func (s *BackupService) DeleteBackup(ctx context.Context, name string) error {
+	if _, err := s.GetBackup(ctx, name); err != nil {
+		return err
+	}
+	store, err := openBackupStore()
+	if err != nil {
+		return err
+	}
+	if err := store.remove(ctx, name+backupMetaSuffix); err != nil {
+		return err
+	}
+	return store.remove(ctx, name+backupArchiveSuffix)
+}
+
+// IsBackupNotFound 备份是否不存在
+func IsBackupNotFound(err error) bool {
+	return errors.Is(err, errBackupNotFound)
+}
+
+func readBackupMeta(ctx context.Context, store backupStore, name string) (*model.Backup, error) {
+	data, err := store.get(ctx, name+backupMetaSuffix)
+	if err != nil {
+		return nil, err
+	}
+	var backup model.Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// normalizeRestoreOptions 校验冲突策略（缺省为 skip）并解析标签选择器
+func normalizeRestoreOptions(opts *model.RestoreOptions) (labels.Selector, error) {
+	switch opts.ConflictPolicy {
+	case "":
+		opts.ConflictPolicy = model.ConflictSkip
+	case model.ConflictSkip, model.ConflictOverwrite:
+	default:
+		return nil, fmt.Errorf("conflict_policy 只支持 skip 或 overwrite")
+	}
+	if opts.LabelSelector == "" {
+		return labels.Everything(), nil
+	}
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("标签选择器无效: %w", err)
+	}
+	return selector, nil
+}
+
+// Restore 将备份恢复到 clusterID 指定的集群（可与备份来源不同）。对象按依赖顺序以 server-side apply 提交，
+// 单个对象失败不影响其余对象。conflict_policy 为 skip 时已存在的对象保持不变（记为 exists），
+// overwrite 时以备份内容强制 apply（接管备份中写出的字段，其余字段保持线上值）。
+// 归档从存储流式读取，只保留被选中的对象
+func (s *BackupService) Restore(ctx context.Context, name string, clusterID uint, opts model.RestoreOptions, fieldManager string) (*model.RestoreResult, error) {
+	selector, err := normalizeRestoreOptions(&opts)
+	if err != nil {
+		return nil, err
+	}
+	client, _, err := s.client(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	rs := NewResourceService(client)
+	resources, err := rs.APIResources(false)
+	if err != nil {
+		return nil, err
+	}
+
+	_, archive, err := s.OpenBackup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	keep := restoreFilter(opts, selector, resourceIndex(resources))
+	var objs []*unstructured.Unstructured
+	if _, err := readBackupArchive(archive, func(obj *unstructured.Unstructured) {
+		if keep(obj) {
+			objs = append(objs, obj)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("备份中没有符合条件的对象")
+	}
+	overwrite := opts.ConflictPolicy == model.ConflictOverwrite
+	result := rs.ApplyObjects(ctx, objs, model.ApplyOptions{
+		ContinueOnError: true,
+		Force:           overwrite,
+		SkipExisting:    !overwrite,
+		DryRun:          opts.DryRun,
+		FieldManager:    fieldManager,
+	})
+	return &model.RestoreResult{Backup: name, ApplyResult: *result}, nil
+}
+
+// resourceIndex 按 GroupKind 索引集群的资源类型，用于按 resource 名或简称过滤备份中的对象
+func resourceIndex(resources []model.APIResourceInfo) map[schema.GroupKind]model.APIResourceInfo {
+	index := make(map[schema.GroupKind]model.APIResourceInfo, len(resources))
+	for _, r := range resources {
+		index[schema.GroupKind{Group: r.Group, Kind: r.Kind}] = r
+	}
+	return index
+}
+
+// restoreFilter 返回按命名空间、类型与标签选择对象的函数，选中的对象就地重映射命名空间。
+// 所选命名空间的 Namespace 对象总是保留（除非被 exclude_kinds 排除），以便恢复到新命名空间
+func restoreFilter(opts model.RestoreOptions, selector labels.Selector, index map[schema.GroupKind]model.APIResourceInfo) func(*unstructured.Unstructured) bool {
+	matchAny := func(args []string, obj *unstructured.Unstructured) bool {
+		gk := obj.GroupVersionKind().GroupKind()
+		info := index[gk]
+		for _, arg := range args {
+			if matchResourceArg(arg, gk.Group, info.Resource, gk.Kind, info.ShortNames) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(obj *unstructured.Unstructured) bool {
+		isNamespace := obj.GetKind() == "Namespace" && obj.GroupVersionKind().Group == ""
+		source := obj.GetNamespace()
+		if isNamespace {
+			source = obj.GetName()
+		}
+		if len(opts.Namespaces) > 0 && !slices.Contains(opts.Namespaces, source) {
+			return false
+		}
+		if matchAny(opts.ExcludeKinds, obj) {
+			return false
+		}
+		if !isNamespace && (len(opts.IncludeKinds) > 0 && !matchAny(opts.IncludeKinds, obj) || !selector.Matches(labels.Set(obj.GetLabels()))) {
+			return false
+		}
+		remapNamespace(obj, opts.NamespaceMapping)
+		return true
+	}
+}
+
+// remapNamespace 按映射修改对象所在命名空间；Namespace 对象改名，RoleBinding 中引用源命名空间 ServiceAccount 的 subject 一并修改
+func remapNamespace(obj *unstructured.Unstructured, mapping map[string]string) {
+	if len(mapping) == 0 {
+		return
+	}
+	if obj.GetKind() == "Namespace" {
+		if target, ok := mapping[obj.GetName()]; ok {
+			obj.SetName(target)
+			if lbls := obj.GetLabels(); lbls["kubernetes.io/metadata.name"] != "" {
+				lbls["kubernetes.io/metadata.name"] = target
+				obj.SetLabels(lbls)
+			}
+		}
+		return
+	}
+	if target, ok := mapping[obj.GetNamespace()]; ok {
+		obj.SetNamespace(target)
+	}
+	if obj.GetKind() != "RoleBinding" {
+		return
+	}
+	subjects, _, _ := unstructured.NestedSlice(obj.Object, "subjects")
+	for _, item := range subjects {
+		if subject, ok := item.(map[string]interface{}); ok {
+			if ns, _ := subject["namespace"].(string); mapping[ns] != "" {
+				subject["namespace"] = mapping[ns]
+			}
+		}
+	}
+	if subjects != nil {
+		_ = unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+	}
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// writeBackupArchive 打包元数据与对象为 tar.gz 写入 w
+func writeBackupArchive(w io.Writer, backup *model.Backup, objs []*unstructured.Unstructured) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: backup.CreatedAt, Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	meta, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := add(backupMetaFile, meta); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if err := add(backupResourceDir+manifestFileName(obj), data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readBackupArchive 逐个读取归档条目：解析元数据并按归档中的顺序将对象交给 visit，不在内存中保留整个归档
+func readBackupArchive(r io.Reader, visit func(*unstructured.Unstructured)) (*model.Backup, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取备份归档: %w", err)
+	}
+	defer gz.Close()
+
+	backup := &model.Backup{}
+	var total int64
+	err = extractTar(gz, func(name string, r io.Reader) error {
+		if name != backupMetaFile && !(strings.HasPrefix(name, backupResourceDir) && strings.HasSuffix(name, ".yaml")) {
+			return nil
+		}
+		content, err := io.ReadAll(io.LimitReader(r, maxBackupEntrySize+1))
+		if err != nil {
+			return err
+		}
+		if len(content) > maxBackupEntrySize {
+			return fmt.Errorf("%s 超过 %d MB", name, maxBackupEntrySize>>20)
+		}
+		if total += int64(len(content)); total > maxBackupExtracted {
+			return fmt.Errorf("备份归档解压后超过 %d MB", maxBackupExtracted>>20)
+		}
+		if name == backupMetaFile {
+			return json.Unmarshal(content, backup)
+		}
+		decoded, err := decodeManifests(string(content))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for _, obj := range decoded {
+			visit(obj)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// ListSchedules 列出定时备份计划
+func (s *BackupService) ListSchedules() ([]model.BackupSchedule, error) {
+	var schedules []model.BackupSchedule
+	err := database.DB.Order("name").Find(&schedules).Error
+	return schedules, err
+}
+
+// CreateSchedule 创建定时备份计划并立即登记调度
+func (s *BackupService) CreateSchedule(req model.BackupScheduleRequest) (*model.BackupSchedule, error) {
+	schedule := &model.BackupSchedule{Name: req.Name}
+	applyScheduleRequest(schedule, req)
+	if err := s.validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	var count int64
+	database.DB.Model(&model.BackupSchedule{}).Where("name = ?", schedule.Name).Count(&count)
+	if count > 0 {
+		return nil, fmt.Errorf("备份计划 %s 已存在", schedule.Name)
+	}
+	if err := database.DB.Create(schedule).Error; err != nil {
+		return nil, err
+	}
+	return schedule, s.register(schedule)
+}
+
+// UpdateSchedule 修改定时备份计划（名称不可修改，已有备份按名称归属计划）
+func (s *BackupService) UpdateSchedule(id uint, req model.BackupScheduleRequest) (*model.BackupSchedule, error) {
+	var schedule model.BackupSchedule
+	if err := database.DB.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+	applyScheduleRequest(&schedule, req)
+	if err := s.validateSchedule(&schedule); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Save(&schedule).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, s.register(&schedule)
+}
+
+// DeleteSchedule 删除定时备份计划，已生成的备份保留
+func (s *BackupService) DeleteSchedule(id uint) error {
+	if err := database.DB.Delete(&model.BackupSchedule{}, id).Error; err != nil {
+		return err
+	}
+	s.unregister(id)
+	return nil
+}
+
+// RunSchedule 按计划执行一次备份，记录执行结果并按 keep 清理该计划的旧备份
+func (s *BackupService) RunSchedule(ctx context.Context, id uint) (*model.Backup, error) {
+	var schedule model.BackupSchedule
+	if err := database.DB.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+	backup, err := s.createBackup(ctx, schedule.ClusterID, model.BackupRequest{Namespaces: schedule.Namespaces, Kinds: schedule.Kinds, IncludeSecrets: schedule.IncludeSecrets}, schedule.Name, "")
+
+	now := time.Now()
+	updates := map[string]interface{}{"last_run_at": &now, "last_error": ""}
+	if err != nil {
+		updates["last_error"] = err.Error()
+	} else {
+		updates["last_backup"] = backup.Name
+	}
+	if dbErr := database.DB.Model(&schedule).Updates(updates).Error; dbErr != nil {
+		log.Printf("[WARN] 记录备份计划 %s 执行结果失败: %v", schedule.Name, dbErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Keep > 0 {
+		if err := s.pruneBackups(ctx, schedule.ClusterID, schedule.Name, schedule.Keep); err != nil {
+			log.Printf("[WARN] 清理备份计划 %s 的旧备份失败: %v", schedule.Name, err)
+		}
+	}
+	return backup, nil
+}
+
+// pruneBackups 只保留计划在该集群最近的 keep 份备份
+func (s *BackupService) pruneBackups(ctx context.Context, clusterID uint, scheduleName string, keep int) error {
+	backups, err := s.ListBackups(ctx, model.BackupQuery{ClusterID: &clusterID, Schedule: scheduleName})
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(backups); i++ {
+		if err := s.DeleteBackup(ctx, backups[i].Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyScheduleRequest 将请求中可修改的字段写入计划
+func applyScheduleRequest(schedule *model.BackupSchedule, req model.BackupScheduleRequest) {
+	schedule.ClusterID = req.ClusterID
+	schedule.Namespaces = req.Namespaces
+	schedule.Kinds = req.Kinds
+	schedule.Schedule = strings.TrimSpace(req.Schedule)
+	schedule.Keep = req.Keep
+	schedule.Suspend = req.Suspend
+	schedule.IncludeSecrets = req.IncludeSecrets
+}
+
+// validateSchedule 校验计划名、cron 表达式与集群
+func (s *BackupService) validateSchedule(schedule *model.BackupSchedule) error {
+	if len(schedule.Name) > 40 || !backupNamePattern.MatchString(schedule.Name) {
+		return fmt.Errorf("计划名只能包含小写字母、数字与 -，且不超过 40 个字符")
+	}
+	if len(schedule.Namespaces) == 0 {
+		return fmt.Errorf("namespaces 不能为空")
+	}
+	if _, err := cron.ParseStandard(schedule.Schedule); err != nil {
+		return fmt.Errorf("cron 表达式无效: %w", err)
+	}
+	if schedule.ClusterID != 0 {
+		if _, err := s.clusterService.GetCluster(schedule.ClusterID); err != nil {
+			return fmt.Errorf("集群 %d 不存在", schedule.ClusterID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// ErrBackupStorageDisabled 未配置备份存储
+var ErrBackupStorageDisabled = errors.New("未配置备份存储（BACKUP_DIR 或 BACKUP_S3_BUCKET）")
+
+// errBackupNotFound 存储中不存在该对象
+var errBackupNotFound = errors.New("备份不存在")
+
+// backupStore 备份归档的存储后端，key 为不含目录的文件名
+type backupStore interface {
+	put(ctx context.Context, key string, r io.Reader) error // 流式写入，r 返回错误时不留下对象
+	get(ctx context.Context, key string) ([]byte, error)
+	open(ctx context.Context, key string) (io.ReadCloser, error) // 流式读取，用于归档
+	list(ctx context.Context, suffix string) ([]string, error)
+	remove(ctx context.Context, key string) error
+}
+
+// openBackupStore 按配置选择存储：配置了 BACKUP_S3_BUCKET 时使用 S3 兼容存储，否则使用 BACKUP_DIR
+func openBackupStore() (backupStore, error) {
+	if config.App == nil {
+		return nil, ErrBackupStorageDisabled
+	}
+	if s3 := config.App.BackupS3; s3.Bucket != "" {
+		client, err := minio.New(s3.Endpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(s3.AccessKey, s3.SecretKey, ""),
+			Secure: !s3.Insecure,
+			Region: s3.Region,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("无法连接 S3: %w", err)
+		}
+		return &s3Store{client: client, bucket: s3.Bucket, prefix: strings.Trim(s3.Prefix, "/")}, nil
+	}
+	if config.App.BackupDir != "" {
+		return &localStore{dir: config.App.BackupDir}, nil
+	}
+	return nil, ErrBackupStorageDisabled
+}
+
+// localStore 本地目录存储
+type localStore struct {
+	dir string
+}
+
+// put 先写临时文件再改名，避免中断时留下不完整的归档
+func (s *localStore) put(_ context.Context, key string, r io.Reader) error {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-"+key+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, key))
+}
+
+func (s *localStore) get(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errBackupNotFound
+	}
+	return data, err
+}
+
+func (s *localStore) open(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errBackupNotFound
+	}
+	return f, err
+}
+
+func (s *localStore) list(_ context.Context, suffix string) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && strings.HasSuffix(e.Name(), suffix) && !strings.HasPrefix(e.Name(), ".") {
+			keys = append(keys, e.Name())
+		}
+	}
+	return keys, nil
+}
+
+func (s *localStore) remove(_ context.Context, key string) error {
+	err := os.Remove(filepath.Join(s.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// s3Store S3 兼容对象存储（AWS S3、MinIO 等），对象键为 <prefix>/<key>
+type s3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func (s *s3Store) objectName(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return path.Join(s.prefix, key)
+}
+
+// s3PartSize 流式上传（长度未知）的分片大小，未指定时 minio 按 5 TB 上限计算分片，每片缓冲超过 500 MB
+const s3PartSize = 16 << 20
+
+// put 长度未知，按分片上传，读取出错时放弃整个上传
+func (s *s3Store) put(ctx context.Context, key string, r io.Reader) error {
+	_, err := s.client.PutObject(ctx, s.bucket, s.objectName(key), r, -1, minio.PutObjectOptions{PartSize: s3PartSize})
+	return err
+}
+
+func (s *s3Store) get(ctx context.Context, key string) ([]byte, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, s.objectName(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(obj)
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, errBackupNotFound
+	}
+	return data, err
+}
+
+// open GetObject 不发请求，先 Stat 以便对象不存在时立即报错
+func (s *s3Store) open(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, s.objectName(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, errBackupNotFound
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *s3Store) list(ctx context.Context, suffix string) ([]string, error) {
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+	var keys []string
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		if key := strings.TrimPrefix(obj.Key, prefix); !strings.Contains(key, "/") && strings.HasSuffix(key, suffix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *s3Store) remove(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, s.objectName(key), minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/kube-admin/kube-admin/backend/database"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const backupObjects = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+  labels: {kubernetes.io/metadata.name: shop}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: app-config, namespace: shop, labels: {app: web}}
+data: {mode: prod}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web, namespace: shop, labels: {app: web}}
+spec:
+  replicas: 2
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata: {name: web-reader, namespace: shop}
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: Role, name: reader}
+subjects:
+- {kind: ServiceAccount, name: web, namespace: shop}
+- {kind: ServiceAccount, name: monitor, namespace: monitoring}
+`
+
+// TestBackupArchiveRoundTrip 备份归档写入后可按原顺序读回元数据与对象
+func TestBackupArchiveRoundTrip(t *testing.T) {
+	objs, err := decodeManifests(backupObjects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := &model.Backup{Name: "manual-20260101-000000-abcd", Namespaces: []string{"shop"}, CreatedAt: time.Now().UTC()}
+	var data bytes.Buffer
+	if err := writeBackupArchive(&data, backup, objs); err != nil {
+		t.Fatal(err)
+	}
+	var restored []*unstructured.Unstructured
+	meta, err := readBackupArchive(&data, func(obj *unstructured.Unstructured) {
+		restored = append(restored, obj)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Name != backup.Name || len(restored) != len(objs) {
+		t.Fatalf("round trip: meta %+v, %d objects", meta, len(restored))
+	}
+	for i := range objs {
+		if restored[i].GetKind() != objs[i].GetKind() || restored[i].GetName() != objs[i].GetName() {
+			t.Errorf("object %d = %s %s", i, restored[i].GetKind(), restored[i].GetName())
+		}
+	}
+}
+
+// TestRestoreFilter 恢复按命名空间、类型与标签选择对象，Namespace 总是保留并随映射改名
+func TestRestoreFilter(t *testing.T) {
+	index := resourceIndex([]model.APIResourceInfo{
+		{Version: "v1", Resource: "configmaps", Kind: "ConfigMap", Namespaced: true, ShortNames: []string{"cm"}},
+		{Group: "apps", Version: "v1", Resource: "deployments", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}},
+	})
+	restore := func(opts model.RestoreOptions) []*unstructured.Unstructured {
+		objs, err := decodeManifests(backupObjects)
+		if err != nil {
+			t.Fatal(err)
+		}
+		selector, err := labels.Parse(opts.LabelSelector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keep := restoreFilter(opts, selector, index)
+		var selected []*unstructured.Unstructured
+		for _, obj := range objs {
+			if keep(obj) {
+				selected = append(selected, obj)
+			}
+		}
+		return selected
+	}
+
+	// 类型过滤与标签选择器不作用于 Namespace
+	got := restore(model.RestoreOptions{IncludeKinds: []string{"cm", "deployments.apps"}, LabelSelector: "app=web", ExcludeKinds: []string{"Deployment"}})
+	if len(got) != 2 || got[0].GetKind() != "Namespace" || got[1].GetName() != "app-config" {
+		t.Errorf("filtered = %v", objectNames(got))
+	}
+	if got := restore(model.RestoreOptions{Namespaces: []string{"other"}}); len(got) != 0 {
+		t.Errorf("namespace filter = %v", objectNames(got))
+	}
+
+	// 命名空间重映射：Namespace 改名，RoleBinding 中同命名空间的 subject 一并修改
+	got = restore(model.RestoreOptions{NamespaceMapping: map[string]string{"shop": "shop-dr"}})
+	if got[0].GetName() != "shop-dr" || got[0].GetLabels()["kubernetes.io/metadata.name"] != "shop-dr" {
+		t.Errorf("namespace = %s %v", got[0].GetName(), got[0].GetLabels())
+	}
+	for _, obj := range got[1:] {
+		if obj.GetNamespace() != "shop-dr" {
+			t.Errorf("%s namespace = %s", obj.GetName(), obj.GetNamespace())
+		}
+	}
+	subjects, _, _ := unstructured.NestedSlice(got[3].Object, "subjects")
+	if subjects[0].(map[string]interface{})["namespace"] != "shop-dr" || subjects[1].(map[string]interface{})["namespace"] != "monitoring" {
+		t.Errorf("subjects = %v", subjects)
+	}
+}
+
+// TestLocalStore 本地存储的写入、列出、读取与删除，写入失败不留下对象
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store := &localStore{dir: t.TempDir()}
+	if err := store.put(ctx, "a.json", strings.NewReader("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.put(ctx, "a.tar.gz", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := store.list(ctx, backupMetaSuffix)
+	if err != nil || len(keys) != 1 || keys[0] != "a.json" {
+		t.Fatalf("list = %v, %v", keys, err)
+	}
+	if data, err := store.get(ctx, "a.json"); err != nil || string(data) != "{}" {
+		t.Errorf("get = %q, %v", data, err)
+	}
+	if rc, err := store.open(ctx, "a.tar.gz"); err != nil {
+		t.Errorf("open = %v", err)
+	} else {
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(data) != "x" {
+			t.Errorf("open read %q", data)
+		}
+	}
+	// 写入中途出错不留下对象
+	if err := store.put(ctx, "b.tar.gz", io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errors.New("boom")))); err == nil {
+		t.Error("expected put error")
+	}
+	if _, err := store.open(ctx, "b.tar.gz"); !IsBackupNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if err := store.remove(ctx, "a.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.get(ctx, "a.json"); !IsBackupNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+// TestNewBackupName 备份名带前缀与 UTC 时间且符合命名规则
+func TestNewBackupName(t *testing.T) {
+	name := newBackupName("nightly", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if !backupNamePattern.MatchString(name) || name[:len("nightly-20260102-030405-")] != "nightly-20260102-030405-" {
+		t.Errorf("backup name = %s", name)
+	}
+}
+
+// useTestDB 以内存 sqlite 替换全局数据库
+func useTestDB(t *testing.T) *gorm.DB {
+	old := database.DB
+	t.Cleanup(func() { database.DB = old })
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存数据库每个连接各自独立，后台任务须与测试共用同一连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&model.BackupSchedule{}, &model.BackupJob{}); err != nil {
+		t.Fatal(err)
+	}
+	database.DB = db
+	return db
+}
+
+// TestBackupJobs 后台任务记录成功、失败与 Stop 时中断的结果
+func TestBackupJobs(t *testing.T) {
+	useTestDB(t)
+	s := NewBackupService(nil, nil)
+	ok, err := s.startJob(&model.BackupJob{Type: model.BackupJobBackup}, func(ctx context.Context) (*model.BackupJobResult, error) {
+		return &model.BackupJobResult{Backup: &model.Backup{Name: "manual-20261019-000000-abcd"}}, nil
+	})
+	if err != nil || ok.Status != model.BackupJobRunning {
+		t.Fatalf("start = %+v, %v", ok, err)
+	}
+	failed, _ := s.startJob(&model.BackupJob{Type: model.BackupJobRestore}, func(ctx context.Context) (*model.BackupJobResult, error) {
+		return nil, errors.New("boom")
+	})
+	// Stop 取消尚未结束的任务，任务记为中断
+	blocked, _ := s.startJob(&model.BackupJob{Type: model.BackupJobRestore}, func(ctx context.Context) (*model.BackupJobResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	s.Stop(context.Background())
+
+	for id, want := range map[string]string{ok.ID: model.BackupJobSucceeded, failed.ID: model.BackupJobFailed, blocked.ID: model.BackupJobFailed} {
+		job, err := s.GetJob(id)
+		if err != nil || job.Status != want || job.FinishedAt == nil {
+			t.Errorf("job %s = %+v, %v; want %s", id, job, err, want)
+		}
+	}
+	if job, _ := s.GetJob(ok.ID); job.Result == nil || job.Result.Backup.Name != "manual-20261019-000000-abcd" {
+		t.Errorf("result = %v", job.Result)
+	}
+	if job, _ := s.GetJob(blocked.ID); job.Error != errJobInterrupted.Error() {
+		t.Errorf("error = %s", job.Error)
+	}
+	if jobs, err := s.ListJobs(10); err != nil || len(jobs) != 3 || jobs[0].Result != nil {
+		t.Errorf("list = %+v, %v", jobs, err)
+	}
+}
+
+// TestClaimScheduledRun 同一次触发只有一个副本认领成功，计划修改、暂停或删除后旧条目认领不到
+func TestClaimScheduledRun(t *testing.T) {
+	db := useTestDB(t)
+	schedule := &model.BackupSchedule{Name: "nightly", Namespaces: []string{"shop"}, Schedule: "0 2 * * *"}
+	if err := db.Create(schedule).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 多个副本在同一分钟内触发，只有第一个认领成功；下一次触发可再次认领
+	tick := time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC)
+	for i, c := range []struct {
+		at   time.Time
+		want bool
+	}{
+		{tick.Add(100 * time.Millisecond), true},
+		{tick.Add(800 * time.Millisecond), false},
+		{tick.Add(time.Second).In(time.FixedZone("CST", 8*3600)), false},
+		{tick.Add(24 * time.Hour), true},
+	} {
+		claimed, err := claimScheduledRun(schedule.ID, schedule.Schedule, c.at)
+		if err != nil || claimed != c.want {
+			t.Errorf("claim %d = %v, %v; want %v", i, claimed, err, c.want)
+		}
+	}
+
+	// 其他副本修改表达式或暂停计划后，按旧配置触发的条目认领不到
+	next := tick.Add(48 * time.Hour)
+	if err := db.Model(schedule).UpdateColumn("schedule", "0 3 * * *").Error; err != nil {
+		t.Fatal(err)
+	}
+	if claimed, err := claimScheduledRun(schedule.ID, "0 2 * * *", next); err != nil || claimed {
+		t.Errorf("claim with stale schedule = %v, %v", claimed, err)
+	}
+	if err := db.Model(schedule).UpdateColumn("suspend", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if claimed, err := claimScheduledRun(schedule.ID, "0 3 * * *", next); err != nil || claimed {
+		t.Errorf("claim while suspended = %v, %v", claimed, err)
+	}
+	if err := db.Delete(schedule).Error; err != nil {
+		t.Fatal(err)
+	}
+	if claimed, err := claimScheduledRun(schedule.ID, "0 3 * * *", next); err != nil || claimed {
+		t.Errorf("claim after delete = %v, %v", claimed, err)
+	}
+}
+
+func objectNames(objs []*unstructured.Unstructured) []string {
+	var out []string
+	for _, obj := range objs {
+		out = append(out, obj.GetKind()+"/"+obj.GetName())
+	}
+	return out
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// listCached 列表读取策略：默认读 informer 缓存（标签选择器在缓存上过滤）；
+// fresh=true、带字段选择器或 K8s 分块（limit/continue）时走实时 LIST，
+// 缓存不可用（未同步、无 watch 权限等）时同样回退，保证接口始终可用。
+func listCached[T metav1.Object](q model.ListQuery, cached func(labels.Selector) ([]T, error), live func(metav1.ListOptions) ([]T, metav1.ListMeta, error)) ([]T, metav1.ListMeta, error) {
+	if !q.Fresh && q.FieldSelector == "" && !q.Chunked() {
+		selector, err := labels.Parse(q.LabelSelector)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		if items, err := cached(selector); err == nil {
+			sortByNamespaceName(items)
+			return items, metav1.ListMeta{}, nil
+		}
+	}
+	return live(listOptions(q))
+}
+
+// listOptions 将查询参数转为 K8s ListOptions（选择器与分块参数透传）
+func listOptions(q model.ListQuery) metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: q.LabelSelector,
+		FieldSelector: q.FieldSelector,
+		Limit:         q.Limit,
+		Continue:      q.Continue,
+	}
+}
+
+// sortByNamespaceName lister 返回无序，按 namespace/name 排序，与实时 LIST 顺序一致
+func sortByNamespaceName[T metav1.Object](items []T) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+}
+
+// pageObjects 名称搜索 + 排序 + 偏移分页，返回当前页与过滤后的总数。
+// 分块模式下不做偏移（翻页由 continue 令牌完成），总数为本块条数加 apiserver 估算的剩余数；
+// 此时若有名称搜索，剩余数未经过滤，总数为 model.TotalUnknown。
+func pageObjects[T metav1.Object](items []T, listMeta metav1.ListMeta, q model.ListQuery) ([]T, int64) {
+	if q.Search != "" {
+		keyword := strings.ToLower(q.Search)
+		filtered := make([]T, 0, len(items))
+		for _, item := range items {
+			if strings.Contains(strings.ToLower(item.GetName()), keyword) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+
+	desc := q.Order == "desc"
+	if q.SortBy != "" || desc {
+		sort.SliceStable(items, func(i, j int) bool {
+			a, b := items[i], items[j]
+			if desc {
+				a, b = b, a
+			}
+			switch q.SortBy {
+			case "namespace":
+				if a.GetNamespace() != b.GetNamespace() {
+					return a.GetNamespace() < b.GetNamespace()
+				}
+			case "created":
+				ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+				if !ta.Equal(&tb) {
+					return ta.Before(&tb)
+				}
+			}
+			return a.GetName() < b.GetName()
+		})
+	}
+
+	total := int64(len(items))
+	if q.Chunked() {
+		if q.Search != "" {
+			return items, model.TotalUnknown
+		}
+		if listMeta.RemainingItemCount != nil {
+			total += *listMeta.RemainingItemCount
+		}
+		return items, total
+	}
+	if q.Paged() {
+		page, size := q.PageBounds()
+		start := (page - 1) * size
+		if start > len(items) {
+			start = len(items)
+		}
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		items = items[start:end]
+	}
+	return items, total
+}
+
+// pageList 对原始对象分页后仅转换当前页，组装分页响应
+func pageList[T metav1.Object, R any](items []T, listMeta metav1.ListMeta, q model.ListQuery, convert func(T) R) *model.PageResponse {
+	pageItems, total := pageObjects(items, listMeta, q)
+	out := make([]R, 0, len(pageItems))
+	for _, item := range pageItems {
+		out = append(out, convert(item))
+	}
+	resp := &model.PageResponse{
+		Total:              total,
+		Items:              out,
+		Continue:           listMeta.Continue,
+		RemainingItemCount: listMeta.RemainingItemCount,
+	}
+	if q.Paged() && !q.Chunked() {
+		resp.Page, resp.PageSize = q.PageBounds()
+	}
+	return resp
+}
+
+// itemPointers 将 List 结果的 Items 转为指针切片，与 lister 返回类型对齐
+func itemPointers[T any](items []T) []*T {
+	out := make([]*T, len(items))
+	for i := range items {
+		out[i] = &items[i]
+	}
+	return out
+}
+
+// cachedPods 读取Pod列表（namespace 为空表示全部）
+func cachedPods(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*corev1.Pod, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*corev1.Pod, error) {
+		lister, err := client.Cache().Pods(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.Pods(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*corev1.Pod, metav1.ListMeta, error) {
+		list, err := client.ClientSet.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedNodes 读取Node列表
+func cachedNodes(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]*corev1.Node, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*corev1.Node, error) {
+		lister, err := client.Cache().Nodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.List(selector)
+	}, func(opts metav1.ListOptions) ([]*corev1.Node, metav1.ListMeta, error) {
+		list, err := client.ClientSet.CoreV1().Nodes().List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedNamespaces 读取Namespace列表
+func cachedNamespaces(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]*corev1.Namespace, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*corev1.Namespace, error) {
+		lister, err := client.Cache().Namespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.List(selector)
+	}, func(opts metav1.ListOptions) ([]*corev1.Namespace, metav1.ListMeta, error) {
+		list, err := client.ClientSet.CoreV1().Namespaces().List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedServices 读取Service列表（namespace 为空表示全部）
+func cachedServices(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*corev1.Service, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*corev1.Service, error) {
+		lister, err := client.Cache().Services(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.Services(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*corev1.Service, metav1.ListMeta, error) {
+		list, err := client.ClientSet.CoreV1().Services(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedConfigMaps 读取ConfigMap列表（namespace 为空表示全部）
+func cachedConfigMaps(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*corev1.ConfigMap, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*corev1.ConfigMap, error) {
+		lister, err := client.Cache().ConfigMaps(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.ConfigMaps(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*corev1.ConfigMap, metav1.ListMeta, error) {
+		list, err := client.ClientSet.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// secretListPageSize 实时分页读取 Secret 的每页条数
+const secretListPageSize = 500
+
+// listSecrets 读取Secret列表（namespace 为空表示全部）。Secret 不进 informer 缓存，避免常驻内存保存全部
+// Secret 内容：始终实时 LIST，分块请求透传 limit/continue，否则按页读完
+func listSecrets(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*corev1.Secret, metav1.ListMeta, error) {
+	iface := client.ClientSet.CoreV1().Secrets(namespace)
+	opts := listOptions(q)
+	if q.Chunked() {
+		list, err := iface.List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	}
+
+	opts.Limit = secretListPageSize
+	var items []corev1.Secret
+	for {
+		list, err := iface.List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		items = append(items, list.Items...)
+		if opts.Continue = list.Continue; opts.Continue == "" {
+			return itemPointers(items), metav1.ListMeta{}, nil
+		}
+	}
+}
+
+// cachedDeployments 读取Deployment列表（namespace 为空表示全部）
+func cachedDeployments(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*appsv1.Deployment, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*appsv1.Deployment, error) {
+		lister, err := client.Cache().Deployments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.Deployments(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*appsv1.Deployment, metav1.ListMeta, error) {
+		list, err := client.ClientSet.AppsV1().Deployments(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedReplicaSets 读取ReplicaSet列表（namespace 为空表示全部）
+func cachedReplicaSets(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*appsv1.ReplicaSet, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*appsv1.ReplicaSet, error) {
+		lister, err := client.Cache().ReplicaSets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.ReplicaSets(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*appsv1.ReplicaSet, metav1.ListMeta, error) {
+		list, err := client.ClientSet.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedStatefulSets 读取StatefulSet列表（namespace 为空表示全部）
+func cachedStatefulSets(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*appsv1.StatefulSet, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*appsv1.StatefulSet, error) {
+		lister, err := client.Cache().StatefulSets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.StatefulSets(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*appsv1.StatefulSet, metav1.ListMeta, error) {
+		list, err := client.ClientSet.AppsV1().StatefulSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedDaemonSets 读取DaemonSet列表（namespace 为空表示全部）
+func cachedDaemonSets(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*appsv1.DaemonSet, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*appsv1.DaemonSet, error) {
+		lister, err := client.Cache().DaemonSets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.DaemonSets(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*appsv1.DaemonSet, metav1.ListMeta, error) {
+		list, err := client.ClientSet.AppsV1().DaemonSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedJobs 读取Job列表（namespace 为空表示全部）
+func cachedJobs(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*batchv1.Job, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*batchv1.Job, error) {
+		lister, err := client.Cache().Jobs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.Jobs(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*batchv1.Job, metav1.ListMeta, error) {
+		list, err := client.ClientSet.BatchV1().Jobs(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedCronJobs 读取CronJob列表（namespace 为空表示全部）
+func cachedCronJobs(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*batchv1.CronJob, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*batchv1.CronJob, error) {
+		lister, err := client.Cache().CronJobs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.CronJobs(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*batchv1.CronJob, metav1.ListMeta, error) {
+		list, err := client.ClientSet.BatchV1().CronJobs(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
+
+// cachedHPAs 读取HPA列表（namespace 为空表示全部）
+func cachedHPAs(ctx context.Context, client *k8s.Client, namespace string, q model.ListQuery) ([]*autoscalingv2.HorizontalPodAutoscaler, metav1.ListMeta, error) {
+	return listCached(q, func(selector labels.Selector) ([]*autoscalingv2.HorizontalPodAutoscaler, error) {
+		lister, err := client.Cache().HorizontalPodAutoscalers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.HorizontalPodAutoscalers(namespace).List(selector)
+	}, func(opts metav1.ListOptions) ([]*autoscalingv2.HorizontalPodAutoscaler, metav1.ListMeta, error) {
+		list, err := client.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, metav1.ListMeta{}, err
+		}
+		return itemPointers(list.Items), list.ListMeta, nil
+	})
+}
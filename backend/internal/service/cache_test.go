@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPods() []*corev1.Pod {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var pods []*corev1.Pod
+	for i, name := range []string{"web-1", "api-1", "web-2", "db-1", "web-3"} {
+		pods = append(pods, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(base.Add(time.Duration(i) * time.Minute)),
+		}})
+	}
+	return pods
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// TestPageObjectsSearchAndPage 名称搜索后偏移分页，总数为过滤后条数
+func TestPageObjectsSearchAndPage(t *testing.T) {
+	q := model.ListQuery{Search: "WEB", SortBy: "name", Page: 2, PageSize: 2}
+	items, total := pageObjects(testPods(), metav1.ListMeta{}, q)
+	if total != 3 {
+		t.Fatalf("total = %d, want 3", total)
+	}
+	if got := podNames(items); len(got) != 1 || got[0] != "web-3" {
+		t.Fatalf("page 2 = %v, want [web-3]", got)
+	}
+}
+
+// TestPageObjectsSortCreatedDesc 按创建时间倒序
+func TestPageObjectsSortCreatedDesc(t *testing.T) {
+	items, _ := pageObjects(testPods(), metav1.ListMeta{}, model.ListQuery{SortBy: "created", Order: "desc"})
+	if got := podNames(items); got[0] != "web-3" || got[4] != "web-1" {
+		t.Fatalf("created desc = %v", got)
+	}
+}
+
+// TestPageObjectsChunked 分块模式不做偏移，总数叠加剩余条数
+func TestPageObjectsChunked(t *testing.T) {
+	remaining := int64(10)
+	items, total := pageObjects(testPods(), metav1.ListMeta{RemainingItemCount: &remaining}, model.ListQuery{Limit: 5})
+	if len(items) != 5 || total != 15 {
+		t.Fatalf("chunked len=%d total=%d, want 5/15", len(items), total)
+	}
+	if _, total := pageObjects(testPods(), metav1.ListMeta{RemainingItemCount: &remaining}, model.ListQuery{Limit: 5, Search: "pod"}); total != model.TotalUnknown {
+		t.Errorf("chunked search total=%d, want unknown", total)
+	}
+}
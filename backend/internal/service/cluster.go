@@ -83,18 +83,23 @@ func (s *ClusterService) GetClusterResponse(id uint) (*model.ClusterResponse, er
 
 // CreateCluster 创建集群
 func (s *ClusterService) CreateCluster(req model.ClusterRequest) (*model.ClusterResponse, error) {
-	if req.ConfigContent == "" && req.ConfigPath == "" && (req.ServerURL == "" || req.Token == "") {
-		return nil, fmt.Errorf("必须提供至少一种连接方式：1. kubeconfig内容 2. kubeconfig文件路径 3. 服务器地址和Token")
+	if !req.HasCredential() {
+		return nil, fmt.Errorf("必须提供至少一种连接方式：1. kubeconfig内容 2. kubeconfig文件路径 3. 服务器地址和Token 4. agent 接入")
 	}
 
+	connectionType := req.ConnectionType
+	if connectionType == "" {
+		connectionType = model.ConnectionDirect
+	}
 	cluster := model.Cluster{
-		Name:          req.Name,
-		Description:   req.Description,
-		ServerURL:     req.ServerURL,
-		Token:         req.Token,
-		ConfigPath:    req.ConfigPath,
-		ConfigContent: req.ConfigContent,
-		Status:        "active",
+		Name:           req.Name,
+		Description:    req.Description,
+		ServerURL:      req.ServerURL,
+		Token:          req.Token,
+		ConfigPath:     req.ConfigPath,
+		ConfigContent:  req.ConfigContent,
+		ConnectionType: connectionType,
+		Status:         "active",
 	}
 
 	if err := database.DB.Create(&cluster).Error; err != nil {
@@ -121,6 +126,9 @@ func (s *ClusterService) UpdateCluster(id uint, req model.ClusterRequest) (*mode
 	cluster.Description = req.Description
 	cluster.ServerURL = req.ServerURL
 	cluster.ConfigPath = req.ConfigPath
+	if req.ConnectionType != "" {
+		cluster.ConnectionType = req.ConnectionType
+	}
 
 	// 仅在提供新值时更新敏感字段，留空表示保留
 	if req.Token != "" {
@@ -130,8 +138,8 @@ func (s *ClusterService) UpdateCluster(id uint, req model.ClusterRequest) (*mode
 		cluster.ConfigContent = req.ConfigContent
 	}
 
-	// 校验：更新后仍需至少一种可用连接方式
-	if cluster.ConfigContent == "" && cluster.ConfigPath == "" && (cluster.ServerURL == "" || cluster.Token == "") {
+	// 校验：更新后仍需至少一种可用连接方式（agent 模式凭 join token 接入，无需直连凭据）
+	if !cluster.IsAgent() && cluster.ConfigContent == "" && cluster.ConfigPath == "" && (cluster.ServerURL == "" || cluster.Token == "") {
 		return nil, fmt.Errorf("更新后集群无可用连接方式，请保留或重新提供凭据")
 	}
 
@@ -179,6 +187,10 @@ func (s *ClusterService) TestConnectionByID(id uint) (*model.TestConnectionRespo
 	if err != nil {
 		return nil, fmt.Errorf("集群不存在: %v", err)
 	}
+	if cluster.IsAgent() {
+		// agent 集群无直连凭据，连通性以隧道在线状态为准（见 GET /clusters/:id/agent）
+		return &model.TestConnectionResponse{Success: false, Message: "agent 接入集群不支持直连测试，请查看 agent 连接状态"}, nil
+	}
 	req := model.TestConnectionRequest{
 		ServerURL:     cluster.ServerURL,
 		Token:         cluster.Token,
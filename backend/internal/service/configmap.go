@@ -18,24 +18,21 @@ func NewConfigMapService(k8sClient *k8s.Client) *ConfigMapService {
 	return &ConfigMapService{k8sClient: k8sClient}
 }
 
-// ListConfigMaps 获取ConfigMap列表
-func (s *ConfigMapService) ListConfigMaps(namespace string) ([]model.ConfigMapInfo, error) {
-	configMapList, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
+// ListConfigMaps 获取ConfigMap列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *ConfigMapService) ListConfigMaps(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedConfigMaps(ctx, s.k8sClient, namespace, q)
 	if err != nil {
 		return nil, err
 	}
 
-	var configMaps []model.ConfigMapInfo
-	for _, cm := range configMapList.Items {
-		configMaps = append(configMaps, s.convertConfigMap(&cm))
-	}
-
-	return configMaps, nil
+	return pageList(list, listMeta, q, func(cm *corev1.ConfigMap) model.ConfigMapInfo {
+		return s.convertConfigMap(cm)
+	}), nil
 }
 
 // GetConfigMap 获取ConfigMap详情
-func (s *ConfigMapService) GetConfigMap(namespace, name string) (*model.ConfigMapInfo, error) {
-	cm, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+func (s *ConfigMapService) GetConfigMap(ctx context.Context, namespace, name string) (*model.ConfigMapInfo, error) {
+	cm, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +42,7 @@ func (s *ConfigMapService) GetConfigMap(namespace, name string) (*model.ConfigMa
 }
 
 // CreateConfigMap 创建ConfigMap
-func (s *ConfigMapService) CreateConfigMap(namespace, name string, data map[string]string) error {
+func (s *ConfigMapService) CreateConfigMap(ctx context.Context, namespace, name string, data map[string]string) error {
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -54,25 +51,25 @@ func (s *ConfigMapService) CreateConfigMap(namespace, name string, data map[stri
 		Data: data,
 	}
 
-	_, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+	_, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
 	return err
 }
 
-// UpdateConfigMap 更新ConfigMap
-func (s *ConfigMapService) UpdateConfigMap(namespace, name string, data map[string]string) error {
-	cm, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+// UpdateConfigMap 更新ConfigMap，dryRun 时只做服务端校验不持久化
+func (s *ConfigMapService) UpdateConfigMap(ctx context.Context, namespace, name string, data map[string]string, dryRun bool) error {
+	cm, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
 	cm.Data = data
-	_, err = s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	_, err = s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
 	return err
 }
 
 // DeleteConfigMap 删除ConfigMap
-func (s *ConfigMapService) DeleteConfigMap(namespace, name string) error {
-	return s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (s *ConfigMapService) DeleteConfigMap(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
 }
 
 // convertConfigMap 转换ConfigMap对象
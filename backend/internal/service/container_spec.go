@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// podTemplatePaths 含 Pod 模板的工作负载及模板所在路径（仅内置类型支持 strategic merge patch）。
+// Job 创建后 Pod 模板不可修改，不在其列
+var podTemplatePaths = map[schema.GroupResource][]string{
+	{Group: "apps", Resource: "deployments"}:        {"spec", "template"},
+	{Group: "apps", Resource: "statefulsets"}:       {"spec", "template"},
+	{Group: "apps", Resource: "daemonsets"}:         {"spec", "template"},
+	{Group: "apps", Resource: "replicasets"}:        {"spec", "template"},
+	{Group: "batch", Resource: "cronjobs"}:          {"spec", "jobTemplate", "spec", "template"},
+	{Group: "", Resource: "replicationcontrollers"}: {"spec", "template"},
+}
+
+// podTemplatePath 返回 GVR 对应的 Pod 模板路径
+func podTemplatePath(gvr schema.GroupVersionResource) ([]string, error) {
+	path, ok := podTemplatePaths[gvr.GroupResource()]
+	if !ok {
+		return nil, fmt.Errorf("%s 不是含 Pod 模板的内置工作负载", gvr.Resource)
+	}
+	return path, nil
+}
+
+// podTemplateOf 从对象中取出 Pod 模板
+func podTemplateOf(u *unstructured.Unstructured, path []string) (*corev1.PodTemplateSpec, error) {
+	raw, found, err := unstructured.NestedMap(u.Object, path...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s 缺少 %s", u.GetName(), strings.Join(path, "."))
+	}
+	template := &corev1.PodTemplateSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// GetContainers 读取工作负载 Pod 模板中的容器配置
+func (s *ResourceService) GetContainers(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) ([]model.WorkloadContainer, error) {
+	path, err := podTemplatePath(gvr)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.Get(ctx, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	template, err := podTemplateOf(u, path)
+	if err != nil {
+		return nil, err
+	}
+	return workloadContainers(&template.Spec), nil
+}
+
+// UpdateContainers 修改容器镜像、环境变量、资源与探针。
+// 在模板副本上应用修改后与原模板比较，生成最小的 strategic merge patch 提交，
+// 并写入 kubernetes.io/change-cause 注解供 rollout 历史展示。
+// patch 带读取时的 resourceVersion，对象在此期间被修改时 apiserver 返回 409，避免覆盖他人的修改。
+func (s *ResourceService) UpdateContainers(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, req model.ContainerSpecUpdate) (*model.ContainerSpecResult, error) {
+	path, err := podTemplatePath(gvr)
+	if err != nil {
+		return nil, err
+	}
+	iface, err := s.namespacedResource(gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+	u, err := iface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	original, err := podTemplateOf(u, path)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := original.DeepCopy()
+	for _, update := range req.Containers {
+		if err := applyContainerUpdate(&modified.Spec, update); err != nil {
+			return nil, err
+		}
+	}
+	templatePatch, err := podTemplatePatch(original, modified)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ContainerSpecResult{Containers: workloadContainers(&modified.Spec)}
+	if len(templatePatch) == 0 {
+		result.Containers = workloadContainers(&original.Spec)
+		return result, nil
+	}
+
+	result.Changed = true
+	result.ChangeCause = req.ChangeCause
+	if result.ChangeCause == "" {
+		result.ChangeCause = containerChangeCause(req.Containers)
+	}
+	patch := nestedPatch(path, templatePatch)
+	patch["metadata"] = map[string]interface{}{
+		"resourceVersion": u.GetResourceVersion(),
+		"annotations":     map[string]interface{}{changeCauseAnnotation: result.ChangeCause},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := iface.Patch(ctx, name, types.StrategicMergePatchType, data, metav1.PatchOptions{}); err != nil {
+		return nil, err
+	}
+	result.Patch = string(data)
+	return result, nil
+}
+
+// podTemplatePatch 计算两个模板间的 strategic merge patch，无差异时返回 nil
+func podTemplatePatch(original, modified *corev1.PodTemplateSpec) (map[string]interface{}, error) {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return nil, err
+	}
+	modifiedJSON, err := json.Marshal(modified)
+	if err != nil {
+		return nil, err
+	}
+	data, err := strategicpatch.CreateTwoWayMergePatch(originalJSON, modifiedJSON, corev1.PodTemplateSpec{})
+	if err != nil {
+		return nil, err
+	}
+	patch := map[string]interface{}{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	return patch, nil
+}
+
+// nestedPatch 将模板 patch 放到对象中的模板路径下
+func nestedPatch(path []string, leaf map[string]interface{}) map[string]interface{} {
+	patch := leaf
+	for i := len(path) - 1; i >= 0; i-- {
+		patch = map[string]interface{}{path[i]: patch}
+	}
+	return patch
+}
+
+// containerProbe 容器上的一个探针字段及其新值
+type containerProbe struct {
+	kind   string
+	target **corev1.Probe
+	probe  *corev1.Probe
+}
+
+// applyContainerUpdate 在 Pod spec 上应用单个容器的修改并校验
+func applyContainerUpdate(spec *corev1.PodSpec, update model.ContainerUpdate) error {
+	containers := spec.Containers
+	if update.Init {
+		containers = spec.InitContainers
+	}
+	idx := slices.IndexFunc(containers, func(c corev1.Container) bool { return c.Name == update.Name })
+	if idx < 0 {
+		return fmt.Errorf("容器 %s 不存在", update.Name)
+	}
+	c := &containers[idx]
+
+	if update.Image != "" {
+		c.Image = update.Image
+	}
+
+	for _, name := range update.RemoveEnv {
+		c.Env = slices.DeleteFunc(c.Env, func(e corev1.EnvVar) bool { return e.Name == name })
+	}
+	for _, env := range update.Env {
+		if err := validateEnvVar(env); err != nil {
+			return fmt.Errorf("容器 %s: %w", c.Name, err)
+		}
+		if i := slices.IndexFunc(c.Env, func(e corev1.EnvVar) bool { return e.Name == env.Name }); i >= 0 {
+			c.Env[i] = env
+		} else {
+			c.Env = append(c.Env, env)
+		}
+	}
+
+	if update.Resources != nil {
+		if err := validateResources(update.Resources); err != nil {
+			return fmt.Errorf("容器 %s: %w", c.Name, err)
+		}
+		c.Resources.Requests = update.Resources.Requests
+		c.Resources.Limits = update.Resources.Limits
+	}
+
+	probes := []containerProbe{
+		{"liveness", &c.LivenessProbe, update.LivenessProbe},
+		{"readiness", &c.ReadinessProbe, update.ReadinessProbe},
+		{"startup", &c.StartupProbe, update.StartupProbe},
+	}
+	for _, kind := range update.RemoveProbes {
+		i := slices.IndexFunc(probes, func(p containerProbe) bool { return p.kind == kind })
+		if i < 0 {
+			return fmt.Errorf("remove_probes 仅支持 liveness、readiness、startup")
+		}
+		*probes[i].target = nil
+	}
+	for _, p := range probes {
+		if p.probe == nil {
+			continue
+		}
+		if err := validateProbe(p.probe); err != nil {
+			return fmt.Errorf("容器 %s 的 %s 探针: %w", c.Name, p.kind, err)
+		}
+		*p.target = p.probe
+	}
+	return nil
+}
+
+// validateEnvVar value 与 valueFrom 互斥，valueFrom 只能指定一种来源
+func validateEnvVar(env corev1.EnvVar) error {
+	if env.Name == "" {
+		return fmt.Errorf("环境变量名不能为空")
+	}
+	if env.ValueFrom == nil {
+		return nil
+	}
+	if env.Value != "" {
+		return fmt.Errorf("环境变量 %s 不能同时设置 value 与 valueFrom", env.Name)
+	}
+	sources := 0
+	for _, set := range []bool{env.ValueFrom.FieldRef != nil, env.ValueFrom.ResourceFieldRef != nil, env.ValueFrom.ConfigMapKeyRef != nil, env.ValueFrom.SecretKeyRef != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("环境变量 %s 的 valueFrom 必须且只能指定一种来源", env.Name)
+	}
+	return nil
+}
+
+// validateResources 同一资源的 request 不能超过 limit
+func validateResources(r *corev1.ResourceRequirements) error {
+	for name, request := range r.Requests {
+		if limit, ok := r.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("%s 的 request（%s）不能大于 limit（%s）", name, request.String(), limit.String())
+		}
+	}
+	return nil
+}
+
+// validateProbe 探针必须且只能指定一种检查方式
+func validateProbe(p *corev1.Probe) error {
+	handlers := 0
+	for _, set := range []bool{p.Exec != nil, p.HTTPGet != nil, p.TCPSocket != nil, p.GRPC != nil} {
+		if set {
+			handlers++
+		}
+	}
+	if handlers != 1 {
+		return fmt.Errorf("必须且只能指定 exec、httpGet、tcpSocket、grpc 之一")
+	}
+	return nil
+}
+
+// containerChangeCause 按修改内容生成变更原因，如 "set image app=nginx:1.25; set env app"
+func containerChangeCause(updates []model.ContainerUpdate) string {
+	var images, envs, resources, probes []string
+	for _, u := range updates {
+		if u.Image != "" {
+			images = append(images, u.Name+"="+u.Image)
+		}
+		if len(u.Env) > 0 || len(u.RemoveEnv) > 0 {
+			envs = append(envs, u.Name)
+		}
+		if u.Resources != nil {
+			resources = append(resources, u.Name)
+		}
+		if u.LivenessProbe != nil || u.ReadinessProbe != nil || u.StartupProbe != nil || len(u.RemoveProbes) > 0 {
+			probes = append(probes, u.Name)
+		}
+	}
+	var parts []string
+	for _, p := range []struct {
+		verb  string
+		items []string
+	}{{"set image", images}, {"set env", envs}, {"set resources", resources}, {"set probes", probes}} {
+		if len(p.items) > 0 {
+			parts = append(parts, p.verb+" "+strings.Join(p.items, " "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// workloadContainers 列出 init 容器与业务容器的配置
+func workloadContainers(spec *corev1.PodSpec) []model.WorkloadContainer {
+	result := make([]model.WorkloadContainer, 0, len(spec.InitContainers)+len(spec.Containers))
+	add := func(c corev1.Container, init bool) {
+		env := c.Env
+		if env == nil {
+			env = []corev1.EnvVar{}
+		}
+		result = append(result, model.WorkloadContainer{
+			Name:           c.Name,
+			Init:           init,
+			Image:          c.Image,
+			Env:            env,
+			Resources:      c.Resources,
+			LivenessProbe:  c.LivenessProbe,
+			ReadinessProbe: c.ReadinessProbe,
+			StartupProbe:   c.StartupProbe,
+		})
+	}
+	for _, c := range spec.InitContainers {
+		add(c, true)
+	}
+	for _, c := range spec.Containers {
+		add(c, false)
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func testPodTemplate() *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "migrate", Image: "migrate:1"}},
+		Containers: []corev1.Container{
+			{
+				Name:  "app",
+				Image: "app:1",
+				Env:   []corev1.EnvVar{{Name: "MODE", Value: "prod"}, {Name: "DEBUG", Value: "1"}},
+				LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+					TCPSocket: &corev1.TCPSocketAction{},
+				}},
+			},
+			{Name: "sidecar", Image: "proxy:1"},
+		},
+	}}
+}
+
+// TestContainerUpdatePatch 容器修改生成的 patch 只包含变更的容器与字段，且不修改原模板
+func TestContainerUpdatePatch(t *testing.T) {
+	original := testPodTemplate()
+	modified := original.DeepCopy()
+	err := applyContainerUpdate(&modified.Spec, model.ContainerUpdate{
+		Name:      "app",
+		Image:     "app:2",
+		RemoveEnv: []string{"DEBUG"},
+		Env: []corev1.EnvVar{{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"},
+		}}},
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		},
+		RemoveProbes: []string{"liveness"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original.Spec.Containers[0].Image != "app:1" {
+		t.Fatal("original template was modified")
+	}
+
+	patch, err := podTemplatePatch(original, modified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(patch)
+	got := string(data)
+	// 只包含被修改的容器与字段，未修改的 sidecar/init 容器不出现
+	for _, want := range []string{`"image":"app:2"`, `"$patch":"delete"`, `"secretKeyRef"`, `"livenessProbe":null`, `"cpu":"500m"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("patch missing %s: %s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"proxy:1", "migrate", `"prod"`} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("patch should not contain %s: %s", unwanted, got)
+		}
+	}
+
+	if patch, err := podTemplatePatch(original, original.DeepCopy()); err != nil || patch != nil {
+		t.Errorf("unchanged template: patch=%v err=%v", patch, err)
+	}
+}
+
+// TestContainerUpdateValidation 非法的容器修改被拒绝，change-cause 按修改内容生成
+func TestContainerUpdateValidation(t *testing.T) {
+	cases := map[string]model.ContainerUpdate{
+		"unknown container":  {Name: "nope", Image: "x"},
+		"init not container": {Name: "app", Init: true, Image: "x"},
+		"value and valueFrom": {Name: "app", Env: []corev1.EnvVar{{Name: "A", Value: "1", ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+		}}}},
+		"request over limit": {Name: "app", Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		}},
+		"probe without handler": {Name: "app", ReadinessProbe: &corev1.Probe{PeriodSeconds: 5}},
+		"unknown probe kind":    {Name: "app", RemoveProbes: []string{"warmup"}},
+	}
+	for name, update := range cases {
+		if err := applyContainerUpdate(&testPodTemplate().Spec, update); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	cause := containerChangeCause([]model.ContainerUpdate{{Name: "app", Image: "app:2", Env: []corev1.EnvVar{{Name: "A"}}}, {Name: "sidecar", Image: "proxy:2"}})
+	if cause != "set image app=app:2 sidecar=proxy:2; set env app" {
+		t.Errorf("change cause: %q", cause)
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CronJobService CronJob服务
+type CronJobService struct {
+	k8sClient *k8s.Client
+}
+
+// NewCronJobService 创建CronJob服务
+func NewCronJobService(k8sClient *k8s.Client) *CronJobService {
+	return &CronJobService{k8sClient: k8sClient}
+}
+
+// GetK8sClient 获取K8s客户端
+func (s *CronJobService) GetK8sClient() *k8s.Client {
+	return s.k8sClient
+}
+
+// ListCronJobs 获取CronJob列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *CronJobService) ListCronJobs(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedCronJobs(ctx, s.k8sClient, namespace, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageList(list, listMeta, q, convertCronJob), nil
+}
+
+// GetCronJob 获取CronJob详情，含由其创建的 Job
+func (s *CronJobService) GetCronJob(ctx context.Context, namespace, name string) (*model.CronJobDetail, error) {
+	cj, err := s.k8sClient.ClientSet.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	jobs, _, err := cachedJobs(ctx, s.k8sClient, namespace, model.ListQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.CronJobDetail{
+		CronJobInfo:                convertCronJob(cj),
+		StartingDeadlineSeconds:    cj.Spec.StartingDeadlineSeconds,
+		SuccessfulJobsHistoryLimit: cj.Spec.SuccessfulJobsHistoryLimit,
+		FailedJobsHistoryLimit:     cj.Spec.FailedJobsHistoryLimit,
+		ActiveJobs:                 []string{},
+		Jobs:                       []model.JobInfo{},
+	}
+	for _, ref := range cj.Status.Active {
+		detail.ActiveJobs = append(detail.ActiveJobs, ref.Name)
+	}
+	sortJobsByCreation(jobs)
+	for _, job := range jobs {
+		if metav1.IsControlledBy(job, cj) {
+			detail.Jobs = append(detail.Jobs, convertJob(job))
+		}
+	}
+	return detail, nil
+}
+
+// DeleteCronJob 删除CronJob（后台级联删除其 Job 与 Pod）
+func (s *CronJobService) DeleteCronJob(ctx context.Context, namespace, name string, dryRun bool) error {
+	opts := jobDeleteOptions()
+	opts.DryRun = dryRunOption(dryRun)
+	return s.k8sClient.ClientSet.BatchV1().CronJobs(namespace).Delete(ctx, name, opts)
+}
+
+// TriggerCronJob 按 jobTemplate 立即创建一个 Job（同 kubectl create job --from=cronjob/<name>），返回 Job 名称
+func (s *CronJobService) TriggerCronJob(ctx context.Context, namespace, name string) (string, error) {
+	cj, err := s.k8sClient.ClientSet.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	created, err := s.k8sClient.ClientSet.BatchV1().Jobs(namespace).Create(ctx, jobFromCronJob(cj, time.Now()), metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return created.Name, nil
+}
+
+// SetSuspended 暂停或恢复CronJob的调度（不影响已在运行的 Job）
+func (s *CronJobService) SetSuspended(ctx context.Context, namespace, name string, suspend bool) error {
+	patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+	_, err := s.k8sClient.ClientSet.BatchV1().CronJobs(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// jobFromCronJob 由 jobTemplate 生成手动触发的 Job，CronJob 作为 controller 归属
+func jobFromCronJob(cj *batchv1.CronJob, now time.Time) *batchv1.Job {
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cj.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            derivedJobName(cj.Name, "manual", now),
+			Namespace:       cj.Namespace,
+			Labels:          cj.Spec.JobTemplate.Labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cj, batchv1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: *cj.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
+
+// nextSchedule 按 cron 表达式计算下一次调度时间。
+// 与 CronJob 控制器一致使用标准 5 段语法（支持 @hourly 等描述符）；未设置 timeZone 时按 UTC 计算。
+func nextSchedule(cj *batchv1.CronJob, now time.Time) (time.Time, error) {
+	loc := time.UTC
+	if tz := cj.Spec.TimeZone; tz != nil && *tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(*tz); err != nil {
+			return time.Time{}, fmt.Errorf("无效的时区 %q: %w", *tz, err)
+		}
+	}
+	sched, err := cron.ParseStandard(cj.Spec.Schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的 cron 表达式: %w", err)
+	}
+	return sched.Next(now.In(loc)), nil
+}
+
+// convertCronJob 转换CronJob对象
+func convertCronJob(cj *batchv1.CronJob) model.CronJobInfo {
+	info := model.CronJobInfo{
+		K8sResource: model.K8sResource{
+			Name:              cj.Name,
+			Namespace:         cj.Namespace,
+			Labels:            cj.Labels,
+			Annotations:       cj.Annotations,
+			CreationTimestamp: cj.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			ResourceVersion:   cj.ResourceVersion,
+		},
+		Schedule:          cj.Spec.Schedule,
+		Suspend:           cj.Spec.Suspend != nil && *cj.Spec.Suspend,
+		ConcurrencyPolicy: string(cj.Spec.ConcurrencyPolicy),
+		Active:            len(cj.Status.Active),
+		Images:            podSpecImages(&cj.Spec.JobTemplate.Spec.Template.Spec),
+	}
+	if cj.Spec.TimeZone != nil {
+		info.TimeZone = *cj.Spec.TimeZone
+	}
+	if cj.Status.LastScheduleTime != nil {
+		info.LastScheduleTime = cj.Status.LastScheduleTime.Format("2006-01-02 15:04:05")
+	}
+	if cj.Status.LastSuccessfulTime != nil {
+		info.LastSuccessfulTime = cj.Status.LastSuccessfulTime.Format("2006-01-02 15:04:05")
+	}
+
+	next, err := nextSchedule(cj, time.Now())
+	switch {
+	case err != nil:
+		info.ScheduleError = err.Error()
+	case !info.Suspend:
+		info.NextScheduleTime = next.Local().Format("2006-01-02 15:04:05")
+	}
+	return info
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// daemonSetImplicitTolerations DaemonSet 控制器为其 Pod 自动添加的容忍（节点异常或不可调度时仍运行）
+var daemonSetImplicitTolerations = []corev1.Toleration{
+	{Key: corev1.TaintNodeNotReady, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeDiskPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodePIDPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// DaemonSetService DaemonSet服务
+type DaemonSetService struct {
+	k8sClient *k8s.Client
+}
+
+// NewDaemonSetService 创建DaemonSet服务
+func NewDaemonSetService(k8sClient *k8s.Client) *DaemonSetService {
+	return &DaemonSetService{k8sClient: k8sClient}
+}
+
+// GetK8sClient 获取K8s客户端
+func (s *DaemonSetService) GetK8sClient() *k8s.Client {
+	return s.k8sClient
+}
+
+// ListDaemonSets 获取DaemonSet列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *DaemonSetService) ListDaemonSets(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedDaemonSets(ctx, s.k8sClient, namespace, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageList(list, listMeta, q, convertDaemonSet), nil
+}
+
+// GetDaemonSet 获取DaemonSet详情，含逐节点覆盖情况
+func (s *DaemonSetService) GetDaemonSet(ctx context.Context, namespace, name string) (*model.DaemonSetDetail, error) {
+	ds, err := s.k8sClient.ClientSet.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, _, err := cachedNodes(ctx, s.k8sClient, model.ListQuery{})
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, _, err := cachedPods(ctx, s.k8sClient, namespace, model.ListQuery{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.DaemonSetDetail{DaemonSetInfo: convertDaemonSet(ds)}
+	detail.Nodes = daemonSetNodeCoverage(ds, nodes, pods)
+	for _, n := range detail.Nodes {
+		switch {
+		case n.Eligible && n.Pod != "":
+			detail.CoveredNodes++
+		case n.Eligible:
+			detail.MissingNodes++
+		}
+	}
+	return detail, nil
+}
+
+// DeleteDaemonSet 删除DaemonSet
+func (s *DaemonSetService) DeleteDaemonSet(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.AppsV1().DaemonSets(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
+}
+
+// RestartDaemonSet 滚动重启DaemonSet
+func (s *DaemonSetService) RestartDaemonSet(ctx context.Context, namespace, name string) error {
+	_, err := s.k8sClient.ClientSet.AppsV1().DaemonSets(namespace).Patch(ctx, name, types.MergePatchType, restartPatch(), metav1.PatchOptions{})
+	return err
+}
+
+// convertDaemonSet 转换DaemonSet对象
+func convertDaemonSet(ds *appsv1.DaemonSet) model.DaemonSetInfo {
+	return model.DaemonSetInfo{
+		K8sResource: model.K8sResource{
+			Name:              ds.Name,
+			Namespace:         ds.Namespace,
+			Labels:            ds.Labels,
+			Annotations:       ds.Annotations,
+			CreationTimestamp: ds.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			ResourceVersion:   ds.ResourceVersion,
+		},
+		DesiredNumberScheduled: ds.Status.DesiredNumberScheduled,
+		CurrentNumberScheduled: ds.Status.CurrentNumberScheduled,
+		NumberReady:            ds.Status.NumberReady,
+		UpdatedNumberScheduled: ds.Status.UpdatedNumberScheduled,
+		NumberAvailable:        ds.Status.NumberAvailable,
+		NumberMisscheduled:     ds.Status.NumberMisscheduled,
+		UpdateStrategy:         string(ds.Spec.UpdateStrategy.Type),
+		NodeSelector:           ds.Spec.Template.Spec.NodeSelector,
+		Images:                 podSpecImages(&ds.Spec.Template.Spec),
+	}
+}
+
+// daemonSetNodeCoverage 逐节点给出是否应运行、实际 Pod 与就绪情况
+func daemonSetNodeCoverage(ds *appsv1.DaemonSet, nodes []*corev1.Node, pods []*corev1.Pod) []model.DaemonSetNodeStatus {
+	podByNode := map[string]*corev1.Pod{}
+	for _, pod := range pods {
+		if metav1.IsControlledBy(pod, ds) && pod.Spec.NodeName != "" && pod.DeletionTimestamp == nil {
+			podByNode[pod.Spec.NodeName] = pod
+		}
+	}
+	sortByNamespaceName(nodes)
+
+	result := make([]model.DaemonSetNodeStatus, 0, len(nodes))
+	for _, node := range nodes {
+		eligible, reason := daemonSetNodeEligible(&ds.Spec.Template.Spec, node)
+		ns := model.DaemonSetNodeStatus{Node: node.Name, Eligible: eligible, Reason: reason}
+		if pod := podByNode[node.Name]; pod != nil {
+			ns.Pod = pod.Name
+			ns.Phase = string(pod.Status.Phase)
+			ns.Ready = podReady(pod)
+			ns.Misscheduled = !eligible
+		}
+		result = append(result, ns)
+	}
+	return result
+}
+
+// daemonSetNodeEligible 估算 Pod 能否运行在节点上：nodeSelector、必需的节点亲和与污点容忍。
+// 不模拟资源不足等调度器细节，结果与控制器的判定可能有少量出入。
+func daemonSetNodeEligible(spec *corev1.PodSpec, node *corev1.Node) (bool, string) {
+	if !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false, "nodeSelector 不匹配"
+	}
+	if a := spec.Affinity; a != nil && a.NodeAffinity != nil && a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		if !nodeSelectorTermsMatch(a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, node) {
+			return false, "节点亲和不匹配"
+		}
+	}
+	tolerations := append(append([]corev1.Toleration{}, spec.Tolerations...), daemonSetImplicitTolerations...)
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !tolerationsTolerate(tolerations, taint) {
+			return false, fmt.Sprintf("未容忍污点 %s=%s:%s", taint.Key, taint.Value, taint.Effect)
+		}
+	}
+	return true, ""
+}
+
+// tolerationsTolerate 任一容忍匹配污点即可
+func tolerationsTolerate(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorTermsMatch 多个 term 之间为 OR，term 内表达式为 AND（matchFields 仅支持 metadata.name）
+func nodeSelectorTermsMatch(terms []corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	for _, term := range terms {
+		if nodeSelectorTermMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorTermMatches 判断单个 term 是否匹配节点
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		if !nodeSelectorRequirementMatches(req, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		if req.Key != "metadata.name" {
+			return false
+		}
+		// 节点名可能超过标签值长度限制，直接比较
+		found := slices.Contains(req.Values, node.Name)
+		if (req.Operator == corev1.NodeSelectorOpIn) != found || (req.Operator != corev1.NodeSelectorOpIn && req.Operator != corev1.NodeSelectorOpNotIn) {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeSelectorRequirementMatches 匹配单个表达式（Gt/Lt 按整数比较）
+func nodeSelectorRequirementMatches(req corev1.NodeSelectorRequirement, set labels.Set) bool {
+	switch req.Operator {
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if len(req.Values) != 1 || !set.Has(req.Key) {
+			return false
+		}
+		actual, err1 := strconv.ParseInt(set.Get(req.Key), 10, 64)
+		want, err2 := strconv.ParseInt(req.Values[0], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return actual > want
+		}
+		return actual < want
+	}
+	op := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	}[req.Operator]
+	if op == "" {
+		return false
+	}
+	r, err := labels.NewRequirement(req.Key, op, req.Values)
+	if err != nil {
+		return false
+	}
+	return r.Matches(set)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestDaemonSetNodeEligible 按污点容忍、nodeSelector 与节点亲和性判断 DaemonSet 能否调度到节点
+func TestDaemonSetNodeEligible(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"role": "edge", "cores": "8"}},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			{Key: "dedicated", Value: "edge", Effect: corev1.TaintEffectNoSchedule},
+			{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule},
+		}},
+	}
+	tolerateDedicated := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "edge", Effect: corev1.TaintEffectNoSchedule}}
+	affinity := func(req corev1.NodeSelectorRequirement, fields ...corev1.NodeSelectorRequirement) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{req}, MatchFields: fields},
+			}},
+		}}
+	}
+
+	cases := []struct {
+		name string
+		spec corev1.PodSpec
+		want bool
+	}{
+		{name: "untolerated taint", spec: corev1.PodSpec{}},
+		{name: "tolerated (unschedulable is implicit)", spec: corev1.PodSpec{Tolerations: tolerateDedicated}, want: true},
+		{name: "nodeSelector mismatch", spec: corev1.PodSpec{Tolerations: tolerateDedicated, NodeSelector: map[string]string{"role": "core"}}},
+		{name: "affinity In", spec: corev1.PodSpec{Tolerations: tolerateDedicated, Affinity: affinity(
+			corev1.NodeSelectorRequirement{Key: "role", Operator: corev1.NodeSelectorOpIn, Values: []string{"edge", "core"}})}, want: true},
+		{name: "affinity Gt", spec: corev1.PodSpec{Tolerations: tolerateDedicated, Affinity: affinity(
+			corev1.NodeSelectorRequirement{Key: "cores", Operator: corev1.NodeSelectorOpGt, Values: []string{"16"}})}},
+		{name: "matchFields name", spec: corev1.PodSpec{Tolerations: tolerateDedicated, Affinity: affinity(
+			corev1.NodeSelectorRequirement{Key: "role", Operator: corev1.NodeSelectorOpExists},
+			corev1.NodeSelectorRequirement{Key: "metadata.name", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"node-1"}})}},
+	}
+	for _, tc := range cases {
+		got, reason := daemonSetNodeEligible(&tc.spec, node)
+		if got != tc.want || (!got && reason == "") {
+			t.Errorf("%s: eligible=%v reason=%q, want %v", tc.name, got, reason, tc.want)
+		}
+	}
+}
@@ -2,13 +2,36 @@ package service
 
 import (
 	"context"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/kube-admin/kube-admin/backend/internal/model"
 	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// dashboardStatsTTL 统计结果在每个集群上的缓存时间
+const dashboardStatsTTL = 15 * time.Second
+
+// dashboardCache 集群最近一次的统计结果，挂在集群客户端上
+type dashboardCache struct {
+	sync.Mutex
+	stats *DashboardStats
+}
+
+// dashboardCacheKey 统计缓存在 k8s.Client.Shared 中的键
+type dashboardCacheKey struct{}
+
+// dashboardCacheOf 返回集群的统计缓存
+func dashboardCacheOf(client *k8s.Client) *dashboardCache {
+	return client.Shared(dashboardCacheKey{}, func() interface{} { return &dashboardCache{} }).(*dashboardCache)
+}
+
 // DashboardService Dashboard统计服务
 type DashboardService struct {
 	k8sClient *k8s.Client
@@ -30,6 +53,73 @@ type DashboardStats struct {
 	SecretCount     int            `json:"secret_count"`
 	PodStatusStats  PodStatusStats `json:"pod_status_stats"`
 	ClusterUsage    ClusterUsage   `json:"cluster_usage"` // 集群资源实时使用率
+
+	Errors      map[string]string `json:"errors,omitempty"` // 统计失败的分项及原因（如无权限），键见 dashboardSections
+	Stale       []string          `json:"stale,omitempty"`  // 本次失败、沿用上一次成功结果的分项
+	GeneratedAt time.Time         `json:"generated_at"`     // 统计时间
+	Cached      bool              `json:"cached"`           // 是否命中短期缓存
+}
+
+// dashboardSection 统计分项：fill 写入 stats 中各自独占的字段，可并发执行；
+// keep 在本次失败时从上一次结果中沿用该分项
+type dashboardSection struct {
+	name string
+	fill func(ctx context.Context, s *DashboardService, q model.ListQuery, stats *DashboardStats) error
+	keep func(dst, src *DashboardStats)
+}
+
+// countSection 仅需计数的分项：走 metadata 接口，不拉取对象内容（Secret 数据等）
+func countSection(name string, gvr schema.GroupVersionResource, field func(*DashboardStats) *int) dashboardSection {
+	return dashboardSection{
+		name: name,
+		fill: func(ctx context.Context, s *DashboardService, _ model.ListQuery, stats *DashboardStats) error {
+			n, err := countObjects(ctx, s.k8sClient, gvr)
+			*field(stats) = n
+			return err
+		},
+		keep: func(dst, src *DashboardStats) { *field(dst) = *field(src) },
+	}
+}
+
+// dashboardSections 统计分项。节点与 Pod 需要完整对象（使用率、phase），读 informer 缓存
+var dashboardSections = []dashboardSection{
+	{
+		name: "nodes",
+		fill: func(ctx context.Context, s *DashboardService, q model.ListQuery, stats *DashboardStats) error {
+			nodeList, _, err := cachedNodes(ctx, s.k8sClient, q)
+			if err != nil {
+				return err
+			}
+			stats.NodeCount = len(nodeList)
+			s.fillClusterUsage(ctx, stats, nodeList)
+			return nil
+		},
+		keep: func(dst, src *DashboardStats) {
+			dst.NodeCount = src.NodeCount
+			dst.ClusterUsage = src.ClusterUsage
+		},
+	},
+	{
+		name: "pods",
+		fill: func(ctx context.Context, s *DashboardService, q model.ListQuery, stats *DashboardStats) error {
+			podList, _, err := cachedPods(ctx, s.k8sClient, "", q)
+			if err != nil {
+				return err
+			}
+			stats.PodCount = len(podList)
+			stats.PodStatusStats = countPodPhases(podList)
+			return nil
+		},
+		keep: func(dst, src *DashboardStats) {
+			dst.PodCount = src.PodCount
+			dst.PodStatusStats = src.PodStatusStats
+		},
+	},
+	countSection("namespaces", corev1.SchemeGroupVersion.WithResource("namespaces"), func(s *DashboardStats) *int { return &s.NamespaceCount }),
+	countSection("deployments", appsv1.SchemeGroupVersion.WithResource("deployments"), func(s *DashboardStats) *int { return &s.DeploymentCount }),
+	countSection("services", corev1.SchemeGroupVersion.WithResource("services"), func(s *DashboardStats) *int { return &s.ServiceCount }),
+	countSection("configmaps", corev1.SchemeGroupVersion.WithResource("configmaps"), func(s *DashboardStats) *int { return &s.ConfigMapCount }),
+	countSection("secrets", corev1.SchemeGroupVersion.WithResource("secrets"), func(s *DashboardStats) *int { return &s.SecretCount }),
 }
 
 // PodStatusStats Pod状态统计
@@ -51,87 +141,150 @@ type ClusterUsage struct {
 	MemoryCapacity string  `json:"memory_capacity"`
 }
 
-// GetDashboardStats 获取Dashboard统计数据
-func (s *DashboardService) GetDashboardStats() (*DashboardStats, error) {
-	stats := &DashboardStats{}
-
-	// 节点列表（复用：计数 + 使用率聚合）
-	nodeList, err := s.k8sClient.ClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		stats.NodeCount = len(nodeList.Items)
-		s.fillClusterUsage(stats, nodeList)
+// GetDashboardStats 获取Dashboard统计数据。各分项并发统计，结果按集群缓存 dashboardStatsTTL；
+// fresh=true 时跳过缓存并实时 LIST。单个分项失败记入 Errors，若上一次成功过则沿用旧值并标记 Stale。
+func (s *DashboardService) GetDashboardStats(ctx context.Context, fresh bool) (*DashboardStats, error) {
+	cache := dashboardCacheOf(s.k8sClient)
+	cache.Lock()
+	prev := cache.stats
+	cache.Unlock()
+	if !fresh && prev != nil && time.Since(prev.GeneratedAt) < dashboardStatsTTL {
+		cached := *prev
+		cached.Cached = true
+		return &cached, nil
 	}
 
-	// 命名空间数
-	nsList, err := s.k8sClient.ClientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		stats.NamespaceCount = len(nsList.Items)
+	stats := &DashboardStats{GeneratedAt: time.Now()}
+	q := model.ListQuery{Fresh: fresh}
+	errs := make([]error, len(dashboardSections))
+	var wg sync.WaitGroup
+	for i, section := range dashboardSections {
+		wg.Add(1)
+		go func(i int, section dashboardSection) {
+			defer wg.Done()
+			errs[i] = section.fill(ctx, s, q, stats)
+		}(i, section)
 	}
+	wg.Wait()
 
-	// 统计所有命名空间的资源
-	podList, err := s.k8sClient.ClientSet.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		stats.PodCount = len(podList.Items)
-		for _, pod := range podList.Items {
-			switch pod.Status.Phase {
-			case "Running":
-				stats.PodStatusStats.Running++
-			case "Pending":
-				stats.PodStatusStats.Pending++
-			case "Failed":
-				stats.PodStatusStats.Failed++
-			case "Succeeded":
-				stats.PodStatusStats.Succeeded++
-			default:
-				stats.PodStatusStats.Unknown++
-			}
+	for i, section := range dashboardSections {
+		if errs[i] == nil {
+			continue
+		}
+		if stats.Errors == nil {
+			stats.Errors = map[string]string{}
+		}
+		stats.Errors[section.name] = errs[i].Error()
+		// 上一次成功或本身沿用了更早的值时，继续沿用
+		if prev != nil && (prev.Errors[section.name] == "" || slices.Contains(prev.Stale, section.name)) {
+			section.keep(stats, prev)
+			stats.Stale = append(stats.Stale, section.name)
 		}
 	}
 
-	deployList, err := s.k8sClient.ClientSet.AppsV1().Deployments("").List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		stats.DeploymentCount = len(deployList.Items)
+	// 请求被取消时结果不完整，不写入缓存
+	if ctx.Err() == nil {
+		cache.Lock()
+		cache.stats = stats
+		cache.Unlock()
 	}
+	return stats, nil
+}
 
-	svcList, err := s.k8sClient.ClientSet.CoreV1().Services("").List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		stats.ServiceCount = len(svcList.Items)
+// countObjects 统计资源总数：仅取元数据，limit=1 时 apiserver 返回 remainingItemCount；
+// 不支持时退化为元数据分页累加
+func countObjects(ctx context.Context, client *k8s.Client, gvr schema.GroupVersionResource) (int, error) {
+	opts := metav1.ListOptions{Limit: 1}
+	list, err := client.MetadataClient.Resource(gvr).List(ctx, opts)
+	if err != nil {
+		return 0, err
 	}
-
-	cmList, err := s.k8sClient.ClientSet.CoreV1().ConfigMaps("").List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		stats.ConfigMapCount = len(cmList.Items)
+	count := len(list.Items)
+	if list.GetContinue() == "" {
+		return count, nil
+	}
+	if remaining := list.GetRemainingItemCount(); remaining != nil {
+		return count + int(*remaining), nil
+	}
+	opts.Limit = 500
+	for opts.Continue = list.GetContinue(); opts.Continue != ""; opts.Continue = list.GetContinue() {
+		if list, err = client.MetadataClient.Resource(gvr).List(ctx, opts); err != nil {
+			return 0, err
+		}
+		count += len(list.Items)
 	}
+	return count, nil
+}
+
+// fillClusterUsage 聚合所有节点的实时资源使用率。metrics-server 不可用时使用率为 0。
+func (s *DashboardService) fillClusterUsage(ctx context.Context, stats *DashboardStats, nodeList []*corev1.Node) {
+	var usage usageTotals
+	usage.addNodes(nodeList, nodeMetricsMap(ctx, s.k8sClient))
+	stats.ClusterUsage = usage.clusterUsage()
+}
 
-	secretList, err := s.k8sClient.ClientSet.CoreV1().Secrets("").List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		stats.SecretCount = len(secretList.Items)
+// countPodPhases 按 phase 统计 Pod 数
+func countPodPhases(pods []*corev1.Pod) PodStatusStats {
+	var stats PodStatusStats
+	for _, pod := range pods {
+		switch pod.Status.Phase {
+		case "Running":
+			stats.Running++
+		case "Pending":
+			stats.Pending++
+		case "Failed":
+			stats.Failed++
+		case "Succeeded":
+			stats.Succeeded++
+		default:
+			stats.Unknown++
+		}
 	}
+	return stats
+}
 
-	return stats, nil
+// add 累加另一组 Pod 状态统计
+func (p *PodStatusStats) add(o PodStatusStats) {
+	p.Running += o.Running
+	p.Pending += o.Pending
+	p.Failed += o.Failed
+	p.Succeeded += o.Succeeded
+	p.Unknown += o.Unknown
 }
 
-// fillClusterUsage 聚合所有节点的实时资源使用率。metrics-server 不可用时使用率为 0。
-func (s *DashboardService) fillClusterUsage(stats *DashboardStats, nodeList *corev1.NodeList) {
-	metricsMap := nodeMetricsMap(s.k8sClient)
-
-	var usedCPU, allocCPU, usedMem, allocMem resource.Quantity
-	for i := range nodeList.Items {
-		node := &nodeList.Items[i]
-		allocCPU.Add(node.Status.Allocatable[corev1.ResourceCPU])
-		allocMem.Add(node.Status.Allocatable[corev1.ResourceMemory])
+// usageTotals 资源使用量/可分配量累加器，可跨节点、跨集群累加
+type usageTotals struct {
+	usedCPU, allocCPU, usedMem, allocMem resource.Quantity
+}
+
+// addNodes 累加节点可分配量与实时使用量（metricsMap 为 nil 时仅累加可分配量）
+func (u *usageTotals) addNodes(nodeList []*corev1.Node, metricsMap map[string]corev1.ResourceList) {
+	for _, node := range nodeList {
+		u.allocCPU.Add(node.Status.Allocatable[corev1.ResourceCPU])
+		u.allocMem.Add(node.Status.Allocatable[corev1.ResourceMemory])
 		if usage, ok := metricsMap[node.Name]; ok {
-			usedCPU.Add(usage[corev1.ResourceCPU])
-			usedMem.Add(usage[corev1.ResourceMemory])
+			u.usedCPU.Add(usage[corev1.ResourceCPU])
+			u.usedMem.Add(usage[corev1.ResourceMemory])
 		}
 	}
+}
+
+// add 累加另一组使用量
+func (u *usageTotals) add(o usageTotals) {
+	u.usedCPU.Add(o.usedCPU)
+	u.allocCPU.Add(o.allocCPU)
+	u.usedMem.Add(o.usedMem)
+	u.allocMem.Add(o.allocMem)
+}
 
-	stats.ClusterUsage = ClusterUsage{
-		CPUPercent:     calcPercent(usedCPU, allocCPU),
-		MemoryPercent:  calcPercent(usedMem, allocMem),
-		CPUUsed:        usedCPU.String(),
-		CPUCapacity:    allocCPU.String(),
-		MemoryUsed:     usedMem.String(),
-		MemoryCapacity: allocMem.String(),
+// clusterUsage 转为接口输出结构
+func (u *usageTotals) clusterUsage() ClusterUsage {
+	return ClusterUsage{
+		CPUPercent:     calcPercent(u.usedCPU, u.allocCPU),
+		MemoryPercent:  calcPercent(u.usedMem, u.allocMem),
+		CPUUsed:        u.usedCPU.String(),
+		CPUCapacity:    u.allocCPU.String(),
+		MemoryUsed:     u.usedMem.String(),
+		MemoryCapacity: u.allocMem.String(),
 	}
 }
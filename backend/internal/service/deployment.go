@@ -7,6 +7,7 @@ import (
 	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // DeploymentService Deployment服务
@@ -24,24 +25,21 @@ func (s *DeploymentService) GetK8sClient() *k8s.Client {
 	return s.k8sClient
 }
 
-// ListDeployments 获取Deployment列表
-func (s *DeploymentService) ListDeployments(namespace string) ([]model.DeploymentInfo, error) {
-	deploymentList, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+// ListDeployments 获取Deployment列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *DeploymentService) ListDeployments(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedDeployments(ctx, s.k8sClient, namespace, q)
 	if err != nil {
 		return nil, err
 	}
 
-	var deployments []model.DeploymentInfo
-	for _, deploy := range deploymentList.Items {
-		deployments = append(deployments, s.convertDeployment(&deploy))
-	}
-
-	return deployments, nil
+	return pageList(list, listMeta, q, func(deploy *appsv1.Deployment) model.DeploymentInfo {
+		return s.convertDeployment(deploy)
+	}), nil
 }
 
 // GetDeployment 获取Deployment详情
-func (s *DeploymentService) GetDeployment(namespace, name string) (*model.DeploymentInfo, error) {
-	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+func (s *DeploymentService) GetDeployment(ctx context.Context, namespace, name string) (*model.DeploymentInfo, error) {
+	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -51,25 +49,30 @@ func (s *DeploymentService) GetDeployment(namespace, name string) (*model.Deploy
 }
 
 // DeleteDeployment 删除Deployment
-func (s *DeploymentService) DeleteDeployment(namespace, name string) error {
-	return s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (s *DeploymentService) DeleteDeployment(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
 }
 
-// ScaleDeployment 扩缩容Deployment
-func (s *DeploymentService) ScaleDeployment(namespace, name string, replicas int32) error {
-	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+// ScaleDeployment 扩缩容Deployment。副本数由 HPA 管理时默认拒绝（HPAConflictError），
+// force 为 true 时照常执行并返回提示；dryRun 时不持久化
+func (s *DeploymentService) ScaleDeployment(ctx context.Context, namespace, name string, replicas int32, force, dryRun bool) (string, error) {
+	warning, err := hpaScaleGuard(ctx, s.k8sClient, namespace, schema.GroupKind{Group: "apps", Kind: "Deployment"}, name, force)
 	if err != nil {
-		return err
+		return "", err
+	}
+	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
 	}
 
 	deploy.Spec.Replicas = &replicas
-	_, err = s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Update(context.TODO(), deploy, metav1.UpdateOptions{})
-	return err
+	_, err = s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Update(ctx, deploy, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
+	return warning, err
 }
 
 // RestartDeployment 重启Deployment
-func (s *DeploymentService) RestartDeployment(namespace, name string) error {
-	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+func (s *DeploymentService) RestartDeployment(ctx context.Context, namespace, name string) error {
+	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -79,7 +82,7 @@ func (s *DeploymentService) RestartDeployment(namespace, name string) error {
 	}
 	deploy.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = metav1.Now().Format("2006-01-02T15:04:05Z")
 
-	_, err = s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Update(context.TODO(), deploy, metav1.UpdateOptions{})
+	_, err = s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Update(ctx, deploy, metav1.UpdateOptions{})
 	return err
 }
 
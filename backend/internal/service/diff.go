@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// 结构化差异中的字段操作
+const (
+	fieldAdd     = "add"
+	fieldRemove  = "remove"
+	fieldReplace = "replace"
+)
+
+// volatileMetadata 每次写入都会变化或由服务端分配的元数据，对比时忽略
+var volatileMetadata = []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp", "selfLink"}
+
+// DiffFromYAML 对 YAML 中的每个对象做一次 server-side apply 试运行（DryRun=All），
+// 返回线上对象与试运行结果之间的差异，即真正 apply 时会发生的变更；不修改集群中的任何对象。
+func (s *ResourceService) DiffFromYAML(ctx context.Context, yamlStr string, opts model.ApplyOptions) (*model.DiffResult, error) {
+	objs, err := decodeManifests(yamlStr)
+	if err != nil {
+		return nil, err
+	}
+	sortManifests(objs)
+	opts.DryRun = true
+	result := &model.DiffResult{Results: make([]model.ResourceDiff, 0, len(objs)), Summary: map[string]int{}}
+	for _, obj := range objs {
+		r := s.diffObject(ctx, obj, opts)
+		result.Results = append(result.Results, r)
+		result.Summary[r.Action]++
+	}
+	return result, nil
+}
+
+// diffObject 试运行单个对象并计算差异
+func (s *ResourceService) diffObject(ctx context.Context, obj *unstructured.Unstructured, opts model.ApplyOptions) model.ResourceDiff {
+	r := model.ResourceDiff{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+	live, merged, err := s.serverSideApply(ctx, obj, opts, false)
+	if err != nil {
+		r.Action, r.Error = applyFailed, err.Error()
+		if r.Conflicts = applyConflicts(err); len(r.Conflicts) > 0 {
+			r.Action = applyConflict
+		}
+		return r
+	}
+	r.Namespace, r.Name = merged.GetNamespace(), merged.GetName()
+
+	var before map[string]interface{}
+	if live != nil {
+		before = diffableObject(live)
+	}
+	after := diffableObject(merged)
+	if obj.GetKind() == "Secret" {
+		maskSecretData(before, after)
+	}
+
+	r.Changes = fieldChanges("", before, after)
+	switch {
+	case live == nil:
+		r.Action = applyCreated
+	case len(r.Changes) == 0:
+		r.Action = applyUnchanged
+		return r
+	default:
+		r.Action = applyConfigured
+	}
+	if r.Diff, err = unifiedDiff(before, after, "live", "dry-run"); err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// diffableObject 去掉 status 与服务端维护的元数据，只保留声明式内容
+func diffableObject(u *unstructured.Unstructured) map[string]interface{} {
+	obj := u.DeepCopy().Object
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range volatileMetadata {
+			delete(metadata, field)
+		}
+	}
+	return obj
+}
+
+// maskSecretData 遮蔽 Secret 的 data/stringData，只体现键是否变化（同 kubectl diff）
+func maskSecretData(before, after map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		b, _ := before[field].(map[string]interface{})
+		a, _ := after[field].(map[string]interface{})
+		for key, value := range a {
+			if other, ok := b[key]; ok && reflect.DeepEqual(value, other) {
+				a[key] = "***"
+			} else {
+				a[key] = "*** (after)"
+			}
+		}
+		for key := range b {
+			if a[key] == "***" {
+				b[key] = "***"
+			} else {
+				b[key] = "*** (before)"
+			}
+		}
+	}
+}
+
+// unifiedDiff 以 YAML 形式输出两个对象的 unified diff，对象为 nil 时视为空文件
+func unifiedDiff(before, after map[string]interface{}, beforeName, afterName string) (string, error) {
+	toYAML := func(obj map[string]interface{}) (string, error) {
+		if obj == nil {
+			return "", nil
+		}
+		data, err := yaml.Marshal(obj)
+		return string(data), err
+	}
+	a, err := toYAML(before)
+	if err != nil {
+		return "", err
+	}
+	b, err := toYAML(after)
+	if err != nil {
+		return "", err
+	}
+	return textDiff(a, b, beforeName, afterName)
+}
+
+// textDiff 两段文本的 unified diff（3 行上下文），内容一致时为空
+func textDiff(a, b, aName, bName string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: aName,
+		ToFile:   bName,
+		Context:  3,
+	})
+}
+
+// fieldChanges 递归比较两个值，返回按路径排列的叶子级变更。
+// map 按键逐个比较；等长列表按下标比较，长度不同时整体替换。
+func fieldChanges(path string, before, after interface{}) []model.FieldChange {
+	b, bIsMap := before.(map[string]interface{})
+	a, aIsMap := after.(map[string]interface{})
+	if bIsMap && aIsMap {
+		keys := map[string]bool{}
+		for k := range b {
+			keys[k] = true
+		}
+		for k := range a {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		var changes []model.FieldChange
+		for _, k := range sorted {
+			child := fieldPath(path, k)
+			bv, inBefore := b[k]
+			av, inAfter := a[k]
+			switch {
+			case !inBefore:
+				changes = append(changes, model.FieldChange{Path: child, Op: fieldAdd, New: av})
+			case !inAfter:
+				changes = append(changes, model.FieldChange{Path: child, Op: fieldRemove, Old: bv})
+			default:
+				changes = append(changes, fieldChanges(child, bv, av)...)
+			}
+		}
+		return changes
+	}
+
+	bl, bIsList := before.([]interface{})
+	al, aIsList := after.([]interface{})
+	if bIsList && aIsList && len(bl) == len(al) {
+		var changes []model.FieldChange
+		for i := range bl {
+			changes = append(changes, fieldChanges(fmt.Sprintf("%s[%d]", path, i), bl[i], al[i])...)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return []model.FieldChange{{Path: path, Op: fieldReplace, Old: before, New: after}}
+}
+
+// fieldPath 拼接字段路径，含 "." 或 "/" 的键（如注解、标签）用 ["..."] 表示
+func fieldPath(parent, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf(`%s["%s"]`, parent, key)
+	}
+	return parent + "." + key
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestFieldChanges 按字段路径列出新增、删除与修改
+func TestFieldChanges(t *testing.T) {
+	before := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "web",
+			"annotations": map[string]interface{}{"app.kubernetes.io/owner": "a"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "nginx:1.25"},
+			},
+			"paused": true,
+		},
+	}
+	after := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "web",
+			"annotations": map[string]interface{}{"app.kubernetes.io/owner": "b"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "nginx:1.26"},
+			},
+			"selector": "app=web",
+		},
+	}
+	got := map[string]string{}
+	for _, c := range fieldChanges("", before, after) {
+		got[c.Path] = c.Op
+	}
+	want := map[string]string{
+		`.metadata.annotations["app.kubernetes.io/owner"]`: fieldReplace,
+		".spec.containers[0].image":                        fieldReplace,
+		".spec.paused":                                     fieldRemove,
+		".spec.replicas":                                   fieldReplace,
+		".spec.selector":                                   fieldAdd,
+	}
+	if len(got) != len(want) {
+		t.Errorf("changes = %v", got)
+	}
+	for path, op := range want {
+		if got[path] != op {
+			t.Errorf("%s: got %q, want %q", path, got[path], op)
+		}
+	}
+
+	if changes := fieldChanges("", before, before); len(changes) != 0 {
+		t.Errorf("identical objects should have no changes, got %+v", changes)
+	}
+	// 新建对象：顶层字段全部为 add
+	var none map[string]interface{}
+	for _, c := range fieldChanges("", none, after) {
+		if c.Op != fieldAdd {
+			t.Errorf("created object change %+v", c)
+		}
+	}
+}
+
+// TestDiffableObjectAndSecretMask diff 前去掉易变字段，Secret 的值被遮蔽只保留是否变化
+func TestDiffableObjectAndSecretMask(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":            "db",
+			"resourceVersion": "42",
+			"managedFields":   []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"data":   map[string]interface{}{"user": "YQ==", "password": "b2xk"},
+		"status": map[string]interface{}{},
+	}}
+	before := diffableObject(u)
+	if _, ok := before["status"]; ok {
+		t.Error("status should be stripped")
+	}
+	if md := before["metadata"].(map[string]interface{}); md["resourceVersion"] != nil || md["managedFields"] != nil {
+		t.Errorf("volatile metadata not stripped: %v", md)
+	}
+	if u.GetResourceVersion() != "42" {
+		t.Error("original object should be left untouched")
+	}
+
+	after := diffableObject(u)
+	after["data"].(map[string]interface{})["password"] = "bmV3"
+	after["data"].(map[string]interface{})["token"] = "dA=="
+	maskSecretData(before, after)
+	diff, err := unifiedDiff(before, after, "live", "dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"YQ==", "b2xk", "bmV3", "dA=="} {
+		if strings.Contains(diff, secret) {
+			t.Errorf("diff leaks secret value %s:\n%s", secret, diff)
+		}
+	}
+	for _, want := range []string{"-  password: '*** (before)'", "+  password: '*** (after)'", "+  token: '*** (after)'", "   user: '***'"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+}
@@ -21,20 +21,19 @@ func NewEventService(k8sClient *k8s.Client) *EventService {
 	return &EventService{k8sClient: k8sClient}
 }
 
-// ListEvents 查询事件。fieldSelector 可按 involvedObject.kind/name 过滤。
-func (s *EventService) ListEvents(namespace, fieldSelector string) ([]model.EventInfo, error) {
-	list, err := s.k8sClient.ClientSet.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
-		FieldSelector: fieldSelector,
-	})
+// ListEvents 查询事件。fieldSelector 可按 involvedObject.kind/name 过滤，与 q 中的字段选择器合并。
+func (s *EventService) ListEvents(ctx context.Context, namespace, fieldSelector string, q model.ListQuery) (*model.PageResponse, error) {
+	if fieldSelector != "" && q.FieldSelector != "" {
+		q.FieldSelector = fieldSelector + "," + q.FieldSelector
+	} else if fieldSelector != "" {
+		q.FieldSelector = fieldSelector
+	}
+	list, err := s.k8sClient.ClientSet.CoreV1().Events(namespace).List(ctx, listOptions(q))
 	if err != nil {
 		return nil, err
 	}
 
-	events := make([]model.EventInfo, 0, len(list.Items))
-	for i := range list.Items {
-		events = append(events, convertEvent(&list.Items[i]))
-	}
-	return events, nil
+	return pageList(itemPointers(list.Items), list.ListMeta, q, convertEvent), nil
 }
 
 // convertEvent 转换 Event 对象
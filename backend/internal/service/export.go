@@ -0,0 +1,311 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// exportSkipResources 导出整个命名空间时默认跳过的资源（resource.group）：由控制器或集群自动生成、
+// 在目标集群会重新产生的数据。通过 kinds 显式指定时仍可导出
+var exportSkipResources = map[string]bool{
+	"events":                                         true,
+	"events.events.k8s.io":                           true,
+	"endpoints":                                      true,
+	"endpointslices.discovery.k8s.io":                true,
+	"controllerrevisions.apps":                       true,
+	"leases.coordination.k8s.io":                     true,
+	"pods.metrics.k8s.io":                            true,
+	"csistoragecapacities.storage.k8s.io":            true,
+	"policyreports.wgpolicyk8s.io":                   true,
+	"localsubjectaccessreviews.authorization.k8s.io": true,
+}
+
+// exportStripMetadata 导出时去掉的元数据：服务端维护的字段，以及在目标集群无效的 ownerReferences（owner UID 不同）
+var exportStripMetadata = append([]string{"ownerReferences", "deletionTimestamp", "deletionGracePeriodSeconds"}, volatileMetadata...)
+
+// exportStripAnnotations 由客户端工具或控制器写入、重新 apply 时不应带上的注解
+var exportStripAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/selected-node",
+}
+
+// jobControllerLabels Job 控制器自动生成的 selector 标签，带着旧 UID 重新创建会被拒绝
+var jobControllerLabels = []string{"controller-uid", "batch.kubernetes.io/controller-uid"}
+
+// ExportedObjects 导出结果：清理后的对象（按 apply 依赖顺序），以及部分资源无法读取时的提示
+type ExportedObjects struct {
+	Objects  []*unstructured.Unstructured
+	Warnings []string
+}
+
+// ExportObject 获取单个对象并清理为可直接 apply 的清单
+func (s *ResourceService) ExportObject(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := s.Get(ctx, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return CleanForExport(obj), nil
+}
+
+// ExportNamespace 导出命名空间内的资源（含 Namespace 对象本身）。kinds 为空时导出通过发现 API 找到的全部
+// 可 list 的命名空间级资源（跳过 exportSkipResources）；否则只导出匹配的资源，支持 resource、resource.group、
+// Kind 或简称。由控制器管理的对象（有 controller ownerReference，如 Deployment 的 ReplicaSet/Pod）
+// 会由目标集群的控制器重新生成，includeOwned=false 时跳过；ServiceAccount token Secret 与 kube-root-ca.crt 同理。
+func (s *ResourceService) ExportNamespace(ctx context.Context, namespace string, kinds []string, includeOwned bool) (*ExportedObjects, error) {
+	result := &ExportedObjects{}
+	ns, err := s.Get(ctx, schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, "", namespace)
+	if err != nil {
+		return nil, err
+	}
+	result.Objects = append(result.Objects, CleanForExport(ns))
+
+	resources, err := s.exportResources(kinds)
+	if err != nil {
+		return nil, err
+	}
+	for _, gvr := range resources {
+		items, err := s.listAll(ctx, gvr, namespace)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", resourceKey(gvr), err))
+			continue
+		}
+		for i := range items {
+			if !includeOwned && !exportable(&items[i]) {
+				continue
+			}
+			result.Objects = append(result.Objects, CleanForExport(&items[i]))
+		}
+	}
+	sortManifests(result.Objects)
+	return result, nil
+}
+
+// exportResources 解析要导出的命名空间级资源；kinds 中无法识别的项直接报错
+func (s *ResourceService) exportResources(kinds []string) ([]schema.GroupVersionResource, error) {
+	all, err := s.APIResources(false)
+	if err != nil {
+		return nil, err
+	}
+	var gvrs []schema.GroupVersionResource
+	if len(kinds) == 0 {
+		for _, r := range all {
+			gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+			if r.Namespaced && slices.Contains(r.Verbs, "list") && !exportSkipResources[resourceKey(gvr)] {
+				gvrs = append(gvrs, gvr)
+			}
+		}
+		return gvrs, nil
+	}
+
+	seen := map[schema.GroupVersionResource]bool{}
+	for _, kind := range kinds {
+		found := false
+		for _, r := range all {
+			if !r.Namespaced || !matchResourceArg(kind, r.Group, r.Resource, r.Kind, r.ShortNames) {
+				continue
+			}
+			found = true
+			gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+			if !seen[gvr] {
+				seen[gvr] = true
+				gvrs = append(gvrs, gvr)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("未找到命名空间级资源类型: %s", kind)
+		}
+	}
+	return gvrs, nil
+}
+
+// listAll 分页列出命名空间内某资源的全部对象
+func (s *ResourceService) listAll(ctx context.Context, gvr schema.GroupVersionResource, namespace string) ([]unstructured.Unstructured, error) {
+	iface := s.k8sClient.DynamicClient.Resource(gvr).Namespace(namespace)
+	opts := metav1.ListOptions{Limit: 500}
+	var items []unstructured.Unstructured
+	for {
+		list, err := iface.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, list.Items...)
+		if opts.Continue = list.GetContinue(); opts.Continue == "" {
+			return items, nil
+		}
+	}
+}
+
+// exportable 对象是否需要导出：跳过由控制器生成、会在目标集群自动重建的对象
+func exportable(u *unstructured.Unstructured) bool {
+	if metav1.GetControllerOf(u) != nil {
+		return false
+	}
+	switch u.GetKind() {
+	case "Secret":
+		secretType, _, _ := unstructured.NestedString(u.Object, "type")
+		return secretType != "kubernetes.io/service-account-token"
+	case "ConfigMap":
+		return u.GetName() != "kube-root-ca.crt"
+	}
+	return true
+}
+
+// matchResourceArg 资源参数是否匹配：resource、resource.group、Kind 或简称（不区分大小写）
+func matchResourceArg(arg, group, resource, kind string, shortNames []string) bool {
+	arg = strings.ToLower(arg)
+	name, argGroup, hasGroup := strings.Cut(arg, ".")
+	if hasGroup && argGroup != group {
+		return false
+	}
+	if name == resource || name == strings.ToLower(kind) {
+		return true
+	}
+	for _, short := range shortNames {
+		if name == short {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceKey 资源的 resource.group 表示，核心组只有 resource
+func resourceKey(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Resource
+	}
+	return gvr.Resource + "." + gvr.Group
+}
+
+// CleanForExport 返回去掉服务端填充字段后的副本，可直接在其他集群 apply：
+// status、服务端维护的元数据与注解、Service 分配的 ClusterIP、已绑定 PVC 的 volumeName、
+// Pod 的调度结果、Job 自动生成的 selector 等
+func CleanForExport(u *unstructured.Unstructured) *unstructured.Unstructured {
+	out := &unstructured.Unstructured{Object: diffableObject(u)}
+	metadata, _ := out.Object["metadata"].(map[string]interface{})
+	for _, field := range exportStripMetadata {
+		delete(metadata, field)
+	}
+	if out.GetName() != "" {
+		delete(metadata, "generateName")
+	}
+	if annotations := out.GetAnnotations(); annotations != nil {
+		for _, key := range exportStripAnnotations {
+			delete(annotations, key)
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		out.SetAnnotations(annotations)
+	}
+
+	// 模板中由类型化对象序列化出的 creationTimestamp: null
+	unstructured.RemoveNestedField(out.Object, "spec", "template", "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(out.Object, "spec", "jobTemplate", "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(out.Object, "spec", "jobTemplate", "spec", "template", "metadata", "creationTimestamp")
+
+	switch out.GetKind() {
+	case "Namespace":
+		unstructured.RemoveNestedField(out.Object, "spec")
+	case "Service":
+		// headless（None）是用户声明的，其余 ClusterIP 由集群分配，在目标集群可能冲突或超出 Service CIDR
+		if ip, _, _ := unstructured.NestedString(out.Object, "spec", "clusterIP"); ip != "None" {
+			unstructured.RemoveNestedField(out.Object, "spec", "clusterIP")
+			unstructured.RemoveNestedField(out.Object, "spec", "clusterIPs")
+		}
+		unstructured.RemoveNestedField(out.Object, "spec", "healthCheckNodePort")
+		if policy, _, _ := unstructured.NestedString(out.Object, "spec", "ipFamilyPolicy"); policy == "SingleStack" {
+			unstructured.RemoveNestedField(out.Object, "spec", "ipFamilyPolicy")
+			unstructured.RemoveNestedField(out.Object, "spec", "ipFamilies")
+		}
+	case "PersistentVolumeClaim":
+		unstructured.RemoveNestedField(out.Object, "spec", "volumeName")
+	case "Pod":
+		unstructured.RemoveNestedField(out.Object, "spec", "nodeName")
+	case "Job":
+		if manual, _, _ := unstructured.NestedBool(out.Object, "spec", "manualSelector"); !manual {
+			unstructured.RemoveNestedField(out.Object, "spec", "selector")
+			for _, fields := range [][]string{{"metadata", "labels"}, {"spec", "template", "metadata", "labels"}} {
+				for _, label := range jobControllerLabels {
+					unstructured.RemoveNestedField(out.Object, append(fields, label)...)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// MarshalManifests 将对象序列化为多文档 YAML
+func MarshalManifests(objs []*unstructured.Unstructured) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// ZipManifests 将对象打包为 zip，每个对象一个文件：<namespace>/<kind[.group]>/<name>.yaml，集群级对象位于 _cluster 目录下
+func ZipManifests(objs []*unstructured.Unstructured, notes string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		w, err := zw.Create(manifestFileName(obj))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if notes != "" {
+		w, err := zw.Create("WARNINGS.txt")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(notes)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// manifestFileName 对象在 zip 中的路径
+func manifestFileName(obj *unstructured.Unstructured) string {
+	dir := obj.GetNamespace()
+	if dir == "" {
+		dir = "_cluster"
+	}
+	kind := strings.ToLower(obj.GetKind())
+	if group := obj.GroupVersionKind().Group; group != "" {
+		kind += "." + group
+	}
+	return path.Join(dir, kind, obj.GetName()+".yaml")
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const liveObjects = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: shop
+  uid: 1b2c
+  resourceVersion: "42"
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+  managedFields:
+  - manager: kubectl
+spec:
+  clusterIP: 10.96.0.10
+  clusterIPs: [10.96.0.10]
+  ipFamilies: [IPv4]
+  ipFamilyPolicy: SingleStack
+  selector: {app: web}
+  ports:
+  - port: 80
+status:
+  loadBalancer: {}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: shop
+spec:
+  clusterIP: None
+  clusterIPs: [None]
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: shop
+  labels:
+    batch.kubernetes.io/controller-uid: 1b2c
+spec:
+  selector:
+    matchLabels:
+      batch.kubernetes.io/controller-uid: 1b2c
+  template:
+    metadata:
+      creationTimestamp: null
+      labels:
+        batch.kubernetes.io/controller-uid: 1b2c
+        job-name: migrate
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: migrate
+        image: migrate:1
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: shop
+  annotations:
+    pv.kubernetes.io/bind-completed: "yes"
+spec:
+  volumeName: pvc-1b2c
+`
+
+// TestCleanForExport 导出时清理服务端字段与集群分配的值，不修改原对象
+func TestCleanForExport(t *testing.T) {
+	objs, err := decodeManifests(liveObjects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := CleanForExport(objs[0])
+	for _, field := range [][]string{
+		{"status"}, {"metadata", "uid"}, {"metadata", "resourceVersion"}, {"metadata", "creationTimestamp"},
+		{"metadata", "managedFields"}, {"metadata", "annotations"}, {"spec", "clusterIP"}, {"spec", "clusterIPs"}, {"spec", "ipFamilies"},
+	} {
+		if _, found, _ := unstructured.NestedFieldNoCopy(svc.Object, field...); found {
+			t.Errorf("service field %v should be removed", field)
+		}
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(objs[0].Object, "status"); !found {
+		t.Error("original object must not be modified")
+	}
+	if ip, _, _ := unstructured.NestedString(CleanForExport(objs[1]).Object, "spec", "clusterIP"); ip != "None" {
+		t.Errorf("headless clusterIP = %q", ip)
+	}
+
+	job := CleanForExport(objs[2])
+	if _, found, _ := unstructured.NestedFieldNoCopy(job.Object, "spec", "selector"); found {
+		t.Error("generated job selector should be removed")
+	}
+	labels, _, _ := unstructured.NestedStringMap(job.Object, "spec", "template", "metadata", "labels")
+	if len(labels) != 1 || labels["job-name"] != "migrate" || len(job.GetLabels()) != 0 {
+		t.Errorf("job labels = %v / %v", labels, job.GetLabels())
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(job.Object, "spec", "template", "metadata", "creationTimestamp"); found {
+		t.Error("template creationTimestamp should be removed")
+	}
+
+	pvc := CleanForExport(objs[3])
+	if _, found, _ := unstructured.NestedFieldNoCopy(pvc.Object, "spec", "volumeName"); found || len(pvc.GetAnnotations()) != 0 {
+		t.Errorf("pvc binding should be removed: %v", pvc.Object)
+	}
+}
+
+// TestExportable 跳过由控制器生成或集群自动创建的对象
+func TestExportable(t *testing.T) {
+	objs, err := decodeManifests(`apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: web-5d9f
+  ownerReferences:
+  - {apiVersion: apps/v1, kind: Deployment, name: web, uid: 1b2c, controller: true}
+---
+apiVersion: v1
+kind: Secret
+metadata: {name: default-token}
+type: kubernetes.io/service-account-token
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: kube-root-ca.crt}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: app-config}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []bool{false, false, false, true} {
+		if got := exportable(objs[i]); got != want {
+			t.Errorf("exportable(%s %s) = %v", objs[i].GetKind(), objs[i].GetName(), got)
+		}
+	}
+}
+
+// TestMatchResourceArg 资源参数可用复数名、resource.group、Kind 或简称匹配
+func TestMatchResourceArg(t *testing.T) {
+	for _, c := range []struct {
+		arg  string
+		want bool
+	}{
+		{"deployments", true}, {"deployments.apps", true}, {"Deployment", true}, {"deploy", true},
+		{"deployments.extensions", false}, {"pods", false},
+	} {
+		if got := matchResourceArg(c.arg, "apps", "deployments", "Deployment", []string{"deploy"}); got != c.want {
+			t.Errorf("matchResourceArg(%s) = %v", c.arg, got)
+		}
+	}
+}
+
+// TestZipManifests zip 按 <命名空间>/<资源>/<名称>.yaml 组织，集群级资源放在 _cluster 下并附警告
+func TestZipManifests(t *testing.T) {
+	objs, err := decodeManifests(liveObjects + `---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: viewer}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ZipManifests(objs, "note")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	want := []string{
+		"shop/service/web.yaml", "shop/service/db.yaml", "shop/job.batch/migrate.yaml",
+		"shop/persistentvolumeclaim/data.yaml", "_cluster/clusterrole.rbac.authorization.k8s.io/viewer.yaml", "WARNINGS.txt",
+	}
+	if len(names) != len(want) {
+		t.Fatalf("zip entries = %v", names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, names[i], want[i])
+		}
+	}
+}
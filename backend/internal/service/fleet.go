@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// fleetClusterTimeout 单个集群的统计期限，超时即视为不可达，不拖慢整体
+	fleetClusterTimeout = 15 * time.Second
+	// fleetConcurrency 同时统计的集群数上限
+	fleetConcurrency = 8
+)
+
+// FleetService 多集群总览服务：并发统计所有已接入集群
+type FleetService struct {
+	defaultClient  *k8s.Client
+	k8sManager     *k8s.Manager
+	clusterService *ClusterService
+}
+
+// NewFleetService 创建多集群总览服务。defaultClient 可为 nil（未配置默认集群）
+func NewFleetService(defaultClient *k8s.Client, k8sManager *k8s.Manager) *FleetService {
+	return &FleetService{
+		defaultClient:  defaultClient,
+		k8sManager:     k8sManager,
+		clusterService: NewClusterService(),
+	}
+}
+
+// ClusterOverview 单集群概览
+type ClusterOverview struct {
+	ClusterID         uint           `json:"cluster_id"` // 0 表示默认集群
+	ClusterName       string         `json:"cluster_name"`
+	Reachable         bool           `json:"reachable"`
+	Error             string         `json:"error,omitempty"`
+	NodeCount         int            `json:"node_count"`
+	NotReadyNodes     []string       `json:"not_ready_nodes"`
+	PodCount          int            `json:"pod_count"`
+	PodStatusStats    PodStatusStats `json:"pod_status_stats"`
+	WarningEventCount int            `json:"warning_event_count"`
+	ClusterUsage      ClusterUsage   `json:"cluster_usage"`
+
+	usage usageTotals // 供舰队合计使用
+}
+
+// FleetTotals 全部可达集群的合计
+type FleetTotals struct {
+	ClusterCount      int            `json:"cluster_count"`
+	ReachableCount    int            `json:"reachable_count"`
+	NodeCount         int            `json:"node_count"`
+	NotReadyNodeCount int            `json:"not_ready_node_count"`
+	PodCount          int            `json:"pod_count"`
+	PodStatusStats    PodStatusStats `json:"pod_status_stats"`
+	WarningEventCount int            `json:"warning_event_count"`
+	ClusterUsage      ClusterUsage   `json:"cluster_usage"`
+}
+
+// FleetOverview 多集群总览
+type FleetOverview struct {
+	Totals   FleetTotals       `json:"totals"`
+	Clusters []ClusterOverview `json:"clusters"`
+}
+
+// fleetTarget 待统计的集群
+type fleetTarget struct {
+	id   uint
+	name string
+	get  func() (*k8s.Client, error)
+}
+
+// GetOverview 并发统计所有集群（默认集群 + 已登记集群）。
+// 每个集群独立超时；不可达集群在结果中标注原因，不影响其余集群。
+func (s *FleetService) GetOverview(ctx context.Context, fresh bool) (*FleetOverview, error) {
+	targets, err := s.targets()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ClusterOverview, len(targets))
+	forEachTarget(targets, func(i int, t fleetTarget) {
+		results[i] = s.clusterOverview(ctx, t, fresh)
+	})
+
+	overview := &FleetOverview{Clusters: results}
+	var usage usageTotals
+	totals := &overview.Totals
+	totals.ClusterCount = len(results)
+	for i := range results {
+		r := &results[i]
+		if !r.Reachable {
+			continue
+		}
+		totals.ReachableCount++
+		totals.NodeCount += r.NodeCount
+		totals.NotReadyNodeCount += len(r.NotReadyNodes)
+		totals.PodCount += r.PodCount
+		totals.PodStatusStats.add(r.PodStatusStats)
+		totals.WarningEventCount += r.WarningEventCount
+		usage.add(r.usage)
+	}
+	totals.ClusterUsage = usage.clusterUsage()
+	return overview, nil
+}
+
+// targets 收集统计对象：默认集群（若已配置）与数据库中登记的全部集群
+func (s *FleetService) targets() ([]fleetTarget, error) {
+	var targets []fleetTarget
+	if s.defaultClient != nil {
+		client := s.defaultClient
+		targets = append(targets, fleetTarget{name: "default", get: func() (*k8s.Client, error) { return client, nil }})
+	}
+	clusters, err := s.clusterService.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	for _, c := range clusters {
+		id := c.ID
+		targets = append(targets, fleetTarget{id: id, name: c.Name, get: func() (*k8s.Client, error) {
+			cluster, err := s.clusterService.GetCluster(id)
+			if err != nil {
+				return nil, err
+			}
+			return s.k8sManager.GetClient(id, cluster)
+		}})
+	}
+	return targets, nil
+}
+
+// forEachTarget 以 fleetConcurrency 为上限并发处理各集群，全部完成后返回
+func forEachTarget(targets []fleetTarget, fn func(i int, t fleetTarget)) {
+	sem := make(chan struct{}, fleetConcurrency)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t fleetTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i, t)
+		}(i, t)
+	}
+	wg.Wait()
+}
+
+// withClusterTimeout 在 fleetClusterTimeout 内执行单集群操作。fn 直接使用带期限的 ctx
+// （含 informer 首次同步等待），到期即中止，不在后台残留对不可达集群的调用。
+func withClusterTimeout[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, fleetClusterTimeout)
+	defer cancel()
+	v, err := fn(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return v, fmt.Errorf("集群响应超时（%s）", fleetClusterTimeout)
+	}
+	return v, err
+}
+
+// clusterOverview 统计单个集群，超时或出错时返回不可达结果
+func (s *FleetService) clusterOverview(ctx context.Context, t fleetTarget, fresh bool) ClusterOverview {
+	o, err := withClusterTimeout(ctx, func(ctx context.Context) (ClusterOverview, error) {
+		return collectClusterOverview(ctx, t, fresh)
+	})
+	if err != nil {
+		return ClusterOverview{ClusterID: t.id, ClusterName: t.name, NotReadyNodes: []string{}, Error: err.Error()}
+	}
+	return o
+}
+
+// collectClusterOverview 读取节点、Pod、Warning 事件与节点 metrics。
+// 节点列表是可达性的判据；其余数据缺失时按 0 处理。
+func collectClusterOverview(ctx context.Context, t fleetTarget, fresh bool) (ClusterOverview, error) {
+	o := ClusterOverview{ClusterID: t.id, ClusterName: t.name, NotReadyNodes: []string{}}
+	client, err := t.get()
+	if err != nil {
+		return o, err
+	}
+	q := model.ListQuery{Fresh: fresh}
+
+	nodes, _, err := cachedNodes(ctx, client, q)
+	if err != nil {
+		return o, err
+	}
+	o.Reachable = true
+	o.NodeCount = len(nodes)
+	for _, node := range nodes {
+		if getNodeStatus(node) != "Ready" {
+			o.NotReadyNodes = append(o.NotReadyNodes, node.Name)
+		}
+	}
+	o.usage.addNodes(nodes, nodeMetricsMap(ctx, client))
+	o.ClusterUsage = o.usage.clusterUsage()
+
+	if pods, _, err := cachedPods(ctx, client, "", q); err == nil {
+		o.PodCount = len(pods)
+		o.PodStatusStats = countPodPhases(pods)
+	}
+
+	// ResourceVersion=0 由 apiserver 缓存应答，避免穿透 etcd
+	events, err := client.ClientSet.CoreV1().Events("").List(ctx, metav1.ListOptions{
+		FieldSelector:   "type=Warning",
+		ResourceVersion: "0",
+	})
+	if err == nil {
+		o.WarningEventCount = len(events.Items)
+	}
+	return o, nil
+}
@@ -0,0 +1,730 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// 关系图节点健康状态
+const (
+	graphHealthy = "Healthy"
+	graphWarning = "Warning"
+	graphError   = "Error"
+	graphUnknown = "Unknown"
+)
+
+// graphFocusKinds 可作为关系图中心的资源（资源名 → Kind）
+var graphFocusKinds = map[string]string{
+	"deployments":  "Deployment",
+	"replicasets":  "ReplicaSet",
+	"statefulsets": "StatefulSet",
+	"daemonsets":   "DaemonSet",
+	"jobs":         "Job",
+	"cronjobs":     "CronJob",
+	"pods":         "Pod",
+	"services":     "Service",
+	"ingresses":    "Ingress",
+}
+
+// graphLeafKinds 被多个 Pod 共享的节点，以某个资源为中心取子图时不从它们向上展开，避免牵出无关工作负载
+var graphLeafKinds = map[string]bool{
+	"Node":                  true,
+	"ConfigMap":             true,
+	"Secret":                true,
+	"PersistentVolumeClaim": true,
+}
+
+// podErrorReasons 视为错误（而非等待中）的 Pod 阻塞原因
+var podErrorReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"OOMKilled":                  true,
+	"Error":                      true,
+}
+
+// GraphService 资源关系图服务
+type GraphService struct {
+	k8sClient *k8s.Client
+}
+
+// NewGraphService 创建关系图服务
+func NewGraphService(k8sClient *k8s.Client) *GraphService {
+	return &GraphService{k8sClient: k8sClient}
+}
+
+// GetGraph 构建命名空间内的资源关系图：
+// Deployment → ReplicaSet → Pod → Node，Service → Endpoints → Pod，Ingress → Service，
+// Pod → ConfigMap/Secret/PVC，HPA/PDB → 工作负载。
+// resource/name 非空时只返回与该资源相关的子图。
+func (s *GraphService) GetGraph(ctx context.Context, namespace, resource, name string) (*model.ResourceGraph, error) {
+	focus := ""
+	if resource != "" || name != "" {
+		kind, ok := graphFocusKinds[resource]
+		if !ok {
+			return nil, fmt.Errorf("不支持以 %s 为中心构建关系图", resource)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("缺少 name 参数")
+		}
+		focus = graphNodeID(kind, namespace, name)
+	}
+
+	objs, warnings, err := s.loadGraphObjects(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	g := buildGraph(objs)
+
+	var graph *model.ResourceGraph
+	if focus == "" {
+		graph = g.graph()
+	} else {
+		if _, ok := g.nodes[focus]; !ok {
+			return nil, apierrors.NewNotFound(schema.GroupResource{Resource: resource}, name)
+		}
+		graph = g.around(focus)
+	}
+	graph.Warnings = warnings
+	return graph, nil
+}
+
+// graphObjects 构建关系图所需的命名空间内对象。
+// configMaps/secrets/pvcs/nodes 为 nil 表示读取失败，相关节点状态记为 Unknown。
+type graphObjects struct {
+	deployments  []*appsv1.Deployment
+	replicaSets  []*appsv1.ReplicaSet
+	statefulSets []*appsv1.StatefulSet
+	daemonSets   []*appsv1.DaemonSet
+	jobs         []*batchv1.Job
+	cronJobs     []*batchv1.CronJob
+	pods         []*corev1.Pod
+	services     []*corev1.Service
+	endpoints    map[string]*corev1.Endpoints
+	ingresses    []*networkingv1.Ingress
+	hpas         []*autoscalingv2.HorizontalPodAutoscaler
+	pdbs         []*policyv1.PodDisruptionBudget
+	configMaps   map[string]bool
+	secrets      map[string]bool
+	pvcs         map[string]*corev1.PersistentVolumeClaim
+	nodes        map[string]*corev1.Node
+}
+
+// loadGraphObjects 读取命名空间内对象（有 informer 的类型读缓存）。
+// 只有 Pod 读取失败时报错，其余类型失败时记入警告并跳过，避免缺少个别权限时整张图不可用。
+func (s *GraphService) loadGraphObjects(ctx context.Context, namespace string) (*graphObjects, []string, error) {
+	client := s.k8sClient
+	q := model.ListQuery{}
+	objs := &graphObjects{}
+	var warnings []string
+	warn := func(kind string, err error) bool {
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("读取 %s 失败: %v", kind, err))
+			return false
+		}
+		return true
+	}
+
+	var err error
+	if objs.pods, _, err = cachedPods(ctx, client, namespace, q); err != nil {
+		return nil, nil, err
+	}
+	objs.deployments, _, err = cachedDeployments(ctx, client, namespace, q)
+	warn("Deployment", err)
+	objs.replicaSets, _, err = cachedReplicaSets(ctx, client, namespace, q)
+	warn("ReplicaSet", err)
+	objs.statefulSets, _, err = cachedStatefulSets(ctx, client, namespace, q)
+	warn("StatefulSet", err)
+	objs.daemonSets, _, err = cachedDaemonSets(ctx, client, namespace, q)
+	warn("DaemonSet", err)
+	objs.jobs, _, err = cachedJobs(ctx, client, namespace, q)
+	warn("Job", err)
+	objs.cronJobs, _, err = cachedCronJobs(ctx, client, namespace, q)
+	warn("CronJob", err)
+	objs.services, _, err = cachedServices(ctx, client, namespace, q)
+	warn("Service", err)
+	objs.hpas, _, err = cachedHPAs(ctx, client, namespace, q)
+	warn("HorizontalPodAutoscaler", err)
+
+	if nodes, _, err := cachedNodes(ctx, client, q); warn("Node", err) {
+		objs.nodes = make(map[string]*corev1.Node, len(nodes))
+		for _, n := range nodes {
+			objs.nodes[n.Name] = n
+		}
+	}
+	// ConfigMap/Secret 只需名称：按命名空间实时 LIST 元数据，不拉取内容、不启动集群级 informer
+	if names, err := metadataNames(ctx, client, schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespace); warn("ConfigMap", err) {
+		objs.configMaps = names
+	}
+	if names, err := metadataNames(ctx, client, schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, namespace); warn("Secret", err) {
+		objs.secrets = names
+	}
+
+	// 以下类型没有 informer，实时 LIST
+	if list, err := client.ClientSet.CoreV1().Endpoints(namespace).List(ctx, metav1.ListOptions{}); warn("Endpoints", err) {
+		objs.endpoints = make(map[string]*corev1.Endpoints, len(list.Items))
+		for i := range list.Items {
+			objs.endpoints[list.Items[i].Name] = &list.Items[i]
+		}
+	}
+	if list, err := client.ClientSet.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{}); warn("PersistentVolumeClaim", err) {
+		objs.pvcs = make(map[string]*corev1.PersistentVolumeClaim, len(list.Items))
+		for i := range list.Items {
+			objs.pvcs[list.Items[i].Name] = &list.Items[i]
+		}
+	}
+	if list, err := client.ClientSet.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{}); warn("Ingress", err) {
+		objs.ingresses = itemPointers(list.Items)
+	}
+	if list, err := client.ClientSet.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{}); warn("PodDisruptionBudget", err) {
+		objs.pdbs = itemPointers(list.Items)
+	}
+	return objs, warnings, nil
+}
+
+// metadataNames 列出命名空间内某资源的对象名称（只取元数据）
+func metadataNames(ctx context.Context, client *k8s.Client, gvr schema.GroupVersionResource, namespace string) (map[string]bool, error) {
+	list, err := client.MetadataClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(list.Items))
+	for _, item := range list.Items {
+		names[item.Name] = true
+	}
+	return names, nil
+}
+
+// graphNodeID 节点 ID：Kind/namespace/name，集群级资源为 Kind/name
+func graphNodeID(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + "/" + name
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+// graphBuilder 按插入顺序收集节点与去重后的边
+type graphBuilder struct {
+	nodes map[string]*model.GraphNode
+	order []string
+	edges []model.GraphEdge
+	seen  map[model.GraphEdge]bool
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{nodes: map[string]*model.GraphNode{}, seen: map[model.GraphEdge]bool{}}
+}
+
+// node 添加节点（已存在时保留原节点），返回节点 ID
+func (g *graphBuilder) node(kind, namespace, name, status, message string) string {
+	id := graphNodeID(kind, namespace, name)
+	if _, ok := g.nodes[id]; !ok {
+		g.nodes[id] = &model.GraphNode{ID: id, Kind: kind, Name: name, Namespace: namespace, Status: status, Message: message}
+		g.order = append(g.order, id)
+	}
+	return id
+}
+
+// edge 添加一条有向边（重复的边忽略）
+func (g *graphBuilder) edge(from, to, typ string) {
+	e := model.GraphEdge{From: from, To: to, Type: typ}
+	if !g.seen[e] {
+		g.seen[e] = true
+		g.edges = append(g.edges, e)
+	}
+}
+
+// graph 输出完整关系图
+func (g *graphBuilder) graph() *model.ResourceGraph {
+	return g.subgraph(nil)
+}
+
+// subgraph 输出 keep 中的节点及两端都在 keep 中的边；keep 为 nil 时输出全部
+func (g *graphBuilder) subgraph(keep map[string]bool) *model.ResourceGraph {
+	result := &model.ResourceGraph{Nodes: []model.GraphNode{}, Edges: []model.GraphEdge{}}
+	for _, id := range g.order {
+		if keep == nil || keep[id] {
+			result.Nodes = append(result.Nodes, *g.nodes[id])
+		}
+	}
+	for _, e := range g.edges {
+		if keep == nil || (keep[e.From] && keep[e.To]) {
+			result.Edges = append(result.Edges, e)
+		}
+	}
+	return result
+}
+
+// around 以 start 为中心取子图：沿边方向向下展开（如 Deployment → ReplicaSet → Pod → Node），
+// 向下到达的节点再沿反方向向上找引用它的资源（如 Pod ← Endpoints ← Service ← Ingress）；
+// 向上到达的节点只继续向上，共享的叶子节点不向上展开，因此不会牵出同一命名空间的其他工作负载。
+func (g *graphBuilder) around(start string) *model.ResourceGraph {
+	out := map[string][]string{}
+	in := map[string][]string{}
+	for _, e := range g.edges {
+		out[e.From] = append(out[e.From], e.To)
+		in[e.To] = append(in[e.To], e.From)
+	}
+
+	type visit struct {
+		id   string
+		down bool
+	}
+	reached := map[string]bool{start: true}
+	expandedDown := map[string]bool{}
+	expandedUp := map[string]bool{}
+	queue := []visit{{start, true}}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if expandedDown[v.id] || (!v.down && expandedUp[v.id]) {
+			continue
+		}
+		if v.down {
+			expandedDown[v.id] = true
+			for _, to := range out[v.id] {
+				reached[to] = true
+				queue = append(queue, visit{to, true})
+			}
+			if graphLeafKinds[g.nodes[v.id].Kind] {
+				continue
+			}
+		}
+		expandedUp[v.id] = true
+		for _, from := range in[v.id] {
+			reached[from] = true
+			queue = append(queue, visit{from, false})
+		}
+	}
+	return g.subgraph(reached)
+}
+
+// buildGraph 由命名空间内对象构建关系图
+func buildGraph(objs *graphObjects) *graphBuilder {
+	g := newGraphBuilder()
+	owners := map[types.UID]string{}
+	ownedBy := func(obj metav1.Object, id string) {
+		owners[obj.GetUID()] = id
+		if ref := metav1.GetControllerOf(obj); ref != nil {
+			if owner, ok := owners[ref.UID]; ok {
+				g.edge(owner, id, "owns")
+			}
+		}
+	}
+
+	// 有 Pod 的 ReplicaSet 即使已缩容到 0（如旧版本仍在终止的 Pod）也保留
+	podOwners := map[types.UID]bool{}
+	for _, pod := range objs.pods {
+		if ref := metav1.GetControllerOf(pod); ref != nil {
+			podOwners[ref.UID] = true
+		}
+	}
+
+	for _, d := range objs.deployments {
+		status, msg := replicaHealth(replicasOrOne(d.Spec.Replicas), d.Status.AvailableReplicas)
+		ownedBy(d, g.node("Deployment", d.Namespace, d.Name, status, msg))
+	}
+	for _, rs := range objs.replicaSets {
+		if replicasOrOne(rs.Spec.Replicas) == 0 && rs.Status.Replicas == 0 && !podOwners[rs.UID] {
+			continue
+		}
+		status, msg := replicaHealth(replicasOrOne(rs.Spec.Replicas), rs.Status.ReadyReplicas)
+		ownedBy(rs, g.node("ReplicaSet", rs.Namespace, rs.Name, status, msg))
+	}
+	for _, sts := range objs.statefulSets {
+		status, msg := replicaHealth(replicasOrOne(sts.Spec.Replicas), sts.Status.ReadyReplicas)
+		ownedBy(sts, g.node("StatefulSet", sts.Namespace, sts.Name, status, msg))
+	}
+	for _, ds := range objs.daemonSets {
+		status, msg := replicaHealth(ds.Status.DesiredNumberScheduled, ds.Status.NumberReady)
+		ownedBy(ds, g.node("DaemonSet", ds.Namespace, ds.Name, status, msg))
+	}
+	for _, cj := range objs.cronJobs {
+		msg := cj.Spec.Schedule
+		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+			msg += "（已暂停）"
+		}
+		ownedBy(cj, g.node("CronJob", cj.Namespace, cj.Name, graphHealthy, msg))
+	}
+	for _, job := range objs.jobs {
+		status, msg := jobHealth(job)
+		ownedBy(job, g.node("Job", job.Namespace, job.Name, status, msg))
+	}
+
+	for _, pod := range objs.pods {
+		status, msg := podHealth(pod)
+		id := g.node("Pod", pod.Namespace, pod.Name, status, msg)
+		ownedBy(pod, id)
+		if pod.Spec.NodeName != "" {
+			status, msg := nodeHealth(objs.nodes, pod.Spec.NodeName)
+			g.edge(id, g.node("Node", "", pod.Spec.NodeName, status, msg), "scheduled")
+		}
+		for _, ref := range podReferences(&pod.Spec) {
+			status, msg := referenceHealth(objs, ref)
+			g.edge(id, g.node(ref.kind, pod.Namespace, ref.name, status, msg), ref.edge)
+		}
+	}
+
+	for _, svc := range objs.services {
+		ep := objs.endpoints[svc.Name]
+		status, msg := serviceHealth(svc, ep, objs.endpoints != nil)
+		id := g.node("Service", svc.Namespace, svc.Name, status, msg)
+		if ep == nil {
+			continue
+		}
+		status, msg = endpointsHealth(ep)
+		epID := g.node("Endpoints", ep.Namespace, ep.Name, status, msg)
+		g.edge(id, epID, "endpoints")
+		for _, subset := range ep.Subsets {
+			for _, addr := range append(append([]corev1.EndpointAddress{}, subset.Addresses...), subset.NotReadyAddresses...) {
+				if ref := addr.TargetRef; ref != nil && ref.Kind == "Pod" {
+					if podID := graphNodeID("Pod", ep.Namespace, ref.Name); g.nodes[podID] != nil {
+						g.edge(epID, podID, "targets")
+					}
+				}
+			}
+		}
+	}
+
+	for _, ing := range objs.ingresses {
+		backends := ingressServices(ing)
+		var missing []string
+		for _, name := range backends {
+			if g.nodes[graphNodeID("Service", ing.Namespace, name)] == nil {
+				missing = append(missing, name)
+			}
+		}
+		status, msg := graphHealthy, ""
+		if len(missing) > 0 {
+			status, msg = graphError, "后端 Service 不存在: "+strings.Join(missing, ", ")
+		}
+		id := g.node("Ingress", ing.Namespace, ing.Name, status, msg)
+		for _, name := range backends {
+			g.edge(id, g.node("Service", ing.Namespace, name, graphError, "Service 不存在"), "routes")
+		}
+	}
+
+	for _, hpa := range objs.hpas {
+		status, msg := hpaHealth(hpa)
+		id := g.node("HorizontalPodAutoscaler", hpa.Namespace, hpa.Name, status, msg)
+		ref := hpa.Spec.ScaleTargetRef
+		g.edge(id, g.node(ref.Kind, hpa.Namespace, ref.Name, graphError, "扩缩容目标不存在"), "scales")
+	}
+
+	for _, pdb := range objs.pdbs {
+		status, msg := pdbHealth(pdb)
+		id := g.node("PodDisruptionBudget", pdb.Namespace, pdb.Name, status, msg)
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		for _, target := range pdbTargets(objs, selector) {
+			if g.nodes[target] != nil {
+				g.edge(id, target, "protects")
+			}
+		}
+	}
+	return g
+}
+
+// replicasOrOne spec.replicas 未设置时默认为 1
+func replicasOrOne(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// replicaHealth 全部就绪为健康，部分就绪为警告，期望副本大于 0 却无就绪副本为错误
+func replicaHealth(desired, ready int32) (string, string) {
+	msg := fmt.Sprintf("%d/%d 就绪", ready, desired)
+	switch {
+	case ready >= desired:
+		return graphHealthy, msg
+	case ready == 0:
+		return graphError, msg
+	}
+	return graphWarning, msg
+}
+
+// jobHealth 失败为错误，暂停为警告，运行中与已完成为健康
+func jobHealth(job *batchv1.Job) (string, string) {
+	switch status := jobStatus(job); status {
+	case "Failed":
+		msg := status
+		if cond := jobCondition(job, batchv1.JobFailed); cond != nil && cond.Reason != "" {
+			msg = cond.Reason
+		}
+		return graphError, msg
+	case "Suspended":
+		return graphWarning, status
+	default:
+		return graphHealthy, status
+	}
+}
+
+// podHealth 就绪或已完成为健康；崩溃、拉镜像失败等为错误；其余未就绪（调度中、启动中、终止中）为警告
+func podHealth(pod *corev1.Pod) (string, string) {
+	bp, blocking := blockingPod(pod)
+	if !blocking {
+		return graphHealthy, string(pod.Status.Phase)
+	}
+	msg := bp.Reason
+	if msg == "" {
+		msg = bp.Phase
+	}
+	if pod.Status.Phase == corev1.PodFailed || podErrorReasons[bp.Reason] {
+		return graphError, msg
+	}
+	return graphWarning, msg
+}
+
+// nodeHealth Ready 为健康，NotReady/Unknown 为错误；节点列表不可用时为 Unknown
+func nodeHealth(nodes map[string]*corev1.Node, name string) (string, string) {
+	if nodes == nil {
+		return graphUnknown, ""
+	}
+	node, ok := nodes[name]
+	if !ok {
+		return graphError, "节点不存在"
+	}
+	if status := getNodeStatus(node); status != "Ready" {
+		return graphError, status
+	}
+	return graphHealthy, "Ready"
+}
+
+// podReference Pod 引用的 ConfigMap/Secret/PVC
+type podReference struct {
+	kind     string
+	name     string
+	edge     string // mounts（卷挂载）/ references（环境变量、镜像拉取凭据）
+	optional bool
+}
+
+// podReferences 收集 Pod 通过卷、环境变量与 imagePullSecrets 引用的配置与存储
+func podReferences(spec *corev1.PodSpec) []podReference {
+	var refs []podReference
+	add := func(kind, name, edge string, optional *bool) {
+		if name != "" {
+			refs = append(refs, podReference{kind: kind, name: name, edge: edge, optional: optional != nil && *optional})
+		}
+	}
+
+	for _, v := range spec.Volumes {
+		switch {
+		case v.ConfigMap != nil:
+			add("ConfigMap", v.ConfigMap.Name, "mounts", v.ConfigMap.Optional)
+		case v.Secret != nil:
+			add("Secret", v.Secret.SecretName, "mounts", v.Secret.Optional)
+		case v.PersistentVolumeClaim != nil:
+			add("PersistentVolumeClaim", v.PersistentVolumeClaim.ClaimName, "mounts", nil)
+		case v.Projected != nil:
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					add("ConfigMap", src.ConfigMap.Name, "mounts", src.ConfigMap.Optional)
+				}
+				if src.Secret != nil {
+					add("Secret", src.Secret.Name, "mounts", src.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				add("ConfigMap", from.ConfigMapRef.Name, "references", from.ConfigMapRef.Optional)
+			}
+			if from.SecretRef != nil {
+				add("Secret", from.SecretRef.Name, "references", from.SecretRef.Optional)
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				add("ConfigMap", ref.Name, "references", ref.Optional)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				add("Secret", ref.Name, "references", ref.Optional)
+			}
+		}
+	}
+	for _, s := range spec.ImagePullSecrets {
+		add("Secret", s.Name, "references", nil)
+	}
+	return refs
+}
+
+// referenceHealth 被引用对象存在为健康，不存在时必需引用为错误、可选引用为警告；PVC 另看绑定状态
+func referenceHealth(objs *graphObjects, ref podReference) (string, string) {
+	missing := func() (string, string) {
+		if ref.optional {
+			return graphWarning, "不存在（可选）"
+		}
+		return graphError, "不存在"
+	}
+	switch ref.kind {
+	case "ConfigMap", "Secret":
+		known := objs.configMaps
+		if ref.kind == "Secret" {
+			known = objs.secrets
+		}
+		if known == nil {
+			return graphUnknown, ""
+		}
+		if !known[ref.name] {
+			return missing()
+		}
+		return graphHealthy, ""
+	case "PersistentVolumeClaim":
+		if objs.pvcs == nil {
+			return graphUnknown, ""
+		}
+		pvc, ok := objs.pvcs[ref.name]
+		if !ok {
+			return missing()
+		}
+		switch pvc.Status.Phase {
+		case corev1.ClaimBound:
+			return graphHealthy, string(pvc.Status.Phase)
+		case corev1.ClaimLost:
+			return graphError, string(pvc.Status.Phase)
+		}
+		return graphWarning, string(pvc.Status.Phase)
+	}
+	return graphUnknown, ""
+}
+
+// serviceHealth 按 Endpoints 判断：有就绪地址为健康，只有未就绪地址为警告，有选择器却无地址为错误
+func serviceHealth(svc *corev1.Service, ep *corev1.Endpoints, endpointsKnown bool) (string, string) {
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return graphHealthy, "ExternalName: " + svc.Spec.ExternalName
+	}
+	if !endpointsKnown {
+		return graphUnknown, ""
+	}
+	if ep == nil {
+		if len(svc.Spec.Selector) == 0 {
+			return graphUnknown, "无选择器且无 Endpoints"
+		}
+		return graphError, "无可用端点"
+	}
+	return endpointsHealth(ep)
+}
+
+// endpointsHealth 统计就绪与未就绪地址数
+func endpointsHealth(ep *corev1.Endpoints) (string, string) {
+	var ready, notReady int
+	for _, subset := range ep.Subsets {
+		ready += len(subset.Addresses)
+		notReady += len(subset.NotReadyAddresses)
+	}
+	msg := fmt.Sprintf("%d 就绪，%d 未就绪", ready, notReady)
+	switch {
+	case ready > 0 && notReady == 0:
+		return graphHealthy, msg
+	case ready+notReady > 0:
+		return graphWarning, msg
+	}
+	return graphError, "无可用端点"
+}
+
+// ingressServices 返回 Ingress 规则与默认后端引用的 Service（去重，保持出现顺序）
+func ingressServices(ing *networkingv1.Ingress) []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(b *networkingv1.IngressBackend) {
+		if b != nil && b.Service != nil && !seen[b.Service.Name] {
+			seen[b.Service.Name] = true
+			names = append(names, b.Service.Name)
+		}
+	}
+	add(ing.Spec.DefaultBackend)
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			add(&rule.HTTP.Paths[i].Backend)
+		}
+	}
+	return names
+}
+
+// hpaHealth AbleToScale 为 False 时为错误，ScalingActive 为 False（如取不到指标）时为警告
+func hpaHealth(hpa *autoscalingv2.HorizontalPodAutoscaler) (string, string) {
+	msg := fmt.Sprintf("%d 副本（%d-%d）", hpa.Status.CurrentReplicas, replicasOrOne(hpa.Spec.MinReplicas), hpa.Spec.MaxReplicas)
+	for _, t := range []autoscalingv2.HorizontalPodAutoscalerConditionType{autoscalingv2.AbleToScale, autoscalingv2.ScalingActive} {
+		for _, cond := range hpa.Status.Conditions {
+			if cond.Type != t || cond.Status != corev1.ConditionFalse {
+				continue
+			}
+			if t == autoscalingv2.AbleToScale {
+				return graphError, cond.Reason + ": " + cond.Message
+			}
+			return graphWarning, cond.Reason + ": " + cond.Message
+		}
+	}
+	return graphHealthy, msg
+}
+
+// pdbHealth 健康 Pod 少于期望为错误，当前不允许驱逐为警告
+func pdbHealth(pdb *policyv1.PodDisruptionBudget) (string, string) {
+	msg := fmt.Sprintf("允许驱逐 %d 个，健康 %d/%d", pdb.Status.DisruptionsAllowed, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy)
+	switch {
+	case pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy:
+		return graphError, msg
+	case pdb.Status.DisruptionsAllowed == 0:
+		return graphWarning, msg
+	}
+	return graphHealthy, msg
+}
+
+// pdbTargets PDB 保护的对象：Pod 模板标签匹配选择器的顶层工作负载，以及匹配的无控制器 Pod
+func pdbTargets(objs *graphObjects, selector labels.Selector) []string {
+	var targets []string
+	match := func(kind string, obj metav1.Object, template *corev1.PodTemplateSpec) {
+		if metav1.GetControllerOf(obj) == nil && selector.Matches(labels.Set(template.Labels)) {
+			targets = append(targets, graphNodeID(kind, obj.GetNamespace(), obj.GetName()))
+		}
+	}
+	for _, d := range objs.deployments {
+		match("Deployment", d, &d.Spec.Template)
+	}
+	for _, rs := range objs.replicaSets {
+		match("ReplicaSet", rs, &rs.Spec.Template)
+	}
+	for _, sts := range objs.statefulSets {
+		match("StatefulSet", sts, &sts.Spec.Template)
+	}
+	for _, ds := range objs.daemonSets {
+		match("DaemonSet", ds, &ds.Spec.Template)
+	}
+	for _, pod := range objs.pods {
+		if metav1.GetControllerOf(pod) == nil && selector.Matches(labels.Set(pod.Labels)) {
+			targets = append(targets, graphNodeID("Pod", pod.Namespace, pod.Name))
+		}
+	}
+	return targets
+}
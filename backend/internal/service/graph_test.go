@@ -0,0 +1,140 @@
+package service
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// graphFixture 两个 Deployment（web、api）共享节点与 ConfigMap，Service/Ingress 只指向 web
+func graphFixture() *graphObjects {
+	controller := true
+	ownedBy := func(uid string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{UID: types.UID(uid), Controller: &controller}}
+	}
+	meta := func(name, uid, owner string) metav1.ObjectMeta {
+		m := metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid)}
+		if owner != "" {
+			m.OwnerReferences = ownedBy(owner)
+		}
+		return m
+	}
+	zero, one := int32(0), int32(1)
+	ready := corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}
+	podSpec := corev1.PodSpec{
+		NodeName: "node-1",
+		Volumes:  []corev1.Volume{{Name: "cfg", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "shared"}}}}},
+	}
+	crashing := corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}}}
+
+	return &graphObjects{
+		deployments: []*appsv1.Deployment{
+			{ObjectMeta: meta("web", "d-web", ""), Spec: appsv1.DeploymentSpec{Replicas: &one}, Status: appsv1.DeploymentStatus{AvailableReplicas: 1}},
+			{ObjectMeta: meta("api", "d-api", ""), Spec: appsv1.DeploymentSpec{Replicas: &one}},
+		},
+		replicaSets: []*appsv1.ReplicaSet{
+			{ObjectMeta: meta("web-1", "rs-web", "d-web"), Spec: appsv1.ReplicaSetSpec{Replicas: &one}, Status: appsv1.ReplicaSetStatus{ReadyReplicas: 1}},
+			{ObjectMeta: meta("web-0", "rs-web-old", "d-web"), Spec: appsv1.ReplicaSetSpec{Replicas: &zero}},
+			{ObjectMeta: meta("api-1", "rs-api", "d-api"), Spec: appsv1.ReplicaSetSpec{Replicas: &one}},
+		},
+		pods: []*corev1.Pod{
+			{ObjectMeta: meta("web-1-a", "p-web", "rs-web"), Spec: podSpec, Status: ready},
+			{ObjectMeta: meta("api-1-a", "p-api", "rs-api"), Spec: podSpec, Status: crashing},
+		},
+		services: []*corev1.Service{{ObjectMeta: meta("web", "s-web", ""), Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "web"}}}},
+		endpoints: map[string]*corev1.Endpoints{
+			"web": {
+				ObjectMeta: meta("web", "e-web", ""),
+				Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-1-a"}}}}},
+			},
+		},
+		ingresses: []*networkingv1.Ingress{{
+			ObjectMeta: meta("web", "i-web", ""),
+			Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{
+					{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web"}}},
+					{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "gone"}}},
+				},
+			}}}}},
+		}},
+		configMaps: map[string]bool{"shared": true},
+		secrets:    map[string]bool{},
+		nodes:      map[string]*corev1.Node{"node-1": {ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}}}},
+	}
+}
+
+// TestBuildGraph 构建资源关系图：省略已缩容且无 Pod 的 ReplicaSet，节点状态与各类边正确
+func TestBuildGraph(t *testing.T) {
+	g := buildGraph(graphFixture())
+
+	if _, ok := g.nodes["ReplicaSet/default/web-0"]; ok {
+		t.Error("scaled-down ReplicaSet without pods should be omitted")
+	}
+	status := map[string]string{
+		"Deployment/default/web": graphHealthy,
+		"Deployment/default/api": graphError,
+		"Pod/default/api-1-a":    graphError,
+		"Service/default/gone":   graphError,
+		"Ingress/default/web":    graphError,
+		"Node/node-1":            graphHealthy,
+	}
+	for id, want := range status {
+		if n := g.nodes[id]; n == nil || n.Status != want {
+			t.Errorf("%s: got %+v, want status %s", id, n, want)
+		}
+	}
+
+	edges := map[string]bool{}
+	for _, e := range g.edges {
+		edges[e.From+" -"+e.Type+"-> "+e.To] = true
+	}
+	for _, want := range []string{
+		"Deployment/default/web -owns-> ReplicaSet/default/web-1",
+		"ReplicaSet/default/web-1 -owns-> Pod/default/web-1-a",
+		"Pod/default/web-1-a -scheduled-> Node/node-1",
+		"Pod/default/web-1-a -mounts-> ConfigMap/default/shared",
+		"Service/default/web -endpoints-> Endpoints/default/web",
+		"Endpoints/default/web -targets-> Pod/default/web-1-a",
+		"Ingress/default/web -routes-> Service/default/web",
+	} {
+		if !edges[want] {
+			t.Errorf("missing edge %s", want)
+		}
+	}
+}
+
+// TestGraphAround 以对象为中心的子图不经共享的节点或 ConfigMap 扩散到无关对象
+func TestGraphAround(t *testing.T) {
+	g := buildGraph(graphFixture())
+	ids := func(id string) map[string]bool {
+		set := map[string]bool{}
+		for _, n := range g.around(id).Nodes {
+			set[n.ID] = true
+		}
+		return set
+	}
+
+	web := ids("Deployment/default/web")
+	for _, want := range []string{"Pod/default/web-1-a", "Node/node-1", "ConfigMap/default/shared", "Service/default/web", "Ingress/default/web"} {
+		if !web[want] {
+			t.Errorf("web graph missing %s", want)
+		}
+	}
+	// 共享的节点与 ConfigMap 不应把 api 牵进来；Ingress 的另一个后端也不属于 web
+	for _, unwanted := range []string{"Deployment/default/api", "Pod/default/api-1-a", "Service/default/gone"} {
+		if web[unwanted] {
+			t.Errorf("web graph should not contain %s", unwanted)
+		}
+	}
+
+	svc := ids("Service/default/web")
+	for _, want := range []string{"Endpoints/default/web", "Pod/default/web-1-a", "ReplicaSet/default/web-1", "Deployment/default/web", "Ingress/default/web"} {
+		if !svc[want] {
+			t.Errorf("service graph missing %s", want)
+		}
+	}
+}
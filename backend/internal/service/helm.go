@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/config"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"sigs.k8s.io/yaml"
+)
+
+// helmDriver release 存储方式，与 helm CLI 默认一致（Secret），两边可互相管理同一批 release
+const helmDriver = "secret"
+
+// helmTimeout 钩子与资源删除等待上限，需小于 Helm 写操作的请求期限（见 router 中的 longRequestTimeout）
+const helmTimeout = 4 * time.Minute
+
+// MaxChartSize 上传 chart 包的大小上限
+const MaxChartSize = 20 << 20
+
+// ErrChartDirDisabled 未配置 HELM_CHART_DIR 时无法使用本地 chart 仓库
+var ErrChartDirDisabled = errors.New("未配置 HELM_CHART_DIR，无法使用本地 chart 仓库")
+
+// HelmService Helm release 管理，基于 Helm SDK 与集群已有的连接配置（release 以 Secret 存储，与 helm CLI 互通）
+type HelmService struct {
+	k8sClient *k8s.Client
+}
+
+// NewHelmService 创建 Helm 服务
+func NewHelmService(k8sClient *k8s.Client) *HelmService {
+	return &HelmService{k8sClient: k8sClient}
+}
+
+// actionConfig 创建作用于 namespace 的 Helm 动作配置（namespace 为空表示全部命名空间，仅用于列表）
+func (s *HelmService) actionConfig(namespace string) (*action.Configuration, error) {
+	cfg := new(action.Configuration)
+	// Helm 的调试日志过于细碎，不输出
+	discard := func(string, ...interface{}) {}
+	if err := cfg.Init(s.k8sClient.RESTClientGetter(namespace), namespace, helmDriver, discard); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ListReleases 列出 release 的最新版本（namespace 为空表示全部命名空间），包含失败、卸载中等所有状态
+func (s *HelmService) ListReleases(namespace, search string) ([]model.HelmRelease, error) {
+	cfg, err := s.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	list := action.NewList(cfg)
+	list.AllNamespaces = namespace == ""
+	list.All = true
+	list.SetStateMask()
+	releases, err := list.Run()
+	if err != nil {
+		return nil, err
+	}
+	keyword := strings.ToLower(search)
+	out := make([]model.HelmRelease, 0, len(releases))
+	for _, rel := range releases {
+		if keyword != "" && !strings.Contains(strings.ToLower(rel.Name), keyword) {
+			continue
+		}
+		out = append(out, convertRelease(rel))
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// GetRelease 获取指定版本（revision 为 0 表示最新）的清单与 NOTES
+func (s *HelmService) GetRelease(namespace, name string, revision int) (*model.HelmReleaseDetail, error) {
+	rel, err := s.release(namespace, name, revision)
+	if err != nil {
+		return nil, err
+	}
+	return &model.HelmReleaseDetail{HelmRelease: convertRelease(rel), Notes: rel.Info.Notes, Manifest: rel.Manifest}, nil
+}
+
+// GetHistory 获取 release 的全部版本，按版本号倒序
+func (s *HelmService) GetHistory(namespace, name string) ([]model.HelmRelease, error) {
+	cfg, err := s.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	releases, err := action.NewHistory(cfg).Run(name)
+	if err != nil {
+		return nil, helmError(name, err)
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Version > releases[j].Version })
+	out := make([]model.HelmRelease, 0, len(releases))
+	for _, rel := range releases {
+		out = append(out, convertRelease(rel))
+	}
+	return out, nil
+}
+
+// GetValues 获取指定版本的 values：默认为用户提供的部分，all 时为与 chart 默认值合并后的完整 values
+func (s *HelmService) GetValues(namespace, name string, revision int, all bool) (map[string]interface{}, error) {
+	cfg, err := s.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	get := action.NewGetValues(cfg)
+	get.Version = revision
+	get.AllValues = all
+	values, err := get.Run(name)
+	if err != nil {
+		return nil, helmError(name, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, nil
+}
+
+// DiffRevisions 比较两个版本的渲染清单与用户 values。to 为 0 表示最新版本，from 为 0 表示 to 的上一版本
+func (s *HelmService) DiffRevisions(namespace, name string, from, to int) (*model.HelmRevisionDiff, error) {
+	toRel, err := s.release(namespace, name, to)
+	if err != nil {
+		return nil, err
+	}
+	if from == 0 {
+		from = toRel.Version - 1
+	}
+	if from < 1 {
+		return nil, fmt.Errorf("%s 只有一个版本，无可比较的版本", name)
+	}
+	fromRel, err := s.release(namespace, name, from)
+	if err != nil {
+		return nil, err
+	}
+	fromName, toName := fmt.Sprintf("revision %d", fromRel.Version), fmt.Sprintf("revision %d", toRel.Version)
+
+	manifest, err := textDiff(fromRel.Manifest, toRel.Manifest, fromName, toName)
+	if err != nil {
+		return nil, err
+	}
+	fromValues, err := yaml.Marshal(fromRel.Config)
+	if err != nil {
+		return nil, err
+	}
+	toValues, err := yaml.Marshal(toRel.Config)
+	if err != nil {
+		return nil, err
+	}
+	values, err := textDiff(string(fromValues), string(toValues), fromName, toName)
+	if err != nil {
+		return nil, err
+	}
+	return &model.HelmRevisionDiff{From: fromRel.Version, To: toRel.Version, Manifest: manifest, Values: values}, nil
+}
+
+// Rollback 回滚到指定版本（revision 为 0 表示上一版本），回滚本身产生一个新版本
+func (s *HelmService) Rollback(namespace, name string, revision int, dryRun bool) (*model.HelmRelease, error) {
+	cfg, err := s.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	rollback := action.NewRollback(cfg)
+	rollback.Version = revision
+	rollback.Timeout = helmTimeout
+	rollback.DryRun = dryRun
+	if err := rollback.Run(name); err != nil {
+		return nil, helmError(name, err)
+	}
+	if dryRun {
+		return nil, nil
+	}
+	rel, err := s.release(namespace, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := convertRelease(rel)
+	return &result, nil
+}
+
+// Uninstall 卸载 release 及其创建的资源；keepHistory 时保留版本记录（状态为 uninstalled，可回滚恢复）
+func (s *HelmService) Uninstall(namespace, name string, keepHistory, dryRun bool) (string, error) {
+	cfg, err := s.actionConfig(namespace)
+	if err != nil {
+		return "", err
+	}
+	uninstall := action.NewUninstall(cfg)
+	uninstall.KeepHistory = keepHistory
+	uninstall.DryRun = dryRun
+	uninstall.Timeout = helmTimeout
+	resp, err := uninstall.Run(name)
+	if err != nil {
+		return "", helmError(name, err)
+	}
+	return resp.Info, nil
+}
+
+// InstallOrUpgrade 语义同 helm upgrade --install：release 不存在时安装，否则升级。
+// DryRun 时在服务端渲染并校验（可使用 lookup），返回将要生成的清单但不做任何修改。
+func (s *HelmService) InstallOrUpgrade(ctx context.Context, namespace, name string, chrt *chart.Chart, opts model.HelmInstallOptions) (*model.HelmReleaseDetail, error) {
+	if opts.Version != "" && chrt.Metadata.Version != opts.Version {
+		return nil, fmt.Errorf("chart 版本为 %s，与指定的 %s 不符", chrt.Metadata.Version, opts.Version)
+	}
+	values, err := chartutil.ReadValues([]byte(opts.Values))
+	if err != nil {
+		return nil, fmt.Errorf("values 不是合法的 YAML: %w", err)
+	}
+	cfg, err := s.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	history := action.NewHistory(cfg)
+	history.Max = 1
+	_, err = history.Run(name)
+	var rel *release.Release
+	switch {
+	case errors.Is(err, driver.ErrReleaseNotFound):
+		install := action.NewInstall(cfg)
+		install.ReleaseName = name
+		install.Namespace = namespace
+		install.CreateNamespace = opts.CreateNamespace
+		install.Description = opts.Description
+		install.Timeout = helmTimeout
+		if opts.DryRun {
+			install.DryRun, install.DryRunOption = true, "server"
+		}
+		rel, err = install.RunWithContext(ctx, chrt, values)
+	case err != nil:
+		return nil, err
+	default:
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = namespace
+		upgrade.ReuseValues = opts.ReuseValues
+		upgrade.Description = opts.Description
+		upgrade.Timeout = helmTimeout
+		if opts.DryRun {
+			upgrade.DryRun, upgrade.DryRunOption = true, "server"
+		}
+		rel, err = upgrade.RunWithContext(ctx, name, chrt, values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model.HelmReleaseDetail{HelmRelease: convertRelease(rel), Notes: rel.Info.Notes, Manifest: rel.Manifest}, nil
+}
+
+// release 获取指定版本（0 为最新）
+func (s *HelmService) release(namespace, name string, revision int) (*release.Release, error) {
+	cfg, err := s.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	get := action.NewGet(cfg)
+	get.Version = revision
+	rel, err := get.Run(name)
+	if err != nil {
+		return nil, helmError(name, err)
+	}
+	return rel, nil
+}
+
+// helmError 将 release 不存在的存储错误转为可读信息
+func helmError(name string, err error) error {
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return fmt.Errorf("release %s 不存在", name)
+	}
+	return err
+}
+
+// convertRelease 转换 release 概要
+func convertRelease(rel *release.Release) model.HelmRelease {
+	r := model.HelmRelease{Name: rel.Name, Namespace: rel.Namespace, Revision: rel.Version}
+	if rel.Info != nil {
+		r.Status = rel.Info.Status.String()
+		r.Description = rel.Info.Description
+		if !rel.Info.LastDeployed.IsZero() {
+			r.Updated = rel.Info.LastDeployed.Format("2006-01-02 15:04:05")
+		}
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		r.Chart = rel.Chart.Metadata.Name
+		r.ChartVersion = rel.Chart.Metadata.Version
+		r.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	return r
+}
+
+// LoadChartArchive 读取上传的 chart 包（.tgz）
+func LoadChartArchive(r io.Reader) (*chart.Chart, error) {
+	chrt, err := loader.LoadArchive(r)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 chart 包: %w", err)
+	}
+	return chrt, nil
+}
+
+// ListLocalCharts 列出本地 chart 仓库目录中的 chart：有 index.yaml 时按索引列出全部版本，
+// 否则扫描顶层的 chart 目录与 .tgz 包
+func ListLocalCharts() ([]model.HelmChart, error) {
+	dir := config.App.HelmChartDir
+	if dir == "" {
+		return nil, ErrChartDirDisabled
+	}
+	idx, err := loadChartIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+	if idx != nil {
+		charts := []model.HelmChart{}
+		for _, versions := range idx.Entries {
+			for _, v := range versions {
+				path := ""
+				if len(v.URLs) > 0 {
+					path = v.URLs[0]
+				}
+				charts = append(charts, chartInfo(v.Metadata, path))
+			}
+		}
+		sortCharts(charts)
+		return charts, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	charts := []model.HelmChart{}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		var meta *chart.Metadata
+		switch {
+		case entry.IsDir():
+			meta, err = chartutil.LoadChartfile(filepath.Join(path, chartutil.ChartfileName))
+		case strings.HasSuffix(entry.Name(), ".tgz"):
+			var chrt *chart.Chart
+			if chrt, err = loader.LoadFile(path); err == nil {
+				meta = chrt.Metadata
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			continue // 非 chart 目录或损坏的包
+		}
+		charts = append(charts, chartInfo(meta, entry.Name()))
+	}
+	sortCharts(charts)
+	return charts, nil
+}
+
+// LoadLocalChart 从本地 chart 仓库目录加载 chart：ref 为目录内的相对路径（chart 目录或 .tgz），
+// 或 index.yaml 中的 chart 名（version 为空时取最新版本）
+func LoadLocalChart(ref, version string) (*chart.Chart, error) {
+	dir := config.App.HelmChartDir
+	if dir == "" {
+		return nil, ErrChartDirDisabled
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("缺少 chart 参数或 chart_file")
+	}
+	if !strings.ContainsAny(ref, `/\`) && !strings.HasSuffix(ref, ".tgz") {
+		idx, err := loadChartIndex(dir)
+		if err != nil {
+			return nil, err
+		}
+		if idx != nil {
+			cv, err := idx.Get(ref, version)
+			if err != nil {
+				return nil, fmt.Errorf("chart 仓库中没有 %s %s", ref, version)
+			}
+			if len(cv.URLs) == 0 {
+				return nil, fmt.Errorf("%s %s 没有下载地址", ref, cv.Version)
+			}
+			ref = cv.URLs[0]
+		}
+	}
+	path, err := pathInDir(dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(path)
+}
+
+// pathInDir 解析配置目录内的相对路径，拒绝绝对路径、远程地址与跳出目录的相对路径。
+// 目录与目标都先解析符号链接再比较，目录内指向外部的链接同样被拒绝；目标必须存在
+func pathInDir(dir, ref string) (string, error) {
+	if strings.Contains(ref, "://") || filepath.IsAbs(ref) {
+		return "", fmt.Errorf("必须是 %s 内的相对路径: %s", dir, ref)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(ref)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%s 内不存在 %s", dir, ref)
+	}
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("必须是 %s 内的相对路径: %s", dir, ref)
+	}
+	return path, nil
+}
+
+// loadChartIndex 读取目录下的 index.yaml，不存在时返回 nil
+func loadChartIndex(dir string) (*repo.IndexFile, error) {
+	path := filepath.Join(dir, "index.yaml")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	idx, err := repo.LoadIndexFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 index.yaml 失败: %w", err)
+	}
+	idx.SortEntries()
+	return idx, nil
+}
+
+// chartInfo 转换 chart 元数据
+func chartInfo(meta *chart.Metadata, path string) model.HelmChart {
+	return model.HelmChart{
+		Name:        meta.Name,
+		Version:     meta.Version,
+		AppVersion:  meta.AppVersion,
+		Description: meta.Description,
+		Path:        path,
+	}
+}
+
+// sortCharts 按名称升序，同名 chart 保持原顺序（index.yaml 中为版本从新到旧）
+func sortCharts(charts []model.HelmChart) {
+	sort.SliceStable(charts, func(i, j int) bool { return charts[i].Name < charts[j].Name })
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kube-admin/kube-admin/backend/config"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// TestPathInDir 路径必须存在且解析符号链接后仍在目录内，拒绝 ..、绝对路径与 URL
+func TestPathInDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "charts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "charts", "web-1.0.0.tgz"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// 目录内指向外部的符号链接
+	if err := os.Symlink(t.TempDir(), filepath.Join(dir, "outside")); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := pathInDir(dir, "charts/web-1.0.0.tgz"); err != nil || p != filepath.Join(dir, "charts", "web-1.0.0.tgz") {
+		t.Errorf("pathInDir = %s, %v", p, err)
+	}
+	for _, bad := range []string{"../etc", "a/../../b", "/etc/passwd", "https://example.com/web.tgz", "outside", "charts/missing.tgz"} {
+		if _, err := pathInDir(dir, bad); err == nil {
+			t.Errorf("%s should be rejected", bad)
+		}
+	}
+}
+
+// TestLocalCharts 扫描并加载本地目录中的 chart 目录与 .tgz 包
+func TestLocalCharts(t *testing.T) {
+	old := config.App
+	t.Cleanup(func() { config.App = old })
+	dir := t.TempDir()
+	config.App = &config.Config{HelmChartDir: dir}
+
+	// 目录形式的 chart 与打包后的 .tgz
+	chartDir, err := chartutil.Create("web", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chrt, err := loader.LoadDir(chartDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chrt.Metadata.Name = "api"
+	archive, err := chartutil.Save(chrt, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	charts, err := ListLocalCharts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(charts) != 2 || charts[0].Name != "api" || charts[0].Path != filepath.Base(archive) || charts[1].Path != "web" {
+		t.Errorf("scanned charts = %+v", charts)
+	}
+	if c, err := LoadLocalChart("web", ""); err != nil || c.Name() != "web" {
+		t.Errorf("load dir chart: %v", err)
+	}
+
+	// 有 index.yaml 时按 chart 名与版本查找
+	idx := repo.NewIndexFile()
+	if err := idx.MustAdd(chrt.Metadata, filepath.Base(archive), "", "sha256:0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.WriteFile(filepath.Join(dir, "index.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadLocalChart("api", chrt.Metadata.Version); err != nil || c.Name() != "api" {
+		t.Errorf("load indexed chart: %v", err)
+	}
+	if _, err := LoadLocalChart("api", "9.9.9"); err == nil {
+		t.Error("expected error for unknown version")
+	}
+
+	config.App = &config.Config{}
+	if _, err := ListLocalCharts(); err != ErrChartDirDisabled {
+		t.Errorf("expected ErrChartDirDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	"github.com/kube-admin/kube-admin/backend/pkg/logger"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// hpaTargetKinds 表单创建 HPA 时支持的目标
+var hpaTargetKinds = map[string]bool{"Deployment": true, "StatefulSet": true}
+
+// HPAConflictError 目标副本数由 HPA 管理时拒绝手动扩缩容
+type HPAConflictError struct {
+	HPA         string
+	MinReplicas int32
+	MaxReplicas int32
+}
+
+func (e *HPAConflictError) Error() string {
+	return fmt.Sprintf("副本数由 HPA %s 管理（%d-%d），手动扩缩容会被立即还原；确需操作请加 force=true", e.HPA, e.MinReplicas, e.MaxReplicas)
+}
+
+// HPAService HPA服务
+type HPAService struct {
+	k8sClient *k8s.Client
+}
+
+// NewHPAService 创建HPA服务
+func NewHPAService(k8sClient *k8s.Client) *HPAService {
+	return &HPAService{k8sClient: k8sClient}
+}
+
+// ListHPAs 获取HPA列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *HPAService) ListHPAs(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedHPAs(ctx, s.k8sClient, namespace, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageList(list, listMeta, q, convertHPA), nil
+}
+
+// GetHPA 获取HPA详情，含扩缩容事件历史
+func (s *HPAService) GetHPA(ctx context.Context, namespace, name string) (*model.HPADetail, error) {
+	hpa, err := s.k8sClient.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.HPADetail{HPAInfo: convertHPA(hpa), Events: []model.EventInfo{}}
+	events, err := s.k8sClient.ClientSet.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=HorizontalPodAutoscaler,involvedObject.name=%s", name),
+	})
+	if err != nil {
+		return detail, nil // 事件只是辅助信息，读取失败不影响详情
+	}
+	items := itemPointers(events.Items)
+	sort.Slice(items, func(i, j int) bool { return eventTime(items[i]).After(eventTime(items[j])) })
+	for _, e := range items {
+		detail.Events = append(detail.Events, convertEvent(e))
+	}
+	return detail, nil
+}
+
+// CreateHPA 按表单为 Deployment/StatefulSet 创建 HPA
+func (s *HPAService) CreateHPA(ctx context.Context, namespace string, form model.HPAForm) (*model.HPAInfo, error) {
+	if !hpaTargetKinds[form.TargetKind] {
+		return nil, fmt.Errorf("target_kind 仅支持 Deployment、StatefulSet")
+	}
+	if form.TargetName == "" {
+		return nil, fmt.Errorf("target_name 不能为空")
+	}
+	if form.MaxReplicas == nil {
+		return nil, fmt.Errorf("max_replicas 不能为空")
+	}
+	name := form.Name
+	if name == "" {
+		name = form.TargetName
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: form.TargetKind, Name: form.TargetName},
+		},
+	}
+	if err := applyHPAForm(&hpa.Spec, form); err != nil {
+		return nil, err
+	}
+	created, err := s.k8sClient.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace).Create(ctx, hpa, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info := convertHPA(created)
+	return &info, nil
+}
+
+// UpdateHPA 修改最小/最大副本数与 CPU/内存使用率目标，其他指标保持不变
+func (s *HPAService) UpdateHPA(ctx context.Context, namespace, name string, form model.HPAForm) (*model.HPAInfo, error) {
+	hpa, err := s.k8sClient.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := applyHPAForm(&hpa.Spec, form); err != nil {
+		return nil, err
+	}
+
+	// 只替换 spec 中表单涉及的字段（add 在字段缺省时同样生效），并以 resourceVersion 防并发覆盖
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": hpa.ResourceVersion},
+		{"op": "add", "path": "/spec/minReplicas", "value": hpa.Spec.MinReplicas},
+		{"op": "add", "path": "/spec/maxReplicas", "value": hpa.Spec.MaxReplicas},
+		{"op": "add", "path": "/spec/metrics", "value": hpa.Spec.Metrics},
+	})
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.k8sClient.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace).Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info := convertHPA(updated)
+	return &info, nil
+}
+
+// DeleteHPA 删除HPA（目标副本数保持当前值）
+func (s *HPAService) DeleteHPA(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
+}
+
+// applyHPAForm 将表单应用到 spec 并校验：1 <= min <= max，至少保留一个指标，使用率目标须为正数
+func applyHPAForm(spec *autoscalingv2.HorizontalPodAutoscalerSpec, form model.HPAForm) error {
+	if form.MinReplicas != nil {
+		spec.MinReplicas = form.MinReplicas
+	}
+	if form.MaxReplicas != nil {
+		spec.MaxReplicas = *form.MaxReplicas
+	}
+	minReplicas := int32(1)
+	if spec.MinReplicas != nil {
+		minReplicas = *spec.MinReplicas
+	}
+	if minReplicas < 1 {
+		return fmt.Errorf("min_replicas 不能小于 1")
+	}
+	if spec.MaxReplicas < minReplicas {
+		return fmt.Errorf("max_replicas 不能小于 min_replicas")
+	}
+
+	for _, t := range []struct {
+		resource corev1.ResourceName
+		target   *int32
+	}{{corev1.ResourceCPU, form.CPUUtilization}, {corev1.ResourceMemory, form.MemoryUtilization}} {
+		if t.target == nil {
+			continue
+		}
+		if *t.target < 0 {
+			return fmt.Errorf("%s 使用率目标不能为负数", t.resource)
+		}
+		spec.Metrics = setResourceUtilization(spec.Metrics, t.resource, *t.target)
+	}
+	if len(spec.Metrics) == 0 {
+		return fmt.Errorf("至少需要一个指标（cpu_utilization 或 memory_utilization）")
+	}
+	return nil
+}
+
+// setResourceUtilization 设置资源使用率指标，utilization 为 0 时移除；保持原有指标顺序
+func setResourceUtilization(metrics []autoscalingv2.MetricSpec, resource corev1.ResourceName, utilization int32) []autoscalingv2.MetricSpec {
+	out := make([]autoscalingv2.MetricSpec, 0, len(metrics)+1)
+	found := false
+	for _, m := range metrics {
+		if m.Type == autoscalingv2.ResourceMetricSourceType && m.Resource != nil && m.Resource.Name == resource {
+			found = true
+			if utilization == 0 {
+				continue
+			}
+			m.Resource = &autoscalingv2.ResourceMetricSource{
+				Name:   resource,
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &utilization},
+			}
+		}
+		out = append(out, m)
+	}
+	if !found && utilization > 0 {
+		out = append(out, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   resource,
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &utilization},
+			},
+		})
+	}
+	return out
+}
+
+// convertHPA 转换HPA对象，指标按 spec 顺序与 status.currentMetrics 配对
+func convertHPA(hpa *autoscalingv2.HorizontalPodAutoscaler) model.HPAInfo {
+	info := model.HPAInfo{
+		K8sResource: model.K8sResource{
+			Name:              hpa.Name,
+			Namespace:         hpa.Namespace,
+			Labels:            hpa.Labels,
+			Annotations:       hpa.Annotations,
+			CreationTimestamp: hpa.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			ResourceVersion:   hpa.ResourceVersion,
+		},
+		TargetKind:      hpa.Spec.ScaleTargetRef.Kind,
+		TargetName:      hpa.Spec.ScaleTargetRef.Name,
+		MinReplicas:     1,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Metrics:         []model.HPAMetric{},
+		Conditions:      []model.PodCondition{},
+	}
+	if hpa.Spec.MinReplicas != nil {
+		info.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	if hpa.Status.LastScaleTime != nil {
+		info.LastScaleTime = hpa.Status.LastScaleTime.Format("2006-01-02 15:04:05")
+	}
+	for _, m := range hpa.Spec.Metrics {
+		metric := model.HPAMetric{Type: string(m.Type)}
+		var target autoscalingv2.MetricTarget
+		metric.Name, target = metricSpecNameTarget(m)
+		metric.Target = metricTargetString(target)
+		for _, cur := range hpa.Status.CurrentMetrics {
+			if name, value, ok := metricStatusNameValue(cur); ok && cur.Type == m.Type && name == metric.Name {
+				metric.Current = metricValueString(value, target.Type)
+				break
+			}
+		}
+		info.Metrics = append(info.Metrics, metric)
+	}
+	for _, cond := range hpa.Status.Conditions {
+		info.Conditions = append(info.Conditions, model.PodCondition{
+			Type:    string(cond.Type),
+			Status:  string(cond.Status),
+			Reason:  cond.Reason,
+			Message: cond.Message,
+		})
+	}
+	return info
+}
+
+// metricSpecNameTarget 取指标名与目标（ContainerResource 名为 容器/资源）
+func metricSpecNameTarget(m autoscalingv2.MetricSpec) (string, autoscalingv2.MetricTarget) {
+	switch {
+	case m.Resource != nil:
+		return string(m.Resource.Name), m.Resource.Target
+	case m.ContainerResource != nil:
+		return m.ContainerResource.Container + "/" + string(m.ContainerResource.Name), m.ContainerResource.Target
+	case m.Pods != nil:
+		return m.Pods.Metric.Name, m.Pods.Target
+	case m.Object != nil:
+		return m.Object.Metric.Name, m.Object.Target
+	case m.External != nil:
+		return m.External.Metric.Name, m.External.Target
+	}
+	return "", autoscalingv2.MetricTarget{}
+}
+
+// metricStatusNameValue 取当前指标的名称与值，与 metricSpecNameTarget 的命名一致
+func metricStatusNameValue(m autoscalingv2.MetricStatus) (string, autoscalingv2.MetricValueStatus, bool) {
+	switch {
+	case m.Resource != nil:
+		return string(m.Resource.Name), m.Resource.Current, true
+	case m.ContainerResource != nil:
+		return m.ContainerResource.Container + "/" + string(m.ContainerResource.Name), m.ContainerResource.Current, true
+	case m.Pods != nil:
+		return m.Pods.Metric.Name, m.Pods.Current, true
+	case m.Object != nil:
+		return m.Object.Metric.Name, m.Object.Current, true
+	case m.External != nil:
+		return m.External.Metric.Name, m.External.Current, true
+	}
+	return "", autoscalingv2.MetricValueStatus{}, false
+}
+
+// metricTargetString 目标值：使用率为百分比，其余为数量
+func metricTargetString(t autoscalingv2.MetricTarget) string {
+	switch {
+	case t.Type == autoscalingv2.UtilizationMetricType && t.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *t.AverageUtilization)
+	case t.Type == autoscalingv2.AverageValueMetricType && t.AverageValue != nil:
+		return t.AverageValue.String()
+	case t.Value != nil:
+		return t.Value.String()
+	}
+	return ""
+}
+
+// metricValueString 当前值按目标类型取对应字段，便于与目标直接比较
+func metricValueString(v autoscalingv2.MetricValueStatus, targetType autoscalingv2.MetricTargetType) string {
+	switch {
+	case targetType == autoscalingv2.UtilizationMetricType && v.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *v.AverageUtilization)
+	case targetType == autoscalingv2.AverageValueMetricType && v.AverageValue != nil:
+		return v.AverageValue.String()
+	case v.Value != nil:
+		return v.Value.String()
+	case v.AverageValue != nil:
+		return v.AverageValue.String()
+	}
+	return ""
+}
+
+// hpaScaleGuard 检查目标副本数是否由 HPA 管理：未 force 时返回 HPAConflictError，force 时放行并返回提示。
+// HPA 列表读取失败（如无权限、apiserver 异常）时无法确认，未 force 时返回错误，force 时放行并提示。
+func hpaScaleGuard(ctx context.Context, client *k8s.Client, namespace string, gk schema.GroupKind, name string, force bool) (string, error) {
+	hpas, _, err := cachedHPAs(ctx, client, namespace, model.ListQuery{})
+	if err != nil {
+		if !force {
+			return "", fmt.Errorf("读取 HPA 失败，无法确认副本数是否由 HPA 管理（可加 force=true 跳过检查）: %w", err)
+		}
+		logger.Warn("扩缩容 %s %s/%s 时读取 HPA 失败: %v", gk.Kind, namespace, name, err)
+		return fmt.Sprintf("读取 HPA 失败，未能确认副本数是否由 HPA 管理: %v", err), nil
+	}
+	hpa := findScalingHPA(hpas, gk, name)
+	if hpa == nil {
+		return "", nil
+	}
+	conflict := &HPAConflictError{HPA: hpa.Name, MinReplicas: 1, MaxReplicas: hpa.Spec.MaxReplicas}
+	if hpa.Spec.MinReplicas != nil {
+		conflict.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	if !force {
+		return "", conflict
+	}
+	return fmt.Sprintf("副本数由 HPA %s 管理（%d-%d），本次手动扩缩容可能被还原", hpa.Name, conflict.MinReplicas, conflict.MaxReplicas), nil
+}
+
+// findScalingHPA 找到以指定对象为扩缩容目标的 HPA
+func findScalingHPA(hpas []*autoscalingv2.HorizontalPodAutoscaler, gk schema.GroupKind, name string) *autoscalingv2.HorizontalPodAutoscaler {
+	for _, hpa := range hpas {
+		ref := hpa.Spec.ScaleTargetRef
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if ref.Kind == gk.Kind && gv.Group == gk.Group && ref.Name == name {
+			return hpa
+		}
+	}
+	return nil
+}
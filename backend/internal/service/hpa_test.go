@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestApplyHPAForm 表单按类型增删 CPU/内存指标并保留其他指标，非法副本数与无指标时报错
+func TestApplyHPAForm(t *testing.T) {
+	rps := resource.MustParse("100")
+	pods := autoscalingv2.MetricSpec{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricSource{
+		Metric: autoscalingv2.MetricIdentifier{Name: "rps"},
+		Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &rps},
+	}}
+	base := func() autoscalingv2.HorizontalPodAutoscalerSpec {
+		return autoscalingv2.HorizontalPodAutoscalerSpec{MinReplicas: int32Ptr(2), MaxReplicas: 5, Metrics: []autoscalingv2.MetricSpec{pods}}
+	}
+
+	spec := base()
+	if err := applyHPAForm(&spec, model.HPAForm{CPUUtilization: int32Ptr(70), MemoryUtilization: int32Ptr(80)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Metrics) != 3 || spec.Metrics[0].Pods == nil || spec.Metrics[1].Resource.Name != corev1.ResourceCPU || *spec.Metrics[2].Resource.Target.AverageUtilization != 80 {
+		t.Errorf("unexpected metrics: %+v", spec.Metrics)
+	}
+	if err := applyHPAForm(&spec, model.HPAForm{CPUUtilization: int32Ptr(0), MaxReplicas: int32Ptr(10)}); err != nil || len(spec.Metrics) != 2 || spec.MaxReplicas != 10 {
+		t.Errorf("remove cpu: err=%v metrics=%d max=%d", err, len(spec.Metrics), spec.MaxReplicas)
+	}
+
+	errCases := map[string]model.HPAForm{
+		"max below min": {MaxReplicas: int32Ptr(1)},
+		"min zero":      {MinReplicas: int32Ptr(0)},
+		"negative":      {CPUUtilization: int32Ptr(-1)},
+	}
+	for name, form := range errCases {
+		spec := base()
+		if err := applyHPAForm(&spec, form); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	empty := autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 3}
+	if err := applyHPAForm(&empty, model.HPAForm{}); err == nil {
+		t.Error("no metrics: expected error")
+	}
+}
+
+// TestConvertHPAMetrics HPA 指标的目标与当前值格式化为百分比或数量
+func TestConvertHPAMetrics(t *testing.T) {
+	usage := resource.MustParse("300Mi")
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			MaxReplicas: 5,
+			Metrics: []autoscalingv2.MetricSpec{
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU, Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: int32Ptr(80)},
+				}},
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceMemory, Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &usage},
+				}},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentMetrics: []autoscalingv2.MetricStatus{
+			{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricStatus{
+				Name: corev1.ResourceCPU, Current: autoscalingv2.MetricValueStatus{AverageUtilization: int32Ptr(45), AverageValue: &usage},
+			}},
+		}},
+	}
+
+	info := convertHPA(hpa)
+	if info.MinReplicas != 1 || len(info.Metrics) != 2 {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+	if m := info.Metrics[0]; m.Name != "cpu" || m.Target != "80%" || m.Current != "45%" {
+		t.Errorf("cpu metric: %+v", m)
+	}
+	if m := info.Metrics[1]; m.Target != "300Mi" || m.Current != "" {
+		t.Errorf("memory metric: %+v", m)
+	}
+}
+
+// TestFindScalingHPA 按扩缩容目标的 group、kind 与名称查找 HPA
+func TestFindScalingHPA(t *testing.T) {
+	hpa := func(apiVersion, kind, name string) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: apiVersion, Kind: kind, Name: name},
+		}}
+	}
+	hpas := []*autoscalingv2.HorizontalPodAutoscaler{hpa("apps/v1", "StatefulSet", "web"), hpa("apps/v1", "Deployment", "web")}
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	if got := findScalingHPA(hpas, deployment, "web"); got != hpas[1] {
+		t.Errorf("got %v, want deployment hpa", got)
+	}
+	if got := findScalingHPA(hpas, deployment, "api"); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
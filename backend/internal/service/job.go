@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// jobGeneratedLabels Job 控制器自动添加到 Pod 模板与选择器上的标签，重试时需去掉以便重新生成
+var jobGeneratedLabels = []string{
+	"controller-uid",
+	"job-name",
+	batchv1.ControllerUidLabel,
+	batchv1.JobNameLabel,
+}
+
+// JobService Job服务
+type JobService struct {
+	k8sClient *k8s.Client
+}
+
+// NewJobService 创建Job服务
+func NewJobService(k8sClient *k8s.Client) *JobService {
+	return &JobService{k8sClient: k8sClient}
+}
+
+// GetK8sClient 获取K8s客户端
+func (s *JobService) GetK8sClient() *k8s.Client {
+	return s.k8sClient
+}
+
+// ListJobs 获取Job列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *JobService) ListJobs(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedJobs(ctx, s.k8sClient, namespace, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageList(list, listMeta, q, convertJob), nil
+}
+
+// GetJob 获取Job详情，含失败原因与所属 Pod
+func (s *JobService) GetJob(ctx context.Context, namespace, name string) (*model.JobDetail, error) {
+	job, err := s.k8sClient.ClientSet.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.JobDetail{JobInfo: convertJob(job), Pods: []model.JobPod{}}
+	if job.Spec.BackoffLimit != nil {
+		detail.BackoffLimit = *job.Spec.BackoffLimit
+	}
+	detail.ActiveDeadlineSeconds = job.Spec.ActiveDeadlineSeconds
+	if cond := jobCondition(job, batchv1.JobFailed); cond != nil {
+		detail.FailureReason, detail.FailureMessage = cond.Reason, cond.Message
+	}
+
+	if job.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+		pods, _, err := cachedPods(ctx, s.k8sClient, namespace, model.ListQuery{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		sortByNamespaceName(pods)
+		for _, pod := range pods {
+			if metav1.IsControlledBy(pod, job) {
+				detail.Pods = append(detail.Pods, convertJobPod(pod))
+			}
+		}
+	}
+	return detail, nil
+}
+
+// DeleteJob 删除Job（后台级联删除其 Pod；Job 默认的删除策略会遗留 Pod）
+func (s *JobService) DeleteJob(ctx context.Context, namespace, name string, dryRun bool) error {
+	opts := jobDeleteOptions()
+	opts.DryRun = dryRunOption(dryRun)
+	return s.k8sClient.ClientSet.BatchV1().Jobs(namespace).Delete(ctx, name, opts)
+}
+
+// DeleteFinishedJobs 删除命名空间下已结束的 Job。
+// status 为 complete/failed 时只删对应结果，为空删除全部已结束的；cronJob 非空时只删该 CronJob 创建的 Job。
+func (s *JobService) DeleteFinishedJobs(ctx context.Context, namespace, status, cronJob string) ([]string, error) {
+	switch status {
+	case "", "complete", "failed":
+	default:
+		return nil, fmt.Errorf("status 仅支持 complete、failed")
+	}
+	list, err := s.k8sClient.ClientSet.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []string{}
+	for i := range list.Items {
+		job := &list.Items[i]
+		if !jobFinishedMatches(job, status, cronJob) {
+			continue
+		}
+		if err := s.k8sClient.ClientSet.BatchV1().Jobs(namespace).Delete(ctx, job.Name, jobDeleteOptions()); err != nil {
+			return deleted, fmt.Errorf("删除 %s 失败: %w", job.Name, err)
+		}
+		deleted = append(deleted, job.Name)
+	}
+	return deleted, nil
+}
+
+// RetryJob 以失败 Job 的规格重新创建一个 Job（Job 模板不可变，无法原地重跑），返回新 Job 名称
+func (s *JobService) RetryJob(ctx context.Context, namespace, name string) (string, error) {
+	job, err := s.k8sClient.ClientSet.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if jobStatus(job) != "Failed" {
+		return "", fmt.Errorf("%s 未失败，无需重试", name)
+	}
+
+	retry := retryJob(job, time.Now())
+	created, err := s.k8sClient.ClientSet.BatchV1().Jobs(namespace).Create(ctx, retry, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return created.Name, nil
+}
+
+// retryJob 复制 Job 规格：去掉控制器生成的选择器与标签，保留 CronJob 归属以便历史清理
+func retryJob(job *batchv1.Job, now time.Time) *batchv1.Job {
+	spec := job.Spec.DeepCopy()
+	spec.Selector = nil
+	spec.ManualSelector = nil
+	for _, key := range jobGeneratedLabels {
+		delete(spec.Template.Labels, key)
+	}
+
+	labels := map[string]string{}
+	for k, v := range job.Labels {
+		labels[k] = v
+	}
+	for _, key := range jobGeneratedLabels {
+		delete(labels, key)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            derivedJobName(job.Name, "retry", now),
+			Namespace:       job.Namespace,
+			Labels:          labels,
+			Annotations:     map[string]string{"kube-admin.io/retry-of": job.Name},
+			OwnerReferences: job.OwnerReferences,
+		},
+		Spec: *spec,
+	}
+}
+
+// derivedJobName 生成 <base>-<kind>-<unix秒>-<5 位随机串>，随机串避免同一秒内重复触发时重名；
+// 超出 63 字符时截断 base
+func derivedJobName(base, kind string, now time.Time) string {
+	suffix := fmt.Sprintf("-%s-%d-%s", kind, now.Unix(), utilrand.String(5))
+	if limit := 63 - len(suffix); len(base) > limit {
+		base = base[:limit]
+	}
+	return base + suffix
+}
+
+// jobDeleteOptions 后台级联删除
+func jobDeleteOptions() metav1.DeleteOptions {
+	policy := metav1.DeletePropagationBackground
+	return metav1.DeleteOptions{PropagationPolicy: &policy}
+}
+
+// jobFinishedMatches Job 是否已结束且符合状态与 CronJob 过滤条件
+func jobFinishedMatches(job *batchv1.Job, status, cronJob string) bool {
+	switch jobStatus(job) {
+	case "Complete":
+		if status == "failed" {
+			return false
+		}
+	case "Failed":
+		if status == "complete" {
+			return false
+		}
+	default:
+		return false
+	}
+	if cronJob == "" {
+		return true
+	}
+	owner := metav1.GetControllerOf(job)
+	return owner != nil && owner.Kind == "CronJob" && owner.Name == cronJob
+}
+
+// jobCondition 返回状态为 True 的指定条件
+func jobCondition(job *batchv1.Job, t batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if c := &job.Status.Conditions[i]; c.Type == t && c.Status == corev1.ConditionTrue {
+			return c
+		}
+	}
+	return nil
+}
+
+// jobStatus 与 kubectl 一致：Complete/Failed 条件优先，其次暂停，否则为运行中
+func jobStatus(job *batchv1.Job) string {
+	switch {
+	case jobCondition(job, batchv1.JobComplete) != nil:
+		return "Complete"
+	case jobCondition(job, batchv1.JobFailed) != nil:
+		return "Failed"
+	case job.Spec.Suspend != nil && *job.Spec.Suspend:
+		return "Suspended"
+	}
+	return "Running"
+}
+
+// jobDuration 结束的 Job 取开始到完成/失败的时长，运行中取到当前的时长
+func jobDuration(job *batchv1.Job, now time.Time) time.Duration {
+	if job.Status.StartTime == nil {
+		return 0
+	}
+	end := now
+	if job.Status.CompletionTime != nil {
+		end = job.Status.CompletionTime.Time
+	} else if cond := jobCondition(job, batchv1.JobFailed); cond != nil {
+		end = cond.LastTransitionTime.Time
+	}
+	return end.Sub(job.Status.StartTime.Time).Round(time.Second)
+}
+
+// convertJob 转换Job对象
+func convertJob(job *batchv1.Job) model.JobInfo {
+	info := model.JobInfo{
+		K8sResource: model.K8sResource{
+			Name:              job.Name,
+			Namespace:         job.Namespace,
+			Labels:            job.Labels,
+			Annotations:       job.Annotations,
+			CreationTimestamp: job.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			ResourceVersion:   job.ResourceVersion,
+		},
+		Completions: job.Spec.Completions,
+		Parallelism: 1,
+		Succeeded:   job.Status.Succeeded,
+		Failed:      job.Status.Failed,
+		Active:      job.Status.Active,
+		Status:      jobStatus(job),
+		Images:      podSpecImages(&job.Spec.Template.Spec),
+	}
+	if job.Spec.Parallelism != nil {
+		info.Parallelism = *job.Spec.Parallelism
+	}
+	if job.Status.StartTime != nil {
+		info.StartTime = job.Status.StartTime.Format("2006-01-02 15:04:05")
+		info.Duration = jobDuration(job, time.Now()).String()
+	}
+	if job.Status.CompletionTime != nil {
+		info.CompletionTime = job.Status.CompletionTime.Format("2006-01-02 15:04:05")
+	}
+	if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "CronJob" {
+		info.Owner = &model.OwnerRef{Kind: owner.Kind, Name: owner.Name}
+	}
+	return info
+}
+
+// convertJobPod 转换 Job 的 Pod，取第一个非正常的容器等待/终止原因
+func convertJobPod(pod *corev1.Pod) model.JobPod {
+	jp := model.JobPod{Name: pod.Name, Phase: string(pod.Status.Phase), Node: pod.Spec.NodeName}
+	if pod.Status.StartTime != nil {
+		jp.StartTime = pod.Status.StartTime.Format("2006-01-02 15:04:05")
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		jp.Restarts += cs.RestartCount
+		if jp.Reason != "" {
+			continue
+		}
+		if w := cs.State.Waiting; w != nil && w.Reason != "" {
+			jp.Reason = w.Reason
+		} else if t := cs.State.Terminated; t != nil && t.ExitCode != 0 {
+			jp.Reason = t.Reason
+		}
+	}
+	return jp
+}
+
+// sortJobsByCreation 按创建时间降序（同一时间按名称）
+func sortJobsByCreation(jobs []*batchv1.Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		a, b := jobs[i].CreationTimestamp, jobs[j].CreationTimestamp
+		if !a.Equal(&b) {
+			return b.Before(&a)
+		}
+		return jobs[i].Name < jobs[j].Name
+	})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testJob(name string, conds ...batchv1.JobConditionType) *batchv1.Job {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	for _, t := range conds {
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: t, Status: corev1.ConditionTrue})
+	}
+	return job
+}
+
+// TestJobFinishedMatches 已结束的 Job 按状态与所属 CronJob 过滤
+func TestJobFinishedMatches(t *testing.T) {
+	controller := true
+	owned := testJob("nightly-1", batchv1.JobFailed)
+	owned.OwnerReferences = []metav1.OwnerReference{{Kind: "CronJob", Name: "nightly", Controller: &controller}}
+
+	cases := []struct {
+		name    string
+		job     *batchv1.Job
+		status  string
+		cronJob string
+		want    bool
+	}{
+		{name: "running", job: testJob("a")},
+		{name: "complete any", job: testJob("a", batchv1.JobComplete), want: true},
+		{name: "complete filtered out", job: testJob("a", batchv1.JobComplete), status: "failed"},
+		{name: "failed", job: testJob("a", batchv1.JobFailed), status: "failed", want: true},
+		{name: "cronjob owner", job: owned, cronJob: "nightly", want: true},
+		{name: "other cronjob", job: owned, cronJob: "weekly"},
+		{name: "bare job with cronjob filter", job: testJob("a", batchv1.JobComplete), cronJob: "nightly"},
+	}
+	for _, tc := range cases {
+		if got := jobFinishedMatches(tc.job, tc.status, tc.cronJob); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestRetryJob 重试 Job 去掉自动生成的选择器与标签、不修改原对象，名称截断到 63 字符且不重复
+func TestRetryJob(t *testing.T) {
+	manual := true
+	job := testJob("migrate", batchv1.JobFailed)
+	job.Labels = map[string]string{"app": "db", batchv1.JobNameLabel: "migrate"}
+	job.Spec = batchv1.JobSpec{
+		Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{batchv1.ControllerUidLabel: "uid"}},
+		ManualSelector: &manual,
+		Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			"app": "db", "controller-uid": "uid", batchv1.ControllerUidLabel: "uid", batchv1.JobNameLabel: "migrate",
+		}}},
+	}
+
+	retry := retryJob(job, time.Unix(1700000000, 0))
+	if !strings.HasPrefix(retry.Name, "migrate-retry-1700000000-") || retry.Spec.Selector != nil || retry.Spec.ManualSelector != nil {
+		t.Fatalf("unexpected retry job: %s %+v", retry.Name, retry.Spec)
+	}
+	if len(retry.Spec.Template.Labels) != 1 || len(retry.Labels) != 1 {
+		t.Errorf("generated labels not stripped: %v %v", retry.Spec.Template.Labels, retry.Labels)
+	}
+	if job.Spec.Selector == nil || len(job.Spec.Template.Labels) != 4 {
+		t.Error("original job was modified")
+	}
+	if name := derivedJobName(strings.Repeat("x", 63), "manual", time.Unix(1700000000, 0)); len(name) != 63 {
+		t.Errorf("name not truncated: %d", len(name))
+	}
+	if derivedJobName("migrate", "manual", time.Unix(1700000000, 0)) == derivedJobName("migrate", "manual", time.Unix(1700000000, 0)) {
+		t.Error("names derived within the same second collide")
+	}
+}
+
+// TestNextSchedule 按 cron 表达式与时区计算下次调度时间，表达式或时区无效时报错
+func TestNextSchedule(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	shanghai := "Asia/Shanghai"
+	invalidTZ := "Mars/Olympus"
+	cases := []struct {
+		schedule string
+		tz       *string
+		want     time.Time
+		wantErr  bool
+	}{
+		{schedule: "0 * * * *", want: time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)},
+		{schedule: "@daily", want: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		// 上海 18:30，下一次 02:00 即 UTC 18:00
+		{schedule: "0 2 * * *", tz: &shanghai, want: time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)},
+		{schedule: "61 * * * *", wantErr: true},
+		{schedule: "0 * * * *", tz: &invalidTZ, wantErr: true},
+	}
+	for _, tc := range cases {
+		cj := &batchv1.CronJob{Spec: batchv1.CronJobSpec{Schedule: tc.schedule, TimeZone: tc.tz}}
+		got, err := nextSchedule(cj, now)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: err=%v", tc.schedule, err)
+			continue
+		}
+		if !tc.wantErr && !got.Equal(tc.want) {
+			t.Errorf("%s: got %s, want %s", tc.schedule, got.UTC(), tc.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/config"
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// 上传的 kustomization 压缩包限制
+const (
+	MaxKustomizeArchiveSize = 20 << 20  // 压缩包大小
+	maxKustomizeExtracted   = 100 << 20 // 解压后总大小
+	maxKustomizeFiles       = 5000      // 文件数
+)
+
+// scpUserPattern kustomize 识别 user@host:repo 形式 git 地址时使用的用户名规则
+var scpUserPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*@`)
+
+// ErrKustomizeDirDisabled 未配置 KUSTOMIZE_DIR 时无法按路径构建
+var ErrKustomizeDirDisabled = errors.New("未配置 KUSTOMIZE_DIR，只能上传 kustomization 压缩包")
+
+// KustomizeBuildLocal 构建 KUSTOMIZE_DIR 内 dir 处（相对路径，为空表示根目录）的 kustomization
+func KustomizeBuildLocal(dir string) ([]*unstructured.Unstructured, error) {
+	root := config.App.KustomizeDir
+	if root == "" {
+		return nil, ErrKustomizeDirDisabled
+	}
+	target, err := pathInDir(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	return kustomizeBuild(filesys.MakeFsOnDisk(), target)
+}
+
+// KustomizeBuildArchive 将上传的 tar、tar.gz 或 zip 解压到内存文件系统后构建，不落盘。
+// dir 为包内 kustomization 所在目录；为空时使用包的根目录，根目录没有 kustomization 且只有一个顶层目录时进入该目录
+func KustomizeBuildArchive(data []byte, dir string) ([]*unstructured.Unstructured, error) {
+	fsys, err := extractArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	target, err := archiveEntryPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" && !hasKustomization(fsys, target) {
+		if entries, _ := fsys.ReadDir(target); len(entries) == 1 && fsys.IsDir(path.Join(target, entries[0])) {
+			target = path.Join(target, entries[0])
+		}
+	}
+	return kustomizeBuild(fsys, target)
+}
+
+// KustomizeResult 将构建出的对象转为预览结果
+func KustomizeResult(objs []*unstructured.Unstructured) (*model.KustomizeBuildResult, error) {
+	result := &model.KustomizeBuildResult{Objects: make([]model.ManifestObject, 0, len(objs))}
+	var buf strings.Builder
+	for i, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+		result.Objects = append(result.Objects, model.ManifestObject{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+	}
+	result.YAML = buf.String()
+	return result, nil
+}
+
+// kustomizeBuild 进程内执行 kustomize build（同 kubectl kustomize 的默认选项：文件只能从各 kustomization 根目录内加载，插件关闭）。
+// kustomize 会直接拉取远程 resources/base（http 地址与 git 仓库），build 前先检查整棵 kustomization 树，只允许本地路径
+func kustomizeBuild(fsys filesys.FileSystem, dir string) ([]*unstructured.Unstructured, error) {
+	if err := checkLocalKustomization(fsys, dir, map[string]bool{}); err != nil {
+		return nil, err
+	}
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("kustomize build 失败: %w", err)
+	}
+	if resMap.Size() == 0 {
+		return nil, fmt.Errorf("kustomize build 没有生成任何资源对象")
+	}
+	// 经 YAML 重新解码，得到 JSON 兼容的数值类型（kyaml 直接转换的 int 无法 DeepCopy）
+	data, err := resMap.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+	return decodeManifests(string(data))
+}
+
+// checkLocalKustomization 递归检查 dir 处的 kustomization 及其引用的本地目录，拒绝任何会触发远程加载的条目。
+// 无法解析的 kustomization 交给 kustomize build 报错
+func checkLocalKustomization(fsys filesys.FileSystem, dir string, seen map[string]bool) error {
+	dir = path.Clean(dir)
+	if seen[dir] {
+		return nil
+	}
+	seen[dir] = true
+	var data []byte
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if p := path.Join(dir, name); fsys.Exists(p) {
+			var err error
+			if data, err = fsys.ReadFile(p); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if data == nil {
+		return nil
+	}
+	var k types.Kustomization
+	if err := k.Unmarshal(data); err != nil {
+		return nil
+	}
+	k.FixKustomization() // bases 并入 resources，envSource 并入 envs
+
+	refs := append([]string{k.OpenAPI["path"]}, k.Resources...)
+	refs = append(refs, k.Components...)
+	refs = append(refs, k.Crds...)
+	refs = append(refs, k.Configurations...)
+	refs = append(refs, k.Generators...)
+	refs = append(refs, k.Transformers...)
+	refs = append(refs, k.Validators...)
+	for _, p := range k.PatchesStrategicMerge {
+		refs = append(refs, string(p))
+	}
+	for _, p := range append(k.Patches, k.PatchesJson6902...) {
+		refs = append(refs, p.Path)
+	}
+	for _, r := range k.Replacements {
+		refs = append(refs, r.Path)
+	}
+	for _, g := range k.ConfigMapGenerator {
+		refs = append(refs, generatorSources(g.KvPairSources)...)
+	}
+	for _, g := range k.SecretGenerator {
+		refs = append(refs, generatorSources(g.KvPairSources)...)
+	}
+	for _, ref := range refs {
+		if remoteKustomizeRef(ref) {
+			return fmt.Errorf("不允许加载远程资源 %s，只能引用本地文件", ref)
+		}
+	}
+
+	// 引用的本地目录可能是另一个 kustomization（base、component 或生成器配置目录）
+	for _, list := range [][]string{k.Resources, k.Components, k.Generators, k.Transformers, k.Validators} {
+		for _, ref := range list {
+			if strings.Contains(ref, "\n") || path.IsAbs(ref) {
+				continue
+			}
+			if sub := path.Join(dir, ref); fsys.IsDir(sub) {
+				if err := checkLocalKustomization(fsys, sub, seen); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// generatorSources configMapGenerator/secretGenerator 引用的文件路径（files 可写作 key=path）
+func generatorSources(src types.KvPairSources) []string {
+	refs := append([]string{}, src.EnvSources...)
+	for _, f := range src.FileSources {
+		if _, p, ok := strings.Cut(f, "="); ok {
+			f = p
+		}
+		refs = append(refs, f)
+	}
+	return refs
+}
+
+// remoteKustomizeRef 判断路径是否会被 kustomize 当作远程地址：带 scheme 的 URL（http、https、ssh、file）、
+// git:: 前缀、user@host:repo 形式以及省略 scheme 的 github.com 仓库。内联的多行补丁不是路径，跳过
+func remoteKustomizeRef(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.Contains(ref, "\n") {
+		return false
+	}
+	lower := strings.ToLower(ref)
+	if strings.Contains(ref, "://") || strings.HasPrefix(lower, "git::") ||
+		strings.HasPrefix(lower, "github.com/") || strings.HasPrefix(lower, "github.com:") {
+		return true
+	}
+	return scpUserPattern.MatchString(ref)
+}
+
+// hasKustomization 目录下是否有 kustomization.yaml / kustomization.yml / Kustomization
+func hasKustomization(fsys filesys.FileSystem, dir string) bool {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if fsys.Exists(path.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractArchive 按文件头识别 zip、gzip 压缩的 tar 或普通 tar，解压普通文件到内存文件系统（忽略目录与链接）
+func extractArchive(data []byte) (filesys.FileSystem, error) {
+	fsys := filesys.MakeFsInMemory()
+	var total int64
+	files := 0
+	add := func(name string, r io.Reader) error {
+		p, err := archiveEntryPath(name)
+		if err != nil {
+			return err
+		}
+		if files++; files > maxKustomizeFiles {
+			return fmt.Errorf("压缩包内文件超过 %d 个", maxKustomizeFiles)
+		}
+		content, err := io.ReadAll(io.LimitReader(r, maxKustomizeExtracted-total+1))
+		if err != nil {
+			return err
+		}
+		if total += int64(len(content)); total > maxKustomizeExtracted {
+			return fmt.Errorf("压缩包解压后超过 %d MB", maxKustomizeExtracted>>20)
+		}
+		return fsys.WriteFile(p, content)
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("无法读取 zip: %w", err)
+		}
+		for _, f := range zr.File {
+			if !f.Mode().IsRegular() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			err = add(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return fsys, nil
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("无法读取 gzip: %w", err)
+		}
+		defer gz.Close()
+		return fsys, extractTar(gz, add)
+	default:
+		return fsys, extractTar(bytes.NewReader(data), add)
+	}
+}
+
+// extractTar 逐个读取 tar 中的普通文件
+func extractTar(r io.Reader, add func(string, io.Reader) error) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("无法读取压缩包（支持 tar、tar.gz、zip）: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := add(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveEntryPath 将包内路径规范为内存文件系统中的绝对路径，拒绝含 ".." 的路径
+func archiveEntryPath(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	for _, seg := range strings.Split(name, "/") {
+		if seg == ".." {
+			return "", fmt.Errorf("非法路径: %s", name)
+		}
+	}
+	return path.Join(filesys.Separator, name), nil
+}
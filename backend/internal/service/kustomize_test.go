@@ -0,0 +1,152 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// kustomizeFiles 一个 base 与引用它的 overlay（overlay 改名前缀并修改副本数）
+var kustomizeFiles = map[string]string{
+	"app/base/kustomization.yaml": "resources:\n- deployment.yaml\n",
+	"app/base/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  selector:
+    matchLabels: {app: web}
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25
+`,
+	"app/overlays/prod/kustomization.yaml": `resources:
+- ../../base
+namePrefix: prod-
+namespace: shop
+replicas:
+- name: web
+  count: 3
+`,
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func zipped(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// TestKustomizeBuildArchive 从 tar.gz 或 zip 归档构建 kustomization，根目录只有一个顶层目录时自动进入
+func TestKustomizeBuildArchive(t *testing.T) {
+	for format, data := range map[string][]byte{"tar.gz": tarGz(t, kustomizeFiles), "zip": zipped(t, kustomizeFiles)} {
+		objs, err := KustomizeBuildArchive(data, "app/overlays/prod")
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(objs) != 1 || objs[0].GetName() != "prod-web" || objs[0].GetNamespace() != "shop" {
+			t.Fatalf("%s: unexpected objects %+v", format, objs)
+		}
+		// apply 前会 DeepCopy，数值类型必须是 JSON 兼容的
+		replicas, _, _ := unstructured.NestedFieldNoCopy(objs[0].DeepCopy().Object, "spec", "replicas")
+		if fmt.Sprint(replicas) != "3" {
+			t.Errorf("%s: replicas = %v", format, replicas)
+		}
+	}
+
+	// 根目录没有 kustomization 且只有一个顶层目录时自动进入
+	single := map[string]string{}
+	for name, content := range kustomizeFiles {
+		if strings.HasPrefix(name, "app/base/") {
+			single[strings.TrimPrefix(name, "app/")] = content
+		}
+	}
+	result, err := KustomizeBuildArchive(tarGz(t, single), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || result[0].GetName() != "web" {
+		t.Errorf("unexpected objects %+v", result)
+	}
+
+	if _, err := KustomizeBuildArchive(tarGz(t, map[string]string{"../evil.yaml": "x"}), ""); err == nil {
+		t.Error("expected error for path traversal")
+	}
+	if _, err := KustomizeBuildArchive(tarGz(t, kustomizeFiles), "../app"); err == nil {
+		t.Error("expected error for path traversal in path")
+	}
+}
+
+// TestKustomizeRejectsRemoteRefs 各处的远程引用（含嵌套 base 中的）在构建前被拒绝，不发出任何请求
+func TestKustomizeRejectsRemoteRefs(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	deployment := kustomizeFiles["app/base/deployment.yaml"]
+	cases := map[string]map[string]string{
+		"http resource": {"kustomization.yaml": "resources:\n- " + srv.URL + "/deployment.yaml\n"},
+		"git base":      {"kustomization.yaml": "bases:\n- github.com/org/repo/base?ref=v1\n"},
+		"scp component": {"kustomization.yaml": "components:\n- git@example.com:org/repo.git\n"},
+		"patch path": {
+			"kustomization.yaml": "resources:\n- deployment.yaml\npatches:\n- path: " + srv.URL + "/patch.yaml\n",
+			"deployment.yaml":    deployment,
+		},
+		"generator file": {"kustomization.yaml": "configMapGenerator:\n- name: cfg\n  files:\n  - app.conf=" + srv.URL + "/app.conf\n"},
+		// 远程地址藏在被引用的本地 base 中
+		"nested": {
+			"overlay/kustomization.yaml": "resources:\n- ../base\n",
+			"base/kustomization.yaml":    "resources:\n- " + srv.URL + "/deployment.yaml\n",
+		},
+	}
+	for name, files := range cases {
+		dir := ""
+		if _, ok := files["overlay/kustomization.yaml"]; ok {
+			dir = "overlay"
+		}
+		if _, err := KustomizeBuildArchive(tarGz(t, files), dir); err == nil || !strings.Contains(err.Error(), "远程") {
+			t.Errorf("%s: expected remote reference error, got %v", name, err)
+		}
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("server was fetched %d times", n)
+	}
+}
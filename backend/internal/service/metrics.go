@@ -11,11 +11,11 @@ import (
 
 // nodeMetricsMap 返回 nodeName -> 资源使用量映射。
 // metrics-server 不可用时返回 nil，调用方需优雅降级。
-func nodeMetricsMap(client *k8s.Client) map[string]corev1.ResourceList {
+func nodeMetricsMap(ctx context.Context, client *k8s.Client) map[string]corev1.ResourceList {
 	if client == nil || client.MetricsClientSet == nil {
 		return nil
 	}
-	list, err := client.MetricsClientSet.MetricsV1beta1().NodeMetricses().List(context.TODO(), metav1.ListOptions{})
+	list, err := client.MetricsClientSet.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil
 	}
@@ -27,11 +27,11 @@ func nodeMetricsMap(client *k8s.Client) map[string]corev1.ResourceList {
 }
 
 // podMetricsMap 返回 podName -> (containerName -> 资源使用量) 映射。
-func podMetricsMap(client *k8s.Client, namespace string) map[string]map[string]corev1.ResourceList {
+func podMetricsMap(ctx context.Context, client *k8s.Client, namespace string) map[string]map[string]corev1.ResourceList {
 	if client == nil || client.MetricsClientSet == nil {
 		return nil
 	}
-	list, err := client.MetricsClientSet.MetricsV1beta1().PodMetricses(namespace).List(context.TODO(), metav1.ListOptions{})
+	list, err := client.MetricsClientSet.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil
 	}
@@ -66,58 +66,58 @@ func formatAge(creationTime time.Time) string {
 	return fmt.Sprintf("%dy", years)
 }
 
-// ListNamespaces 获取Namespace列表
-func (s *NamespaceService) ListNamespaces() ([]model.NamespaceInfo, error) {
-	namespaceList, err := s.k8sClient.ClientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+// ListNamespaces 获取Namespace列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *NamespaceService) ListNamespaces(ctx context.Context, q model.ListQuery) (*model.PageResponse, error) {
+	namespaceList, listMeta, err := cachedNamespaces(ctx, s.k8sClient, q)
 	if err != nil {
 		return nil, err
 	}
 
-	var namespaces []model.NamespaceInfo
-	for _, ns := range namespaceList.Items {
-		info := model.NamespaceInfo{
-			Name:   ns.Name,
-			Status: string(ns.Status.Phase),
-			Age:    formatAge(ns.CreationTimestamp.Time),
-		}
-		// 转换 FinalizerName 切片为 string 切片
-		for _, f := range ns.Spec.Finalizers {
-			info.Finalizers = append(info.Finalizers, string(f))
-		}
-		if ns.DeletionTimestamp != nil {
-			t := ns.DeletionTimestamp.Time
-			info.DeletionTimestamp = &t
-		}
-		for _, c := range ns.Status.Conditions {
-			info.Conditions = append(info.Conditions, model.Condition{
-				Type:               string(c.Type),
-				Status:             string(c.Status),
-				Reason:             c.Reason,
-				Message:            c.Message,
-				LastTransitionTime: c.LastTransitionTime.Time,
-			})
-		}
-		namespaces = append(namespaces, info)
-	}
+	return pageList(namespaceList, listMeta, q, convertNamespace), nil
+}
 
-	return namespaces, nil
+// convertNamespace 转换 Namespace 对象
+func convertNamespace(ns *corev1.Namespace) model.NamespaceInfo {
+	info := model.NamespaceInfo{
+		Name:   ns.Name,
+		Status: string(ns.Status.Phase),
+		Age:    formatAge(ns.CreationTimestamp.Time),
+	}
+	// 转换 FinalizerName 切片为 string 切片
+	for _, f := range ns.Spec.Finalizers {
+		info.Finalizers = append(info.Finalizers, string(f))
+	}
+	if ns.DeletionTimestamp != nil {
+		t := ns.DeletionTimestamp.Time
+		info.DeletionTimestamp = &t
+	}
+	for _, c := range ns.Status.Conditions {
+		info.Conditions = append(info.Conditions, model.Condition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return info
 }
 
 // CreateNamespace 创建Namespace
-func (s *NamespaceService) CreateNamespace(name string) error {
+func (s *NamespaceService) CreateNamespace(ctx context.Context, name string) error {
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
 	}
 
-	_, err := s.k8sClient.ClientSet.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+	_, err := s.k8sClient.ClientSet.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 	return err
 }
 
 // DeleteNamespace 删除Namespace
-func (s *NamespaceService) DeleteNamespace(name string) error {
-	return s.k8sClient.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (s *NamespaceService) DeleteNamespace(ctx context.Context, name string) error {
+	return s.k8sClient.ClientSet.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
 }
 
 // FinalizeNamespace 强制清理 namespace 的 finalizers，解除 Terminating 卡死。
@@ -126,8 +126,8 @@ func (s *NamespaceService) DeleteNamespace(name string) error {
 // 普通删除无效。通过 /finalize 子资源提交 finalizers=[] 的 namespace 对象，
 // 绕过等待 controller，使 namespace 立即被真正删除。
 // 危险操作：会立即真正删除该 namespace 及其下所有资源，调用方必须二次确认。
-func (s *NamespaceService) FinalizeNamespace(name string) error {
-	ns, err := s.k8sClient.ClientSet.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+func (s *NamespaceService) FinalizeNamespace(ctx context.Context, name string) error {
+	ns, err := s.k8sClient.ClientSet.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -138,14 +138,14 @@ func (s *NamespaceService) FinalizeNamespace(name string) error {
 	}
 	_, err = s.k8sClient.ClientSet.CoreV1().RESTClient().Put().
 		Resource("namespaces").Name(name).SubResource("finalize").
-		Body(body).DoRaw(context.TODO())
+		Body(body).DoRaw(ctx)
 	return err
 }
 
 // ListUnavailableAPIServices 列出集群中 Available 状态非 True 的 APIService。
 // 用于诊断 namespace 因 DiscoveryFailed（失效 APIService）卡在 Terminating 的场景。
-func (s *NamespaceService) ListUnavailableAPIServices() ([]model.APIServiceInfo, error) {
-	list, err := s.k8sClient.AggregatorClient.ApiregistrationV1().APIServices().List(context.TODO(), metav1.ListOptions{})
+func (s *NamespaceService) ListUnavailableAPIServices(ctx context.Context) ([]model.APIServiceInfo, error) {
+	list, err := s.k8sClient.AggregatorClient.ApiregistrationV1().APIServices().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +184,6 @@ func (s *NamespaceService) ListUnavailableAPIServices() ([]model.APIServiceInfo,
 
 // DeleteAPIService 删除指定 APIService（集群级危险操作，调用方需二次确认）。
 // 用于移除后端服务已失效的 APIService，使被卡住的 namespace 继续完成删除。
-func (s *NamespaceService) DeleteAPIService(name string) error {
-	return s.k8sClient.AggregatorClient.ApiregistrationV1().APIServices().Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (s *NamespaceService) DeleteAPIService(ctx context.Context, name string) error {
+	return s.k8sClient.AggregatorClient.ApiregistrationV1().APIServices().Delete(ctx, name, metav1.DeleteOptions{})
 }
@@ -19,36 +19,34 @@ func NewNodeService(k8sClient *k8s.Client) *NodeService {
 	return &NodeService{k8sClient: k8sClient}
 }
 
-// ListNodes 获取Node列表（含实时使用率，需 metrics-server）
-func (s *NodeService) ListNodes() ([]model.NodeInfo, error) {
-	nodeList, err := s.k8sClient.ClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+// ListNodes 获取Node列表（含实时使用率，需 metrics-server）。
+// 支持选择器、名称搜索、排序与分页；默认读 informer 缓存。
+func (s *NodeService) ListNodes(ctx context.Context, q model.ListQuery) (*model.PageResponse, error) {
+	nodeList, listMeta, err := cachedNodes(ctx, s.k8sClient, q)
 	if err != nil {
 		return nil, err
 	}
 
-	metricsMap := nodeMetricsMap(s.k8sClient) // 优雅降级：nil 时不填充
-	nodes := make([]model.NodeInfo, 0, len(nodeList.Items))
-	for i := range nodeList.Items {
-		info := s.convertNode(&nodeList.Items[i])
-		if usage, ok := metricsMap[nodeList.Items[i].Name]; ok {
-			fillNodeUsage(&info, usage, &nodeList.Items[i])
+	metricsMap := nodeMetricsMap(ctx, s.k8sClient) // 优雅降级：nil 时不填充
+	return pageList(nodeList, listMeta, q, func(node *corev1.Node) model.NodeInfo {
+		info := s.convertNode(node)
+		if usage, ok := metricsMap[node.Name]; ok {
+			fillNodeUsage(&info, usage, node)
 		}
-		nodes = append(nodes, info)
-	}
-
-	return nodes, nil
+		return info
+	}), nil
 }
 
 // GetNode 获取Node详情
-func (s *NodeService) GetNode(name string) (*model.NodeInfo, error) {
-	node, err := s.k8sClient.ClientSet.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+func (s *NodeService) GetNode(ctx context.Context, name string) (*model.NodeInfo, error) {
+	node, err := s.k8sClient.ClientSet.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
 	info := s.convertNode(node)
 	// 单节点也尝试填充使用率
-	metricsMap := nodeMetricsMap(s.k8sClient)
+	metricsMap := nodeMetricsMap(ctx, s.k8sClient)
 	if usage, ok := metricsMap[node.Name]; ok {
 		fillNodeUsage(&info, usage, node)
 	}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ownerCacheTTL 归属关系缓存时间。UID 不会复用，但对象可能被收养/孤立，故仍需过期
+const ownerCacheTTL = 5 * time.Minute
+
+// ownerErrorTTL 查询失败（如无权限）的缓存时间，避免列表每次都对同一 owner 重试
+const ownerErrorTTL = 30 * time.Second
+
+// maxOwnerDepth 归属链最大深度，防止异常的循环引用
+const maxOwnerDepth = 10
+
+// ownerCacheEntry 某个 owner 对象自身的上级（nil 表示已是顶层），或查询它时的错误
+type ownerCacheEntry struct {
+	next    *metav1.OwnerReference
+	err     error
+	expires time.Time
+}
+
+// ownerCache 集群内 owner UID → 其上级引用，挂在集群客户端上
+type ownerCache struct {
+	sync.Mutex
+	entries map[types.UID]ownerCacheEntry
+	swept   time.Time
+}
+
+// ownerCacheKey 归属关系缓存在 k8s.Client.Shared 中的键
+type ownerCacheKey struct{}
+
+// ownerCacheOf 返回集群的归属关系缓存
+func ownerCacheOf(client *k8s.Client) *ownerCache {
+	return client.Shared(ownerCacheKey{}, func() interface{} {
+		return &ownerCache{entries: map[types.UID]ownerCacheEntry{}}
+	}).(*ownerCache)
+}
+
+// GetOwners 获取任意对象到顶层控制器的完整归属链
+func (s *ResourceService) GetOwners(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*model.OwnerChain, error) {
+	u, err := s.Get(ctx, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := resolveOwnerChain(ctx, s.k8sClient, u.GetNamespace(), u.GetOwnerReferences())
+	result := &model.OwnerChain{Owners: chain}
+	if len(chain) > 0 {
+		result.Top = &chain[len(chain)-1]
+	}
+	if err != nil {
+		result.Incomplete = true
+		result.Message = err.Error()
+	}
+	return result, nil
+}
+
+// resolveOwnerChain 沿 ownerReferences 逐级向上（优先 controller 引用），返回从直接 owner 到顶层控制器的链。
+// 有 informer 的类型（ReplicaSet、Job 等）从缓存读取；其余类型的上级通过元数据 GET 获取并按集群缓存。
+// 查询失败时返回已解析的部分与错误。
+func resolveOwnerChain(ctx context.Context, client *k8s.Client, namespace string, refs []metav1.OwnerReference) ([]model.OwnerRef, error) {
+	return walkOwners(refs, func(ref *metav1.OwnerReference) (*metav1.OwnerReference, error) {
+		if next, ok := informerOwner(ctx, client, namespace, ref); ok {
+			return next, nil
+		}
+		if entry, ok := cachedOwner(client, ref.UID); ok {
+			return entry.next, entry.err
+		}
+		next, err := fetchOwner(ctx, client, namespace, ref)
+		if ctx.Err() == nil {
+			storeOwner(client, ref.UID, next, err)
+		}
+		return next, err
+	})
+}
+
+// walkOwners 从 refs 开始逐级调用 lookup 获取上级，直到顶层、出现循环或超过最大深度
+func walkOwners(refs []metav1.OwnerReference, lookup func(*metav1.OwnerReference) (*metav1.OwnerReference, error)) ([]model.OwnerRef, error) {
+	chain := []model.OwnerRef{}
+	seen := map[types.UID]bool{}
+	for ref := ownerToFollow(refs); ref != nil; {
+		if seen[ref.UID] || len(chain) >= maxOwnerDepth {
+			return chain, fmt.Errorf("%s/%s 的归属链存在循环或层级过深", ref.Kind, ref.Name)
+		}
+		seen[ref.UID] = true
+		chain = append(chain, model.OwnerRef{Kind: ref.Kind, Name: ref.Name, APIVersion: ref.APIVersion})
+
+		next, err := lookup(ref)
+		if err != nil {
+			return chain, fmt.Errorf("查询 %s/%s 失败: %w", ref.Kind, ref.Name, err)
+		}
+		ref = next
+	}
+	return chain, nil
+}
+
+// ownerToFollow 取 controller 引用；没有 controller 时取第一个 owner
+func ownerToFollow(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+	return nil
+}
+
+// ownerListers 有 informer 缓存的 owner 类型，按名称从缓存读取对象
+var ownerListers = map[schema.GroupKind]func(ctx context.Context, client *k8s.Client, namespace, name string) (metav1.Object, error){
+	{Group: "apps", Kind: "ReplicaSet"}: func(ctx context.Context, client *k8s.Client, namespace, name string) (metav1.Object, error) {
+		lister, err := client.Cache().ReplicaSets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.ReplicaSets(namespace).Get(name)
+	},
+	{Group: "apps", Kind: "Deployment"}: func(ctx context.Context, client *k8s.Client, namespace, name string) (metav1.Object, error) {
+		lister, err := client.Cache().Deployments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.Deployments(namespace).Get(name)
+	},
+	{Group: "apps", Kind: "StatefulSet"}: func(ctx context.Context, client *k8s.Client, namespace, name string) (metav1.Object, error) {
+		lister, err := client.Cache().StatefulSets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.StatefulSets(namespace).Get(name)
+	},
+	{Group: "apps", Kind: "DaemonSet"}: func(ctx context.Context, client *k8s.Client, namespace, name string) (metav1.Object, error) {
+		lister, err := client.Cache().DaemonSets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.DaemonSets(namespace).Get(name)
+	},
+	{Group: "batch", Kind: "Job"}: func(ctx context.Context, client *k8s.Client, namespace, name string) (metav1.Object, error) {
+		lister, err := client.Cache().Jobs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.Jobs(namespace).Get(name)
+	},
+	{Group: "batch", Kind: "CronJob"}: func(ctx context.Context, client *k8s.Client, namespace, name string) (metav1.Object, error) {
+		lister, err := client.Cache().CronJobs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return lister.CronJobs(namespace).Get(name)
+	},
+}
+
+// informerOwner 从 informer 缓存读取 owner 的上级，ok=false 表示该类型没有缓存、缓存不可用或缓存中尚未出现该对象
+// （如刚创建、UID 不符），由调用方回退元数据 GET
+func informerOwner(ctx context.Context, client *k8s.Client, namespace string, ref *metav1.OwnerReference) (*metav1.OwnerReference, bool) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, false
+	}
+	get, ok := ownerListers[gv.WithKind(ref.Kind).GroupKind()]
+	if !ok {
+		return nil, false
+	}
+	obj, err := get(ctx, client, namespace, ref.Name)
+	if err != nil || obj.GetUID() != ref.UID {
+		return nil, false
+	}
+	return ownerToFollow(obj.GetOwnerReferences()), true
+}
+
+// fetchOwner 只取 owner 的元数据，返回它自己要继续跟随的上级。
+// owner 已被删除或重建（UID 不符）时视为顶层，链在此结束。
+func fetchOwner(ctx context.Context, client *k8s.Client, namespace string, ref *metav1.OwnerReference) (*metav1.OwnerReference, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := client.RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, err
+	}
+	res := client.MetadataClient.Resource(mapping.Resource)
+	var obj *metav1.PartialObjectMetadata
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		obj, err = res.Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	} else {
+		obj, err = res.Get(ctx, ref.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if obj.UID != ref.UID {
+		return nil, nil
+	}
+	return ownerToFollow(obj.OwnerReferences), nil
+}
+
+// cachedOwner 读取未过期的缓存条目
+func cachedOwner(client *k8s.Client, uid types.UID) (ownerCacheEntry, bool) {
+	cache := ownerCacheOf(client)
+	cache.Lock()
+	defer cache.Unlock()
+	entry, ok := cache.entries[uid]
+	if !ok || time.Now().After(entry.expires) {
+		return ownerCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// storeOwner 写入缓存，并定期清理过期条目
+func storeOwner(client *k8s.Client, uid types.UID, next *metav1.OwnerReference, err error) {
+	cache := ownerCacheOf(client)
+	cache.Lock()
+	defer cache.Unlock()
+	now := time.Now()
+	if now.Sub(cache.swept) > ownerCacheTTL {
+		for id, entry := range cache.entries {
+			if now.After(entry.expires) {
+				delete(cache.entries, id)
+			}
+		}
+		cache.swept = now
+	}
+	ttl := ownerCacheTTL
+	if err != nil {
+		ttl = ownerErrorTTL
+	}
+	cache.entries[uid] = ownerCacheEntry{next: next, err: err, expires: now.Add(ttl)}
+}
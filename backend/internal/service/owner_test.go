@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestWalkOwners 沿 ownerReferences 逐级解析到顶层控制器，查询失败时保留已解析部分
+func TestWalkOwners(t *testing.T) {
+	controller := true
+	ref := func(kind, name string) *metav1.OwnerReference {
+		return &metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: name, UID: types.UID(kind + "/" + name), Controller: &controller}
+	}
+	// Pod → ReplicaSet → Deployment → 自定义控制器（如 Argo Rollout 之类的上层 CRD）
+	parents := map[types.UID]*metav1.OwnerReference{
+		"ReplicaSet/web-1": ref("Deployment", "web"),
+		"Deployment/web":   ref("Rollout", "web"),
+	}
+	lookup := func(r *metav1.OwnerReference) (*metav1.OwnerReference, error) {
+		return parents[r.UID], nil
+	}
+
+	chain, err := walkOwners([]metav1.OwnerReference{*ref("ReplicaSet", "web-1")}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 3 || chain[0].Kind != "ReplicaSet" || chain[2].Kind != "Rollout" {
+		t.Errorf("unexpected chain %+v", chain)
+	}
+
+	// 查询失败时保留已解析部分
+	failing := func(r *metav1.OwnerReference) (*metav1.OwnerReference, error) {
+		if r.Kind == "Deployment" {
+			return nil, errors.New("forbidden")
+		}
+		return lookup(r)
+	}
+	chain, err = walkOwners([]metav1.OwnerReference{*ref("ReplicaSet", "web-1")}, failing)
+	if err == nil || len(chain) != 2 || chain[1].Name != "web" {
+		t.Errorf("partial chain = %+v, err = %v", chain, err)
+	}
+
+	// 循环引用
+	parents["Rollout/web"] = ref("ReplicaSet", "web-1")
+	if _, err := walkOwners([]metav1.OwnerReference{*ref("ReplicaSet", "web-1")}, lookup); err == nil {
+		t.Error("expected cycle error")
+	}
+
+	if chain, err := walkOwners(nil, lookup); err != nil || len(chain) != 0 {
+		t.Errorf("bare object: chain = %+v, err = %v", chain, err)
+	}
+}
+
+// TestOwnerToFollow 优先跟随 controller 引用，没有时取第一个
+func TestOwnerToFollow(t *testing.T) {
+	controller := true
+	refs := []metav1.OwnerReference{{Kind: "ConfigMap", Name: "a"}, {Kind: "Job", Name: "b", Controller: &controller}}
+	if got := ownerToFollow(refs); got == nil || got.Kind != "Job" {
+		t.Errorf("controller ref should win, got %+v", got)
+	}
+	if got := ownerToFollow(refs[:1]); got == nil || got.Kind != "ConfigMap" {
+		t.Errorf("first ref expected without controller, got %+v", got)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/remotecommand"
 )
 
@@ -31,49 +32,45 @@ func (s *PodService) GetK8sClient() *k8s.Client {
 	return s.k8sClient
 }
 
-// ListPods 获取Pod列表（含容器实时使用率，需 metrics-server）
-func (s *PodService) ListPods(namespace string) ([]model.PodInfo, error) {
-	podList, err := s.k8sClient.ClientSet.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+// ListPods 获取Pod列表（含容器实时使用率，需 metrics-server）。
+// 支持选择器、名称搜索、排序与分页；默认读 informer 缓存。
+func (s *PodService) ListPods(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	podList, listMeta, err := cachedPods(ctx, s.k8sClient, namespace, q)
 	if err != nil {
 		return nil, err
 	}
 
-	metricsMap := podMetricsMap(s.k8sClient, namespace) // 优雅降级
-	ownerMap := s.buildOwnerMap(namespace)              // RS→顶层工作负载 映射
-	var pods []model.PodInfo
-	for i := range podList.Items {
-		pods = append(pods, s.convertPod(&podList.Items[i], metricsMap, ownerMap))
-	}
-
-	return pods, nil
+	metricsMap := podMetricsMap(ctx, s.k8sClient, namespace) // 优雅降级
+	return pageList(podList, listMeta, q, func(pod *corev1.Pod) model.PodInfo {
+		return s.convertPod(pod, metricsMap, s.podOwner(ctx, pod))
+	}), nil
 }
 
 // GetPod 获取Pod详情
-func (s *PodService) GetPod(namespace, name string) (*model.PodInfo, error) {
-	pod, err := s.k8sClient.ClientSet.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+func (s *PodService) GetPod(ctx context.Context, namespace, name string) (*model.PodInfo, error) {
+	pod, err := s.k8sClient.ClientSet.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	metricsMap := podMetricsMap(s.k8sClient, namespace)
-	ownerMap := s.buildOwnerMap(namespace)
-	podInfo := s.convertPod(pod, metricsMap, ownerMap)
+	metricsMap := podMetricsMap(ctx, s.k8sClient, namespace)
+	podInfo := s.convertPod(pod, metricsMap, s.podOwner(ctx, pod))
 	return &podInfo, nil
 }
 
 // DeletePod 删除Pod
-func (s *PodService) DeletePod(namespace, name string) error {
-	return s.k8sClient.ClientSet.CoreV1().Pods(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (s *PodService) DeletePod(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
 }
 
 // GetPodLogs 获取Pod日志
-func (s *PodService) GetPodLogs(namespace, name, container string, tailLines int64) (string, error) {
+func (s *PodService) GetPodLogs(ctx context.Context, namespace, name, container string, tailLines int64) (string, error) {
 	req := s.k8sClient.ClientSet.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{
 		Container: container,
 		TailLines: &tailLines,
 	})
 
-	logs, err := req.DoRaw(context.TODO())
+	logs, err := req.DoRaw(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -83,7 +80,7 @@ func (s *PodService) GetPodLogs(namespace, name, container string, tailLines int
 
 // StreamLogs 通过 WebSocket 实时流式传输 Pod 日志。
 // 支持 follow（持续跟踪）、previous（上一个容器）、tailLines、sinceSeconds。
-func (s *PodService) StreamLogs(namespace, name, container string, follow, previous bool, tailLines int64, sinceSeconds int64, wsConn *websocket.Conn) error {
+func (s *PodService) StreamLogs(ctx context.Context, namespace, name, container string, follow, previous bool, tailLines int64, sinceSeconds int64, wsConn *websocket.Conn) error {
 	opts := &corev1.PodLogOptions{
 		Container: container,
 		Follow:    follow,
@@ -96,8 +93,25 @@ func (s *PodService) StreamLogs(namespace, name, container string, follow, previ
 		opts.SinceSeconds = &sinceSeconds
 	}
 
-	req := s.k8sClient.ClientSet.CoreV1().Pods(namespace).GetLogs(name, opts)
-	stream, err := req.Stream(context.TODO())
+	// follow 可能持续很久，使用不带整体请求超时的客户端，由 ctx 控制生命周期
+	clientSet, err := kubernetes.NewForConfig(s.k8sClient.StreamConfig())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// 读循环仅用于感知客户端关闭，浏览器关页后立即停止读取日志流
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := wsConn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	req := clientSet.CoreV1().Pods(namespace).GetLogs(name, opts)
+	stream, err := req.Stream(ctx)
 	if err != nil {
 		return fmt.Errorf("打开日志流失败: %v", err)
 	}
@@ -113,7 +127,7 @@ func (s *PodService) StreamLogs(namespace, name, container string, follow, previ
 			}
 		}
 		if rerr != nil {
-			if rerr == io.EOF {
+			if rerr == io.EOF || ctx.Err() != nil {
 				return nil
 			}
 			return rerr
@@ -122,7 +136,7 @@ func (s *PodService) StreamLogs(namespace, name, container string, follow, previ
 }
 
 // ExecCommand 在Pod中执行命令
-func (s *PodService) ExecCommand(namespace, podName, containerName string, command []string) (string, string, error) {
+func (s *PodService) ExecCommand(ctx context.Context, namespace, podName, containerName string, command []string) (string, string, error) {
 	// 创建REST client
 	client := s.k8sClient.ClientSet.CoreV1().RESTClient()
 
@@ -149,7 +163,7 @@ func (s *PodService) ExecCommand(namespace, podName, containerName string, comma
 
 	// 执行命令
 	var stdout, stderr bytes.Buffer
-	err = executor.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
 		Stdout: &stdout,
 		Stderr: &stderr,
 	})
@@ -162,7 +176,7 @@ func (s *PodService) ExecCommand(namespace, podName, containerName string, comma
 }
 
 // ExecTerminal 在Pod中创建交互式终端
-func (s *PodService) ExecTerminal(namespace, podName, containerName string, wsConn *websocket.Conn) error {
+func (s *PodService) ExecTerminal(ctx context.Context, namespace, podName, containerName string, wsConn *websocket.Conn) error {
 	// 创建REST client
 	client := s.k8sClient.ClientSet.CoreV1().RESTClient()
 
@@ -192,7 +206,7 @@ func (s *PodService) ExecTerminal(namespace, podName, containerName string, wsCo
 	}
 
 	// 执行命令
-	err = executor.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
 		Stdin:             ptyHandler,
 		Stdout:            ptyHandler,
 		Stderr:            ptyHandler,
@@ -262,8 +276,8 @@ func (w *wsStreamHandler) Close() error {
 	return w.conn.Close()
 }
 
-// convertPod 转换Pod对象。metricsMap 为该 namespace 的 pod metrics 映射，可为 nil。
-func (s *PodService) convertPod(pod *corev1.Pod, metricsMap map[string]map[string]corev1.ResourceList, ownerMap map[string]*model.OwnerRef) model.PodInfo {
+// convertPod 转换Pod对象。metricsMap 为该 namespace 的 pod metrics 映射，可为 nil；owner 为顶层归属，可为 nil。
+func (s *PodService) convertPod(pod *corev1.Pod, metricsMap map[string]map[string]corev1.ResourceList, owner *model.OwnerRef) model.PodInfo {
 	podInfo := model.PodInfo{
 		K8sResource: model.K8sResource{
 			Name:              pod.Name,
@@ -366,48 +380,18 @@ func (s *PodService) convertPod(pod *corev1.Pod, metricsMap map[string]map[strin
 		})
 	}
 
-	// 解析所属顶层工作负载（Deployment/StatefulSet/DaemonSet 等）
-	podInfo.Owner = resolvePodOwner(pod, ownerMap)
+	// 所属顶层工作负载（Deployment/StatefulSet/DaemonSet 等）
+	podInfo.Owner = owner
 
 	return podInfo
 }
 
-// buildOwnerMap 构建 namespace 内 ReplicaSet→顶层 Deployment 映射。
-// StatefulSet/DaemonSet 直接管 Pod（无需间接），故只解析 RS→Deployment。
-func (s *PodService) buildOwnerMap(namespace string) map[string]*model.OwnerRef {
-	m := map[string]*model.OwnerRef{}
-	rsList, err := s.k8sClient.ClientSet.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return m
-	}
-	for i := range rsList.Items {
-		rs := &rsList.Items[i]
-		for _, owner := range rs.OwnerReferences {
-			if owner.Controller != nil && *owner.Controller && owner.Kind == "Deployment" {
-				m[rs.Name] = &model.OwnerRef{Kind: owner.Kind, Name: owner.Name}
-				break
-			}
-		}
-	}
-	return m
-}
-
-// resolvePodOwner 解析 pod 顶层归属：controllerRef 为 RS 时查 ownerMap 上升到 Deployment；
-// 为 StatefulSet/DaemonSet/Job 等直接用；bare pod 返回 nil。
-func resolvePodOwner(pod *corev1.Pod, ownerMap map[string]*model.OwnerRef) *model.OwnerRef {
-	for _, owner := range pod.OwnerReferences {
-		if owner.Controller == nil || !*owner.Controller {
-			continue
-		}
-		if owner.Kind == "ReplicaSet" {
-			if o, ok := ownerMap[owner.Name]; ok {
-				return o
-			}
-		}
-		switch owner.Kind {
-		case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob":
-			return &model.OwnerRef{Kind: owner.Kind, Name: owner.Name}
-		}
+// podOwner 解析 Pod 的顶层归属（沿 ownerReferences 逐级向上，任意控制器类型）；
+// 中途查询失败时退回已解析到的最上一级，bare pod 返回 nil。
+func (s *PodService) podOwner(ctx context.Context, pod *corev1.Pod) *model.OwnerRef {
+	chain, _ := resolveOwnerChain(ctx, s.k8sClient, pod.Namespace, pod.OwnerReferences)
+	if len(chain) == 0 {
+		return nil
 	}
-	return nil
+	return &chain[len(chain)-1]
 }
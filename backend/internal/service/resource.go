@@ -1,21 +1,19 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/kube-admin/kube-admin/backend/internal/model"
 	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 // ResourceService 通用资源服务，基于 dynamic client + RESTMapper，
@@ -29,31 +27,59 @@ func NewResourceService(c *k8s.Client) *ResourceService {
 	return &ResourceService{k8sClient: c}
 }
 
-func (s *ResourceService) dynamicInterface() (dynamic.Interface, error) {
-	return dynamic.NewForConfig(s.k8sClient.Config)
-}
-
-// gvrFor 通过 RESTMapper 将 GVK 映射为 GVR，并返回是否命名空间级资源
+// gvrFor 通过集群共享的 RESTMapper 将 GVK 映射为 GVR，并返回是否命名空间级资源
 func (s *ResourceService) gvrFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, bool, error) {
-	dc, err := discovery.NewDiscoveryClientForConfig(s.k8sClient.Config)
-	if err != nil {
-		return schema.GroupVersionResource{}, false, err
-	}
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
-	gk := schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}
-	mapping, err := mapper.RESTMapping(gk, gvk.Version)
+	mapping, err := s.k8sClient.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
 		return schema.GroupVersionResource{}, false, err
 	}
 	return mapping.Resource, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
 }
 
-// namespacedResource 根据 GVR 构造 dynamic 资源接口，namespace 为空或 "all" 时为集群级/所有命名空间
-func (s *ResourceService) namespacedResource(gvr schema.GroupVersionResource, namespace string) (dynamic.ResourceInterface, error) {
-	dyn, err := s.dynamicInterface()
-	if err != nil {
+// APIResources 列出集群可用资源类型（各 group 取首选版本，不含子资源）。
+// 个别聚合 API 不可用时仍返回其余可发现的资源。refresh=true 时先失效发现缓存。
+func (s *ResourceService) APIResources(refresh bool) ([]model.APIResourceInfo, error) {
+	if refresh {
+		s.k8sClient.InvalidateDiscovery()
+	}
+	lists, err := s.k8sClient.CachedDiscovery().ServerPreferredResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
 		return nil, err
 	}
+
+	var resources []model.APIResourceInfo
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			resources = append(resources, model.APIResourceInfo{
+				Group:      gv.Group,
+				Version:    gv.Version,
+				Resource:   r.Name,
+				Kind:       r.Kind,
+				Namespaced: r.Namespaced,
+				Verbs:      r.Verbs,
+				ShortNames: r.ShortNames,
+			})
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group != resources[j].Group {
+			return resources[i].Group < resources[j].Group
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+	return resources, nil
+}
+
+// namespacedResource 根据 GVR 构造 dynamic 资源接口，namespace 为空或 "all" 时为集群级/所有命名空间
+func (s *ResourceService) namespacedResource(gvr schema.GroupVersionResource, namespace string) (dynamic.ResourceInterface, error) {
+	dyn := s.k8sClient.DynamicClient
 	// "all" 是前端「所有命名空间」哨兵，视为空（k8s all-namespaces）
 	if namespace != "" && namespace != "all" {
 		return dyn.Resource(gvr).Namespace(namespace), nil
@@ -61,88 +87,70 @@ func (s *ResourceService) namespacedResource(gvr schema.GroupVersionResource, na
 	return dyn.Resource(gvr), nil
 }
 
-// List 通用列表
-func (s *ResourceService) List(gvr schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+// List 通用列表。选择器与分块参数透传 apiserver，名称搜索/排序/偏移分页在返回结果上完成；
+// 返回的 Items 为当前页，total 为过滤后的总数。
+func (s *ResourceService) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, q model.ListQuery) (*unstructured.UnstructuredList, int64, error) {
 	iface, err := s.namespacedResource(gvr, namespace)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return iface.List(context.TODO(), metav1.ListOptions{})
+	list, err := iface.List(ctx, listOptions(q))
+	if err != nil {
+		return nil, 0, err
+	}
+	listMeta := metav1.ListMeta{Continue: list.GetContinue(), RemainingItemCount: list.GetRemainingItemCount()}
+	pageItems, total := pageObjects(itemPointers(list.Items), listMeta, q)
+	items := make([]unstructured.Unstructured, 0, len(pageItems))
+	for _, item := range pageItems {
+		items = append(items, *item)
+	}
+	list.Items = items
+	return list, total, nil
 }
 
 // Get 通用获取
-func (s *ResourceService) Get(gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+func (s *ResourceService) Get(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
 	iface, err := s.namespacedResource(gvr, namespace)
 	if err != nil {
 		return nil, err
 	}
-	return iface.Get(context.TODO(), name, metav1.GetOptions{})
+	return iface.Get(ctx, name, metav1.GetOptions{})
 }
 
-// Delete 通用删除
-func (s *ResourceService) Delete(gvr schema.GroupVersionResource, namespace, name string) error {
+// Delete 通用删除，dryRun 时只做服务端校验不真正删除
+func (s *ResourceService) Delete(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, dryRun bool) error {
 	iface, err := s.namespacedResource(gvr, namespace)
 	if err != nil {
 		return err
 	}
-	return iface.Delete(context.TODO(), name, metav1.DeleteOptions{})
+	return iface.Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
 }
 
-// ApplyFromYAML 解析 YAML 并创建或更新资源（存在则更新，不存在则创建）
-func (s *ResourceService) ApplyFromYAML(yamlStr string) (*unstructured.Unstructured, error) {
-	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(yamlStr)), 4096)
-	var obj unstructured.Unstructured
-	if err := decoder.Decode(&obj); err != nil {
-		return nil, fmt.Errorf("解析 YAML 失败: %w", err)
-	}
-	if len(obj.Object) == 0 {
-		return nil, fmt.Errorf("YAML 解析后为空对象")
-	}
-
-	gvk := obj.GroupVersionKind()
-	gvr, namespaced, err := s.gvrFor(gvk)
-	if err != nil {
-		return nil, fmt.Errorf("无法识别资源类型 %s: %w", gvk.String(), err)
-	}
-
-	dyn, err := s.dynamicInterface()
-	if err != nil {
-		return nil, err
-	}
-	var iface dynamic.ResourceInterface
-	if namespaced {
-		iface = dyn.Resource(gvr).Namespace(obj.GetNamespace())
-	} else {
-		iface = dyn.Resource(gvr)
-	}
-
-	// 存在则更新（保留 resourceVersion），不存在则创建
-	existing, err := iface.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
-	if err == nil {
-		obj.SetResourceVersion(existing.GetResourceVersion())
-		return iface.Update(context.TODO(), &obj, metav1.UpdateOptions{})
-	}
-	return iface.Create(context.TODO(), &obj, metav1.CreateOptions{})
-}
-
-// Patch 通用补丁
-func (s *ResourceService) Patch(gvr schema.GroupVersionResource, namespace, name string, patchType types.PatchType, patchData []byte) (*unstructured.Unstructured, error) {
+// Patch 通用补丁，dryRun 时返回服务端计算出的结果但不持久化
+func (s *ResourceService) Patch(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, patchType types.PatchType, patchData []byte, dryRun bool) (*unstructured.Unstructured, error) {
 	iface, err := s.namespacedResource(gvr, namespace)
 	if err != nil {
 		return nil, err
 	}
-	return iface.Patch(context.TODO(), name, patchType, patchData, metav1.PatchOptions{})
+	return iface.Patch(ctx, name, patchType, patchData, metav1.PatchOptions{DryRun: dryRunOption(dryRun)})
 }
 
-// Scale 通用扩缩容（适用于含 spec.replicas 的 workload：Deployment/StatefulSet/DaemonSet/ReplicaSet）
-func (s *ResourceService) Scale(gvr schema.GroupVersionResource, namespace, name string, replicas int32) error {
+// Scale 通用扩缩容（适用于含 spec.replicas 的 workload：Deployment/StatefulSet/DaemonSet/ReplicaSet）。
+// 目标由 HPA 管理时的处理同 ScaleDeployment；dryRun 时不持久化
+func (s *ResourceService) Scale(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, replicas int32, force, dryRun bool) (string, error) {
 	iface, err := s.namespacedResource(gvr, namespace)
 	if err != nil {
-		return err
+		return "", err
 	}
-	u, err := iface.Get(context.TODO(), name, metav1.GetOptions{})
+	var warning string
+	if gvk, err := s.k8sClient.RESTMapper().KindFor(gvr); err == nil {
+		if warning, err = hpaScaleGuard(ctx, s.k8sClient, namespace, gvk.GroupKind(), name, force); err != nil {
+			return "", err
+		}
+	}
+	u, err := iface.Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return err
+		return "", err
 	}
 	spec, _ := u.Object["spec"].(map[string]interface{})
 	if spec == nil {
@@ -150,13 +158,20 @@ func (s *ResourceService) Scale(gvr schema.GroupVersionResource, namespace, name
 	}
 	spec["replicas"] = replicas
 	u.Object["spec"] = spec
-	_, err = iface.Update(context.TODO(), u, metav1.UpdateOptions{})
-	return err
+	_, err = iface.Update(ctx, u, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
+	return warning, err
 }
 
 // Restart 通用滚动重启（向 spec.template.metadata.annotations 注入 restartedAt 触发滚动更新）
-func (s *ResourceService) Restart(gvr schema.GroupVersionResource, namespace, name string) error {
-	patchData := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, metav1.Now().Format("2006-01-02T15:04:05Z"))
-	_, err := s.Patch(gvr, namespace, name, types.MergePatchType, []byte(patchData))
+func (s *ResourceService) Restart(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) error {
+	_, err := s.Patch(ctx, gvr, namespace, name, types.MergePatchType, restartPatch(), false)
 	return err
 }
+
+// dryRunOption dryRun 时返回 DryRun=All：服务端完整执行准入、校验与默认值填充，但不写入存储
+func dryRunOption(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/pmezard/go-difflib/difflib"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// revisionAnnotation Deployment/ReplicaSet 上记录版本号的注解（由 deployment controller 维护）
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+	// changeCauseAnnotation 变更原因注解（kubectl --record / kubectl annotate）
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// rollbackSkippedAnnotations 回滚时不从 ReplicaSet 复制到 Deployment 的注解，与 kubectl rollout undo 一致
+var rollbackSkippedAnnotations = map[string]bool{
+	corev1.LastAppliedConfigAnnotation:          true,
+	revisionAnnotation:                          true,
+	"deployment.kubernetes.io/revision-history": true,
+	"deployment.kubernetes.io/desired-replicas": true,
+	"deployment.kubernetes.io/max-replicas":     true,
+	appsv1.DeprecatedRollbackTo:                 true,
+}
+
+// revisionOf 读取对象上的版本号，无注解时为 0
+func revisionOf(obj metav1.Object) (int64, error) {
+	v := obj.GetAnnotations()[revisionAnnotation]
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// ownedReplicaSets 实时列出 Deployment 控制的全部 ReplicaSet（不走缓存，保证版本信息最新）
+func (s *DeploymentService) ownedReplicaSets(ctx context.Context, deploy *appsv1.Deployment) ([]*appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.k8sClient.ClientSet.AppsV1().ReplicaSets(deploy.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	var owned []*appsv1.ReplicaSet
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], deploy) {
+			owned = append(owned, &list.Items[i])
+		}
+	}
+	return owned, nil
+}
+
+// revisionReplicaSets 按版本号索引 ReplicaSet，并返回降序排列的版本号
+func revisionReplicaSets(rsList []*appsv1.ReplicaSet) (map[int64]*appsv1.ReplicaSet, []int64) {
+	byRevision := make(map[int64]*appsv1.ReplicaSet, len(rsList))
+	var revisions []int64
+	for _, rs := range rsList {
+		rev, err := revisionOf(rs)
+		if err != nil || rev == 0 {
+			continue
+		}
+		if _, exists := byRevision[rev]; !exists {
+			revisions = append(revisions, rev)
+		}
+		byRevision[rev] = rs
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] > revisions[j] })
+	return byRevision, revisions
+}
+
+// templateWithoutHash 去掉 pod-template-hash 标签后的模板副本（ReplicaSet 模板比 Deployment 多此标签）
+func templateWithoutHash(t *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+	out := t.DeepCopy()
+	delete(out.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	return out
+}
+
+// ListRevisions 列出 Deployment 的历史版本（按版本号降序）
+func (s *DeploymentService) ListRevisions(ctx context.Context, namespace, name string) ([]model.DeploymentRevision, error) {
+	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rsList, err := s.ownedReplicaSets(ctx, deploy)
+	if err != nil {
+		return nil, err
+	}
+	current, _ := revisionOf(deploy)
+	byRevision, revisions := revisionReplicaSets(rsList)
+
+	result := make([]model.DeploymentRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		rs := byRevision[rev]
+		replicas := int32(0)
+		if rs.Spec.Replicas != nil {
+			replicas = *rs.Spec.Replicas
+		}
+		result = append(result, model.DeploymentRevision{
+			Revision:          rev,
+			ReplicaSet:        rs.Name,
+			ChangeCause:       rs.Annotations[changeCauseAnnotation],
+			Images:            podSpecImages(&rs.Spec.Template.Spec),
+			Replicas:          replicas,
+			CreationTimestamp: rs.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			Current:           rev == current,
+		})
+	}
+	return result, nil
+}
+
+// DiffRevisions 比较两个版本的 Pod 模板。to 为 0 时取当前版本，from 为 0 时取 to 的上一个版本
+func (s *DeploymentService) DiffRevisions(ctx context.Context, namespace, name string, from, to int64) (*model.RevisionDiff, error) {
+	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rsList, err := s.ownedReplicaSets(ctx, deploy)
+	if err != nil {
+		return nil, err
+	}
+	byRevision, revisions := revisionReplicaSets(rsList)
+
+	if to == 0 {
+		if to, err = revisionOf(deploy); err != nil {
+			return nil, err
+		}
+	}
+	if from == 0 {
+		for _, rev := range revisions {
+			if rev < to {
+				from = rev
+				break
+			}
+		}
+		if from == 0 {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("版本 %d 之前没有历史版本", to))
+		}
+	}
+	fromRS, ok := byRevision[from]
+	if !ok {
+		return nil, revisionNotFound(from)
+	}
+	toRS, ok := byRevision[to]
+	if !ok {
+		return nil, revisionNotFound(to)
+	}
+
+	diff, err := templateDiff(&fromRS.Spec.Template, &toRS.Spec.Template, fmt.Sprintf("revision %d", from), fmt.Sprintf("revision %d", to))
+	if err != nil {
+		return nil, err
+	}
+	return &model.RevisionDiff{From: from, To: to, Diff: diff}, nil
+}
+
+// revisionNotFound 版本不存在，与 apiserver 的 NotFound 同类，便于接口层映射为 404
+func revisionNotFound(revision int64) error {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusNotFound,
+		Reason:  metav1.StatusReasonNotFound,
+		Message: fmt.Sprintf("版本 %d 不存在", revision),
+	}}
+}
+
+// templateDiff 以 YAML 形式输出两个 Pod 模板的 unified diff
+func templateDiff(a, b *corev1.PodTemplateSpec, aName, bName string) (string, error) {
+	aYAML, err := yaml.Marshal(templateWithoutHash(a))
+	if err != nil {
+		return "", err
+	}
+	bYAML, err := yaml.Marshal(templateWithoutHash(b))
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(aYAML)),
+		B:        difflib.SplitLines(string(bYAML)),
+		FromFile: aName,
+		ToFile:   bName,
+		Context:  3,
+	})
+}
+
+// RollbackDeployment 回滚到指定版本，语义同 kubectl rollout undo：
+// toRevision 为 0 时回滚到上一版本；暂停中的 Deployment 不允许回滚；
+// 目标模板与当前一致时不做变更；回滚以 JSON Patch 替换模板与注解，并以 resourceVersion 防并发覆盖。
+func (s *DeploymentService) RollbackDeployment(ctx context.Context, namespace, name string, toRevision int64) (*model.RollbackResult, error) {
+	deploy, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if deploy.Spec.Paused {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("%s 处于暂停状态，请先恢复再回滚", name))
+	}
+	current, err := revisionOf(deploy)
+	if err != nil {
+		return nil, err
+	}
+
+	rsList, err := s.ownedReplicaSets(ctx, deploy)
+	if err != nil {
+		return nil, err
+	}
+	byRevision, revisions := revisionReplicaSets(rsList)
+	if toRevision == 0 {
+		// 上一版本：版本号第二大的 ReplicaSet
+		if len(revisions) < 2 {
+			return nil, apierrors.NewBadRequest("没有可回滚的历史版本")
+		}
+		toRevision = revisions[1]
+	}
+	rs, ok := byRevision[toRevision]
+	if !ok {
+		return nil, revisionNotFound(toRevision)
+	}
+
+	result := &model.RollbackResult{FromRevision: current, ToRevision: toRevision}
+	target := templateWithoutHash(&rs.Spec.Template)
+	if equality.Semantic.DeepEqual(target, templateWithoutHash(&deploy.Spec.Template)) {
+		result.Skipped = true
+		result.Message = fmt.Sprintf("当前模板已与版本 %d 一致，未做变更", toRevision)
+		return result, nil
+	}
+
+	// 保留 Deployment 自身的控制类注解，其余注解取自目标 ReplicaSet
+	annotations := map[string]string{}
+	for k := range rollbackSkippedAnnotations {
+		if v, ok := deploy.Annotations[k]; ok {
+			annotations[k] = v
+		}
+	}
+	for k, v := range rs.Annotations {
+		if !rollbackSkippedAnnotations[k] {
+			annotations[k] = v
+		}
+	}
+
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": deploy.ResourceVersion},
+		{"op": "replace", "path": "/spec/template", "value": target},
+		{"op": "replace", "path": "/metadata/annotations", "value": annotations},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return nil, err
+	}
+	result.Message = fmt.Sprintf("已从版本 %d 回滚到版本 %d", current, toRevision)
+	return result, nil
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// workloadKind 校验 GVR 为 apps 组下支持 rollout 的工作负载，返回其 resource 名
+func workloadKind(gvr schema.GroupVersionResource) (string, error) {
+	if gvr.Group == "apps" {
+		if _, ok := WorkloadKinds[gvr.Resource]; ok {
+			return gvr.Resource, nil
+		}
+	}
+	return "", fmt.Errorf("仅支持 apps 组的 deployments、statefulsets、daemonsets")
+}
+
+// SetPaused 暂停/恢复 Deployment 的 rollout（同 kubectl rollout pause/resume，重复操作不报错）。
+// 暂停期间的模板修改会累积，恢复后一次性滚动。
+func (s *ResourceService) SetPaused(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, paused bool) error {
+	kind, err := workloadKind(gvr)
+	if err != nil {
+		return err
+	}
+	if kind != "deployments" {
+		return fmt.Errorf("仅 Deployment 支持暂停/恢复 rollout")
+	}
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	_, err = s.k8sClient.ClientSet.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// GetRolloutStrategy 读取工作负载的滚动更新参数
+func (s *ResourceService) GetRolloutStrategy(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*model.RolloutStrategy, error) {
+	kind, err := workloadKind(gvr)
+	if err != nil {
+		return nil, err
+	}
+	apps := s.k8sClient.ClientSet.AppsV1()
+	switch kind {
+	case "deployments":
+		d, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return deploymentStrategy(d), nil
+	case "statefulsets":
+		sts, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return statefulSetStrategy(sts), nil
+	default:
+		ds, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return daemonSetStrategy(ds), nil
+	}
+}
+
+// UpdateRolloutStrategy 修改滚动更新参数：合并到当前配置后先整体校验，通过后仅 patch 策略相关字段
+func (s *ResourceService) UpdateRolloutStrategy(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string, req model.RolloutStrategy) (*model.RolloutStrategy, error) {
+	kind, err := workloadKind(gvr)
+	if err != nil {
+		return nil, err
+	}
+	apps := s.k8sClient.ClientSet.AppsV1()
+
+	var patch map[string]interface{}
+	switch kind {
+	case "deployments":
+		d, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if patch, err = deploymentStrategyPatch(d, req); err != nil {
+			return nil, err
+		}
+	case "statefulsets":
+		sts, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if patch, err = statefulSetStrategyPatch(sts, req); err != nil {
+			return nil, err
+		}
+	default:
+		ds, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if patch, err = daemonSetStrategyPatch(ds, req); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Patch(ctx, gvr, namespace, name, types.MergePatchType, data, false); err != nil {
+		return nil, err
+	}
+	return s.GetRolloutStrategy(ctx, gvr, namespace, name)
+}
+
+// deploymentStrategy 提取 Deployment 的滚动更新参数
+func deploymentStrategy(d *appsv1.Deployment) *model.RolloutStrategy {
+	st := &model.RolloutStrategy{
+		Kind:                    "Deployment",
+		Type:                    string(d.Spec.Strategy.Type),
+		MinReadySeconds:         &d.Spec.MinReadySeconds,
+		ProgressDeadlineSeconds: d.Spec.ProgressDeadlineSeconds,
+		Paused:                  d.Spec.Paused,
+	}
+	if ru := d.Spec.Strategy.RollingUpdate; ru != nil {
+		st.MaxSurge, st.MaxUnavailable = ru.MaxSurge, ru.MaxUnavailable
+	}
+	return st
+}
+
+// statefulSetStrategy 提取 StatefulSet 的滚动更新参数
+func statefulSetStrategy(sts *appsv1.StatefulSet) *model.RolloutStrategy {
+	st := &model.RolloutStrategy{
+		Kind:            "StatefulSet",
+		Type:            string(sts.Spec.UpdateStrategy.Type),
+		MinReadySeconds: &sts.Spec.MinReadySeconds,
+	}
+	if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil {
+		st.Partition, st.MaxUnavailable = ru.Partition, ru.MaxUnavailable
+	}
+	return st
+}
+
+// daemonSetStrategy 提取 DaemonSet 的滚动更新参数
+func daemonSetStrategy(ds *appsv1.DaemonSet) *model.RolloutStrategy {
+	st := &model.RolloutStrategy{
+		Kind:            "DaemonSet",
+		Type:            string(ds.Spec.UpdateStrategy.Type),
+		MinReadySeconds: &ds.Spec.MinReadySeconds,
+	}
+	if ru := ds.Spec.UpdateStrategy.RollingUpdate; ru != nil {
+		st.MaxSurge, st.MaxUnavailable = ru.MaxSurge, ru.MaxUnavailable
+	}
+	return st
+}
+
+// deploymentStrategyPatch 合并请求并校验，生成 Deployment 的 merge patch
+func deploymentStrategyPatch(d *appsv1.Deployment, req model.RolloutStrategy) (map[string]interface{}, error) {
+	if req.Partition != nil {
+		return nil, fmt.Errorf("partition 仅适用于 StatefulSet")
+	}
+	strategy := *d.Spec.Strategy.DeepCopy()
+	if req.Type != "" {
+		strategy.Type = appsv1.DeploymentStrategyType(req.Type)
+	}
+	switch strategy.Type {
+	case appsv1.RecreateDeploymentStrategyType:
+		if req.MaxSurge != nil || req.MaxUnavailable != nil {
+			return nil, fmt.Errorf("Recreate 策略不支持 max_surge/max_unavailable")
+		}
+		strategy.RollingUpdate = nil
+	case appsv1.RollingUpdateDeploymentStrategyType:
+		ru := &appsv1.RollingUpdateDeployment{}
+		if strategy.RollingUpdate != nil {
+			ru = strategy.RollingUpdate.DeepCopy()
+		}
+		if req.MaxSurge != nil {
+			ru.MaxSurge = req.MaxSurge
+		}
+		if req.MaxUnavailable != nil {
+			ru.MaxUnavailable = req.MaxUnavailable
+		}
+		if err := validateSurgeUnavailable(ru.MaxSurge, ru.MaxUnavailable, false); err != nil {
+			return nil, err
+		}
+		strategy.RollingUpdate = ru
+	default:
+		return nil, fmt.Errorf("Deployment 的 type 仅支持 RollingUpdate、Recreate")
+	}
+
+	minReady := d.Spec.MinReadySeconds
+	if req.MinReadySeconds != nil {
+		minReady = *req.MinReadySeconds
+	}
+	deadline := d.Spec.ProgressDeadlineSeconds
+	if req.ProgressDeadlineSeconds != nil {
+		deadline = req.ProgressDeadlineSeconds
+	}
+	if minReady < 0 {
+		return nil, fmt.Errorf("min_ready_seconds 不能为负数")
+	}
+	if deadline != nil && *deadline <= minReady {
+		return nil, fmt.Errorf("progress_deadline_seconds 必须大于 min_ready_seconds")
+	}
+
+	spec := map[string]interface{}{
+		"strategy":        strategyPatch(string(strategy.Type), strategy.RollingUpdate),
+		"minReadySeconds": minReady,
+	}
+	if deadline != nil {
+		spec["progressDeadlineSeconds"] = *deadline
+	}
+	return map[string]interface{}{"spec": spec}, nil
+}
+
+// statefulSetStrategyPatch 合并请求并校验，生成 StatefulSet 的 merge patch
+func statefulSetStrategyPatch(sts *appsv1.StatefulSet, req model.RolloutStrategy) (map[string]interface{}, error) {
+	if req.MaxSurge != nil {
+		return nil, fmt.Errorf("StatefulSet 不支持 max_surge")
+	}
+	if req.ProgressDeadlineSeconds != nil {
+		return nil, fmt.Errorf("progress_deadline_seconds 仅适用于 Deployment")
+	}
+	strategy := *sts.Spec.UpdateStrategy.DeepCopy()
+	if req.Type != "" {
+		strategy.Type = appsv1.StatefulSetUpdateStrategyType(req.Type)
+	}
+	switch strategy.Type {
+	case appsv1.OnDeleteStatefulSetStrategyType:
+		if req.Partition != nil || req.MaxUnavailable != nil {
+			return nil, fmt.Errorf("OnDelete 策略不支持 partition/max_unavailable")
+		}
+		strategy.RollingUpdate = nil
+	case appsv1.RollingUpdateStatefulSetStrategyType:
+		ru := &appsv1.RollingUpdateStatefulSetStrategy{}
+		if strategy.RollingUpdate != nil {
+			ru = strategy.RollingUpdate.DeepCopy()
+		}
+		if req.Partition != nil {
+			if *req.Partition < 0 {
+				return nil, fmt.Errorf("partition 不能为负数")
+			}
+			ru.Partition = req.Partition
+		}
+		if req.MaxUnavailable != nil {
+			// StatefulSet 的 maxUnavailable 必须至少为 1 或大于 0%
+			if err := validateIntOrPercent("max_unavailable", req.MaxUnavailable, true); err != nil {
+				return nil, err
+			}
+			if v, _ := intOrPercentValue(req.MaxUnavailable); v == 0 {
+				return nil, fmt.Errorf("max_unavailable 必须大于 0")
+			}
+			ru.MaxUnavailable = req.MaxUnavailable
+		}
+		strategy.RollingUpdate = ru
+	default:
+		return nil, fmt.Errorf("StatefulSet 的 type 仅支持 RollingUpdate、OnDelete")
+	}
+
+	minReady := sts.Spec.MinReadySeconds
+	if req.MinReadySeconds != nil {
+		minReady = *req.MinReadySeconds
+	}
+	if minReady < 0 {
+		return nil, fmt.Errorf("min_ready_seconds 不能为负数")
+	}
+
+	var ru interface{}
+	if r := strategy.RollingUpdate; r != nil {
+		m := map[string]interface{}{}
+		if r.Partition != nil {
+			m["partition"] = *r.Partition
+		}
+		if r.MaxUnavailable != nil {
+			m["maxUnavailable"] = r.MaxUnavailable
+		}
+		ru = m
+	}
+	return map[string]interface{}{"spec": map[string]interface{}{
+		"updateStrategy":  map[string]interface{}{"type": string(strategy.Type), "rollingUpdate": ru},
+		"minReadySeconds": minReady,
+	}}, nil
+}
+
+// daemonSetStrategyPatch 合并请求并校验，生成 DaemonSet 的 merge patch
+func daemonSetStrategyPatch(ds *appsv1.DaemonSet, req model.RolloutStrategy) (map[string]interface{}, error) {
+	if req.Partition != nil {
+		return nil, fmt.Errorf("partition 仅适用于 StatefulSet")
+	}
+	if req.ProgressDeadlineSeconds != nil {
+		return nil, fmt.Errorf("progress_deadline_seconds 仅适用于 Deployment")
+	}
+	strategy := *ds.Spec.UpdateStrategy.DeepCopy()
+	if req.Type != "" {
+		strategy.Type = appsv1.DaemonSetUpdateStrategyType(req.Type)
+	}
+	var ru *appsv1.RollingUpdateDaemonSet
+	switch strategy.Type {
+	case appsv1.OnDeleteDaemonSetStrategyType:
+		if req.MaxSurge != nil || req.MaxUnavailable != nil {
+			return nil, fmt.Errorf("OnDelete 策略不支持 max_surge/max_unavailable")
+		}
+	case appsv1.RollingUpdateDaemonSetStrategyType:
+		ru = &appsv1.RollingUpdateDaemonSet{}
+		if strategy.RollingUpdate != nil {
+			ru = strategy.RollingUpdate.DeepCopy()
+		}
+		if req.MaxSurge != nil {
+			ru.MaxSurge = req.MaxSurge
+		}
+		if req.MaxUnavailable != nil {
+			ru.MaxUnavailable = req.MaxUnavailable
+		}
+		if err := validateSurgeUnavailable(ru.MaxSurge, ru.MaxUnavailable, true); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("DaemonSet 的 type 仅支持 RollingUpdate、OnDelete")
+	}
+
+	minReady := ds.Spec.MinReadySeconds
+	if req.MinReadySeconds != nil {
+		minReady = *req.MinReadySeconds
+	}
+	if minReady < 0 {
+		return nil, fmt.Errorf("min_ready_seconds 不能为负数")
+	}
+
+	var ruPatch *appsv1.RollingUpdateDeployment
+	if ru != nil {
+		ruPatch = &appsv1.RollingUpdateDeployment{MaxSurge: ru.MaxSurge, MaxUnavailable: ru.MaxUnavailable}
+	}
+	return map[string]interface{}{"spec": map[string]interface{}{
+		"updateStrategy":  strategyPatch(string(strategy.Type), ruPatch),
+		"minReadySeconds": minReady,
+	}}, nil
+}
+
+// strategyPatch 生成 strategy/updateStrategy 的 patch 片段；rollingUpdate 为 nil 时显式置空（非滚动策略不允许保留该字段）
+func strategyPatch(strategyType string, ru *appsv1.RollingUpdateDeployment) map[string]interface{} {
+	var rollingUpdate interface{}
+	if ru != nil {
+		m := map[string]interface{}{}
+		if ru.MaxSurge != nil {
+			m["maxSurge"] = ru.MaxSurge
+		}
+		if ru.MaxUnavailable != nil {
+			m["maxUnavailable"] = ru.MaxUnavailable
+		}
+		rollingUpdate = m
+	}
+	return map[string]interface{}{"type": strategyType, "rollingUpdate": rollingUpdate}
+}
+
+// validateSurgeUnavailable 校验 maxSurge/maxUnavailable：非负，maxUnavailable 不超过 100%
+// （DaemonSet 的 maxSurge 同样不超过 100%），且二者不能同时为 0
+func validateSurgeUnavailable(maxSurge, maxUnavailable *intstr.IntOrString, surgeCapped bool) error {
+	if err := validateIntOrPercent("max_surge", maxSurge, surgeCapped); err != nil {
+		return err
+	}
+	if err := validateIntOrPercent("max_unavailable", maxUnavailable, true); err != nil {
+		return err
+	}
+	surge, _ := intOrPercentValue(maxSurge)
+	unavailable, _ := intOrPercentValue(maxUnavailable)
+	if maxSurge != nil && maxUnavailable != nil && surge == 0 && unavailable == 0 {
+		return fmt.Errorf("max_surge 与 max_unavailable 不能同时为 0")
+	}
+	return nil
+}
+
+// validateIntOrPercent 校验非负整数或 "N%" 形式的百分比，capped 时百分比不超过 100%
+func validateIntOrPercent(field string, v *intstr.IntOrString, capped bool) error {
+	if v == nil {
+		return nil
+	}
+	n, isPercent := intOrPercentValue(v)
+	if n < 0 {
+		return fmt.Errorf("%s 必须为非负整数或百分比（如 25%%），当前为 %s", field, v.String())
+	}
+	if isPercent && capped && n > 100 {
+		return fmt.Errorf("%s 不能超过 100%%", field)
+	}
+	return nil
+}
+
+// intOrPercentValue 解析数值，百分比返回去掉 % 的数值；格式错误返回 -1
+func intOrPercentValue(v *intstr.IntOrString) (int, bool) {
+	if v == nil {
+		return 0, false
+	}
+	if v.Type == intstr.Int {
+		return v.IntValue(), false
+	}
+	s, ok := strings.CutSuffix(v.StrVal, "%")
+	if !ok {
+		return -1, false
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return -1, true
+	}
+	return n, true
+}
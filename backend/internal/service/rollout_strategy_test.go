@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func intOrStr(v intstr.IntOrString) *intstr.IntOrString { return &v }
+
+func int32Ptr(v int32) *int32 { return &v }
+
+// TestDeploymentStrategyPatch Deployment 策略参数与当前配置合并后校验，Recreate 清除 rollingUpdate
+func TestDeploymentStrategyPatch(t *testing.T) {
+	d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       intOrStr(intstr.FromString("25%")),
+				MaxUnavailable: intOrStr(intstr.FromString("25%")),
+			},
+		},
+		ProgressDeadlineSeconds: int32Ptr(600),
+	}}
+
+	cases := []struct {
+		name string
+		req  model.RolloutStrategy
+		ok   bool
+	}{
+		{name: "surge only", req: model.RolloutStrategy{MaxSurge: intOrStr(intstr.FromInt32(2))}, ok: true},
+		{name: "both zero", req: model.RolloutStrategy{MaxSurge: intOrStr(intstr.FromInt32(0)), MaxUnavailable: intOrStr(intstr.FromString("0%"))}},
+		{name: "unavailable over 100%", req: model.RolloutStrategy{MaxUnavailable: intOrStr(intstr.FromString("150%"))}},
+		{name: "bad percent", req: model.RolloutStrategy{MaxSurge: intOrStr(intstr.FromString("abc"))}},
+		{name: "deadline not above min ready", req: model.RolloutStrategy{MinReadySeconds: int32Ptr(600)}},
+		{name: "recreate with surge", req: model.RolloutStrategy{Type: "Recreate", MaxSurge: intOrStr(intstr.FromInt32(1))}},
+		{name: "recreate", req: model.RolloutStrategy{Type: "Recreate"}, ok: true},
+		{name: "partition", req: model.RolloutStrategy{Partition: int32Ptr(1)}},
+	}
+	for _, tc := range cases {
+		patch, err := deploymentStrategyPatch(d, tc.req)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tc.name, err, tc.ok)
+			continue
+		}
+		if tc.name == "recreate" {
+			strategy := patch["spec"].(map[string]interface{})["strategy"].(map[string]interface{})
+			if strategy["rollingUpdate"] != nil {
+				t.Errorf("recreate should clear rollingUpdate, got %v", strategy["rollingUpdate"])
+			}
+		}
+	}
+}
+
+// TestStatefulSetStrategyPatch StatefulSet 校验 partition 与 maxUnavailable，拒绝 maxSurge
+func TestStatefulSetStrategyPatch(t *testing.T) {
+	sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{
+		UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+	}}
+	if _, err := statefulSetStrategyPatch(sts, model.RolloutStrategy{Partition: int32Ptr(2)}); err != nil {
+		t.Errorf("partition: %v", err)
+	}
+	if _, err := statefulSetStrategyPatch(sts, model.RolloutStrategy{Partition: int32Ptr(-1)}); err == nil {
+		t.Error("negative partition should fail")
+	}
+	if _, err := statefulSetStrategyPatch(sts, model.RolloutStrategy{MaxUnavailable: intOrStr(intstr.FromInt32(0))}); err == nil {
+		t.Error("zero maxUnavailable should fail")
+	}
+	if _, err := statefulSetStrategyPatch(sts, model.RolloutStrategy{MaxSurge: intOrStr(intstr.FromInt32(1))}); err == nil {
+		t.Error("maxSurge should be rejected for StatefulSet")
+	}
+}
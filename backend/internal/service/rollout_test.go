@@ -0,0 +1,61 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testReplicaSet(name, revision, image, hash string) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}}}
+	if revision != "" {
+		rs.Annotations[revisionAnnotation] = revision
+	}
+	rs.Spec.Template.Labels = map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: hash}
+	rs.Spec.Template.Spec.Containers = []corev1.Container{{Name: "web", Image: image}}
+	return rs
+}
+
+// TestRevisionReplicaSets 按版本注解索引 ReplicaSet 并按版本号降序排列，无注解的忽略
+func TestRevisionReplicaSets(t *testing.T) {
+	byRevision, revisions := revisionReplicaSets([]*appsv1.ReplicaSet{
+		testReplicaSet("web-a", "2", "nginx:1.25", "a"),
+		testReplicaSet("web-b", "10", "nginx:1.26", "b"),
+		testReplicaSet("web-c", "", "nginx:1.24", "c"), // 无版本注解，忽略
+		testReplicaSet("web-d", "3", "nginx:1.25", "d"),
+	})
+	if want := []int64{10, 3, 2}; !reflect.DeepEqual(revisions, want) {
+		t.Fatalf("revisions = %v, want %v", revisions, want)
+	}
+	if byRevision[10].Name != "web-b" {
+		t.Fatalf("revision 10 = %s, want web-b", byRevision[10].Name)
+	}
+}
+
+// TestTemplateDiffIgnoresHash 模板 diff 忽略 pod-template-hash，仅输出实际变更
+func TestTemplateDiffIgnoresHash(t *testing.T) {
+	a := testReplicaSet("web-a", "1", "nginx:1.25", "a")
+	b := testReplicaSet("web-b", "2", "nginx:1.25", "b")
+	diff, err := templateDiff(&a.Spec.Template, &b.Spec.Template, "revision 1", "revision 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Fatalf("templates differing only in pod-template-hash should have empty diff, got:\n%s", diff)
+	}
+
+	c := testReplicaSet("web-c", "3", "nginx:1.26", "c")
+	diff, err = templateDiff(&a.Spec.Template, &c.Spec.Template, "revision 1", "revision 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"--- revision 1", "+++ revision 3", "-  - image: nginx:1.25", "+  - image: nginx:1.26"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+}
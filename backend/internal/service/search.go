@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// searchMaxHits 单次搜索返回的命中上限
+const searchMaxHits = 500
+
+// DefaultSearchKinds 未指定 kinds 时搜索的资源（均有 informer 缓存）
+var DefaultSearchKinds = []string{"pods", "deployments", "services", "configmaps"}
+
+// SearchQuery 跨集群搜索条件，Name/LabelSelector/Image 至少一项
+type SearchQuery struct {
+	Name          string   // 名称前缀，忽略大小写
+	LabelSelector string   // 标签选择器
+	Image         string   // 镜像子串（仅匹配带容器的资源）
+	Kinds         []string // resource 或 resource.group，如 pods、statefulsets.apps
+	Fresh         bool     // 绕过 informer 缓存
+}
+
+// SearchHit 搜索命中项
+type SearchHit struct {
+	ClusterID   uint     `json:"cluster_id"`
+	ClusterName string   `json:"cluster_name"`
+	Group       string   `json:"group"`
+	Version     string   `json:"version"`
+	Resource    string   `json:"resource"`
+	Kind        string   `json:"kind"`
+	Namespace   string   `json:"namespace"`
+	Name        string   `json:"name"`
+	Images      []string `json:"images,omitempty"`
+}
+
+// SearchClusterError 搜索失败的集群
+type SearchClusterError struct {
+	ClusterID   uint   `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+	Error       string `json:"error"`
+}
+
+// SearchResult 跨集群搜索结果
+type SearchResult struct {
+	Hits      []SearchHit          `json:"hits"`
+	Errors    []SearchClusterError `json:"errors"`
+	Truncated bool                 `json:"truncated"` // 命中数超过上限被截断
+}
+
+// searchObject 待匹配对象：元数据 + 容器镜像（无容器的资源为 nil）
+type searchObject struct {
+	meta   metav1.Object
+	images []string
+}
+
+// searchKind 一种可搜索资源
+type searchKind struct {
+	gvr  schema.GroupVersionResource
+	kind string
+	// list 返回按标签选择器过滤后的对象
+	list func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error)
+}
+
+// cachedSearchKinds 有 informer 缓存的资源，按 GVR 取用，避免逐集群直连 LIST
+var cachedSearchKinds = map[schema.GroupVersionResource]searchKind{}
+
+func init() {
+	register := func(kind searchKind) { cachedSearchKinds[kind.gvr] = kind }
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+		kind: "Pod",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedPods(ctx, client, "", q)
+			return searchObjects(items, func(p *corev1.Pod) []string { return podSpecImages(&p.Spec) }), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		kind: "Deployment",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedDeployments(ctx, client, "", q)
+			return searchObjects(items, func(d *appsv1.Deployment) []string { return podSpecImages(&d.Spec.Template.Spec) }), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"},
+		kind: "ReplicaSet",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedReplicaSets(ctx, client, "", q)
+			return searchObjects(items, func(r *appsv1.ReplicaSet) []string { return podSpecImages(&r.Spec.Template.Spec) }), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		kind: "StatefulSet",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedStatefulSets(ctx, client, "", q)
+			return searchObjects(items, func(s *appsv1.StatefulSet) []string { return podSpecImages(&s.Spec.Template.Spec) }), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"},
+		kind: "DaemonSet",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedDaemonSets(ctx, client, "", q)
+			return searchObjects(items, func(d *appsv1.DaemonSet) []string { return podSpecImages(&d.Spec.Template.Spec) }), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"},
+		kind: "Job",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedJobs(ctx, client, "", q)
+			return searchObjects(items, func(j *batchv1.Job) []string { return podSpecImages(&j.Spec.Template.Spec) }), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"},
+		kind: "CronJob",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedCronJobs(ctx, client, "", q)
+			return searchObjects(items, func(cj *batchv1.CronJob) []string { return podSpecImages(&cj.Spec.JobTemplate.Spec.Template.Spec) }), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Version: "v1", Resource: "services"},
+		kind: "Service",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedServices(ctx, client, "", q)
+			return searchObjects[*corev1.Service](items, nil), err
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		kind: "ConfigMap",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedConfigMaps(ctx, client, "", q)
+			return searchObjects[*corev1.ConfigMap](items, nil), err
+		},
+	})
+	// Secret 只按元数据实时 LIST：名称与标签即可匹配，不拉取 Secret 内容
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	register(searchKind{
+		gvr:  secretsGVR,
+		kind: "Secret",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			list, err := client.MetadataClient.Resource(secretsGVR).List(ctx, metav1.ListOptions{
+				LabelSelector:   q.LabelSelector,
+				ResourceVersion: "0",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return searchObjects(itemPointers(list.Items), nil), nil
+		},
+	})
+	register(searchKind{
+		gvr:  schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+		kind: "Namespace",
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			items, _, err := cachedNamespaces(ctx, client, q)
+			return searchObjects[*corev1.Namespace](items, nil), err
+		},
+	})
+}
+
+// searchObjects 将类型化对象转为待匹配对象
+func searchObjects[T metav1.Object](items []T, images func(T) []string) []searchObject {
+	out := make([]searchObject, 0, len(items))
+	for _, item := range items {
+		obj := searchObject{meta: item}
+		if images != nil {
+			obj.images = images(item)
+		}
+		out = append(out, obj)
+	}
+	return out
+}
+
+// podSpecImages 收集 PodSpec 中（含 init）容器的镜像
+func podSpecImages(spec *corev1.PodSpec) []string {
+	images := make([]string, 0, len(spec.InitContainers)+len(spec.Containers))
+	for _, c := range spec.InitContainers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// unstructuredPodSpecPaths 常见工作负载中 PodSpec 的位置
+var unstructuredPodSpecPaths = [][]string{
+	{"spec"},                     // Pod
+	{"spec", "template", "spec"}, // Deployment/StatefulSet/DaemonSet/Job 等
+	{"spec", "jobTemplate", "spec", "template", "spec"}, // CronJob
+}
+
+// unstructuredImages 从任意资源中按常见路径提取容器镜像，非工作负载返回 nil
+func unstructuredImages(obj *unstructured.Unstructured) []string {
+	var images []string
+	for _, path := range unstructuredPodSpecPaths {
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, found, _ := unstructured.NestedSlice(obj.Object, append(append([]string{}, path...), field)...)
+			if !found {
+				continue
+			}
+			for _, c := range containers {
+				if m, ok := c.(map[string]interface{}); ok {
+					if image, ok := m["image"].(string); ok {
+						images = append(images, image)
+					}
+				}
+			}
+		}
+		if images != nil {
+			return images
+		}
+	}
+	return nil
+}
+
+// resolveSearchKind 将 resource[.group] 解析为本集群的 GVR。
+// 有缓存的资源直接读缓存；其余经 RESTMapper 解析后以 dynamic client 列表（apiserver 缓存应答）。
+func resolveSearchKind(client *k8s.Client, arg string) (searchKind, error) {
+	gr := schema.ParseGroupResource(strings.ToLower(strings.TrimSpace(arg)))
+	gvr, err := client.RESTMapper().ResourceFor(gr.WithVersion(""))
+	if err != nil {
+		return searchKind{}, err
+	}
+	if kind, ok := cachedSearchKinds[gvr]; ok {
+		return kind, nil
+	}
+	gvk, err := client.RESTMapper().KindFor(gvr)
+	if err != nil {
+		return searchKind{}, err
+	}
+	return searchKind{
+		gvr:  gvr,
+		kind: gvk.Kind,
+		list: func(ctx context.Context, client *k8s.Client, q model.ListQuery) ([]searchObject, error) {
+			list, err := client.DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{
+				LabelSelector:   q.LabelSelector,
+				ResourceVersion: "0",
+			})
+			if err != nil {
+				return nil, err
+			}
+			out := make([]searchObject, 0, len(list.Items))
+			for i := range list.Items {
+				item := &list.Items[i]
+				out = append(out, searchObject{meta: item, images: unstructuredImages(item)})
+			}
+			return out, nil
+		},
+	}, nil
+}
+
+// matchSearch 判断对象是否满足名称前缀与镜像条件（标签已在列表时过滤）
+func matchSearch(obj searchObject, name, image string) ([]string, bool) {
+	if name != "" && !strings.HasPrefix(strings.ToLower(obj.meta.GetName()), name) {
+		return nil, false
+	}
+	if image == "" {
+		return obj.images, true
+	}
+	var matched []string
+	for _, img := range obj.images {
+		if strings.Contains(strings.ToLower(img), image) {
+			matched = append(matched, img)
+		}
+	}
+	return matched, len(matched) > 0
+}
+
+// Search 在所有集群的全部命名空间中按名称前缀、标签选择器或镜像查找资源。
+// 各集群并发且独立超时；集群不可达或资源类型不存在时记入 Errors，不影响其余结果。
+func (s *FleetService) Search(ctx context.Context, sq SearchQuery) (*SearchResult, error) {
+	if sq.Name == "" && sq.LabelSelector == "" && sq.Image == "" {
+		return nil, fmt.Errorf("q、label_selector、image 至少需要一项")
+	}
+	if sq.LabelSelector != "" {
+		if _, err := labels.Parse(sq.LabelSelector); err != nil {
+			return nil, fmt.Errorf("标签选择器无效: %w", err)
+		}
+	}
+	kinds := sq.Kinds
+	if len(kinds) == 0 {
+		kinds = DefaultSearchKinds
+	}
+	name, image := strings.ToLower(sq.Name), strings.ToLower(sq.Image)
+	q := model.ListQuery{LabelSelector: sq.LabelSelector, Fresh: sq.Fresh}
+
+	targets, err := s.targets()
+	if err != nil {
+		return nil, err
+	}
+	hits := make([][]SearchHit, len(targets))
+	errs := make([][]SearchClusterError, len(targets))
+	forEachTarget(targets, func(i int, t fleetTarget) {
+		clusterErr := func(err error) SearchClusterError {
+			return SearchClusterError{ClusterID: t.id, ClusterName: t.name, Error: err.Error()}
+		}
+		r, err := withClusterTimeout(ctx, func(ctx context.Context) (*SearchResult, error) {
+			client, err := t.get()
+			if err != nil {
+				return nil, err
+			}
+			r := &SearchResult{}
+			for _, arg := range kinds {
+				kind, err := resolveSearchKind(client, arg)
+				if err != nil {
+					r.Errors = append(r.Errors, clusterErr(fmt.Errorf("%s: %w", arg, err)))
+					continue
+				}
+				objects, err := kind.list(ctx, client, q)
+				if err != nil {
+					r.Errors = append(r.Errors, clusterErr(fmt.Errorf("%s: %w", arg, err)))
+					continue
+				}
+				for _, obj := range objects {
+					images, ok := matchSearch(obj, name, image)
+					if !ok {
+						continue
+					}
+					r.Hits = append(r.Hits, SearchHit{
+						ClusterID:   t.id,
+						ClusterName: t.name,
+						Group:       kind.gvr.Group,
+						Version:     kind.gvr.Version,
+						Resource:    kind.gvr.Resource,
+						Kind:        kind.kind,
+						Namespace:   obj.meta.GetNamespace(),
+						Name:        obj.meta.GetName(),
+						Images:      images,
+					})
+				}
+			}
+			return r, nil
+		})
+		if err != nil {
+			errs[i] = []SearchClusterError{clusterErr(err)}
+			return
+		}
+		hits[i], errs[i] = r.Hits, r.Errors
+	})
+
+	result := &SearchResult{Hits: []SearchHit{}, Errors: []SearchClusterError{}}
+	for i := range targets {
+		result.Hits = append(result.Hits, hits[i]...)
+		result.Errors = append(result.Errors, errs[i]...)
+	}
+	sort.SliceStable(result.Hits, func(i, j int) bool {
+		a, b := result.Hits[i], result.Hits[j]
+		if a.ClusterName != b.ClusterName {
+			return a.ClusterName < b.ClusterName
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	if len(result.Hits) > searchMaxHits {
+		result.Hits = result.Hits[:searchMaxHits]
+		result.Truncated = true
+	}
+	return result, nil
+}
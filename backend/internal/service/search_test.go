@@ -0,0 +1,59 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestUnstructuredImages 任意工作负载按 Pod 模板路径提取 init 与业务容器镜像，无容器的对象返回 nil
+func TestUnstructuredImages(t *testing.T) {
+	cronJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "CronJob",
+		"spec": map[string]interface{}{
+			"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"initContainers": []interface{}{map[string]interface{}{"name": "init", "image": "busybox:1.36"}},
+				"containers":     []interface{}{map[string]interface{}{"name": "job", "image": "registry/payment-api:1.2"}},
+			}}}},
+		},
+	}}
+	if got, want := unstructuredImages(cronJob), []string{"busybox:1.36", "registry/payment-api:1.2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("images = %v, want %v", got, want)
+	}
+
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "ConfigMap", "data": map[string]interface{}{"a": "b"}}}
+	if got := unstructuredImages(configMap); got != nil {
+		t.Fatalf("images = %v, want nil", got)
+	}
+}
+
+// TestMatchSearch 名称按前缀（忽略大小写）、镜像按子串匹配，返回命中的镜像
+func TestMatchSearch(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "Payment-API-7d9f"}}
+	obj := searchObject{meta: pod, images: []string{"istio/proxyv2:1.20", "registry/payment-api:1.2"}}
+
+	cases := []struct {
+		name, image string
+		ok          bool
+		images      []string
+	}{
+		{name: "payment", ok: true, images: obj.images},
+		{name: "api", ok: false},
+		{image: "payment-api", ok: true, images: []string{"registry/payment-api:1.2"}},
+		{name: "payment", image: "nginx", ok: false},
+	}
+	for _, tc := range cases {
+		images, ok := matchSearch(obj, tc.name, tc.image)
+		if ok != tc.ok || (ok && !reflect.DeepEqual(images, tc.images)) {
+			t.Errorf("matchSearch(%q, %q) = %v, %v; want %v, %v", tc.name, tc.image, images, ok, tc.images, tc.ok)
+		}
+	}
+
+	// 无容器的资源不参与镜像匹配
+	if _, ok := matchSearch(searchObject{meta: pod}, "", "payment"); ok {
+		t.Error("object without containers should not match image")
+	}
+}
@@ -20,24 +20,21 @@ func NewSecretService(k8sClient *k8s.Client) *SecretService {
 	return &SecretService{k8sClient: k8sClient}
 }
 
-// ListSecrets 获取Secret列表
-func (s *SecretService) ListSecrets(namespace string) ([]model.SecretInfo, error) {
-	secretList, err := s.k8sClient.ClientSet.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+// ListSecrets 获取Secret列表（支持选择器、名称搜索、排序与分页；始终实时读取，不经 informer 缓存）
+func (s *SecretService) ListSecrets(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := listSecrets(ctx, s.k8sClient, namespace, q)
 	if err != nil {
 		return nil, err
 	}
 
-	var secrets []model.SecretInfo
-	for _, secret := range secretList.Items {
-		secrets = append(secrets, s.convertSecret(&secret, false))
-	}
-
-	return secrets, nil
+	return pageList(list, listMeta, q, func(secret *corev1.Secret) model.SecretInfo {
+		return s.convertSecret(secret, false)
+	}), nil
 }
 
 // GetSecret 获取Secret详情
-func (s *SecretService) GetSecret(namespace, name string, decode bool) (*model.SecretInfo, error) {
-	secret, err := s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+func (s *SecretService) GetSecret(ctx context.Context, namespace, name string, decode bool) (*model.SecretInfo, error) {
+	secret, err := s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +44,7 @@ func (s *SecretService) GetSecret(namespace, name string, decode bool) (*model.S
 }
 
 // CreateSecret 创建Secret
-func (s *SecretService) CreateSecret(namespace, name, secretType string, data map[string]string) error {
+func (s *SecretService) CreateSecret(ctx context.Context, namespace, name, secretType string, data map[string]string) error {
 	// 将字符串数据转换为字节数组
 	byteData := make(map[string][]byte)
 	for k, v := range data {
@@ -63,13 +60,13 @@ func (s *SecretService) CreateSecret(namespace, name, secretType string, data ma
 		Data: byteData,
 	}
 
-	_, err := s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	_, err := s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
 	return err
 }
 
-// UpdateSecret 更新Secret
-func (s *SecretService) UpdateSecret(namespace, name string, data map[string]string) error {
-	secret, err := s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+// UpdateSecret 更新Secret，dryRun 时只做服务端校验不持久化
+func (s *SecretService) UpdateSecret(ctx context.Context, namespace, name string, data map[string]string, dryRun bool) error {
+	secret, err := s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -81,13 +78,13 @@ func (s *SecretService) UpdateSecret(namespace, name string, data map[string]str
 	}
 
 	secret.Data = byteData
-	_, err = s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	_, err = s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
 	return err
 }
 
 // DeleteSecret 删除Secret
-func (s *SecretService) DeleteSecret(namespace, name string) error {
-	return s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (s *SecretService) DeleteSecret(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
 }
 
 // convertSecret 转换Secret对象
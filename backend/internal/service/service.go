@@ -24,24 +24,21 @@ func (s *ServiceService) GetK8sClient() *k8s.Client {
 	return s.k8sClient
 }
 
-// ListServices 获取Service列表
-func (s *ServiceService) ListServices(namespace string) ([]model.ServiceInfo, error) {
-	serviceList, err := s.k8sClient.ClientSet.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
+// ListServices 获取Service列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *ServiceService) ListServices(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedServices(ctx, s.k8sClient, namespace, q)
 	if err != nil {
 		return nil, err
 	}
 
-	var services []model.ServiceInfo
-	for _, svc := range serviceList.Items {
-		services = append(services, s.convertService(&svc))
-	}
-
-	return services, nil
+	return pageList(list, listMeta, q, func(svc *corev1.Service) model.ServiceInfo {
+		return s.convertService(svc)
+	}), nil
 }
 
 // GetService 获取Service详情
-func (s *ServiceService) GetService(namespace, name string) (*model.ServiceInfo, error) {
-	svc, err := s.k8sClient.ClientSet.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+func (s *ServiceService) GetService(ctx context.Context, namespace, name string) (*model.ServiceInfo, error) {
+	svc, err := s.k8sClient.ClientSet.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -51,8 +48,8 @@ func (s *ServiceService) GetService(namespace, name string) (*model.ServiceInfo,
 }
 
 // DeleteService 删除Service
-func (s *ServiceService) DeleteService(namespace, name string) error {
-	return s.k8sClient.ClientSet.CoreV1().Services(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+func (s *ServiceService) DeleteService(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
 }
 
 // convertService 转换Service对象
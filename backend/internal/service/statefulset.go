@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// StatefulSetService StatefulSet服务
+type StatefulSetService struct {
+	k8sClient *k8s.Client
+}
+
+// NewStatefulSetService 创建StatefulSet服务
+func NewStatefulSetService(k8sClient *k8s.Client) *StatefulSetService {
+	return &StatefulSetService{k8sClient: k8sClient}
+}
+
+// GetK8sClient 获取K8s客户端
+func (s *StatefulSetService) GetK8sClient() *k8s.Client {
+	return s.k8sClient
+}
+
+// ListStatefulSets 获取StatefulSet列表（支持选择器、名称搜索、排序与分页；默认读 informer 缓存）
+func (s *StatefulSetService) ListStatefulSets(ctx context.Context, namespace string, q model.ListQuery) (*model.PageResponse, error) {
+	list, listMeta, err := cachedStatefulSets(ctx, s.k8sClient, namespace, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageList(list, listMeta, q, convertStatefulSet), nil
+}
+
+// GetStatefulSet 获取StatefulSet详情，含各序号副本的 Pod、修订版本与 PVC
+func (s *StatefulSetService) GetStatefulSet(ctx context.Context, namespace, name string) (*model.StatefulSetDetail, error) {
+	sts, err := s.k8sClient.ClientSet.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, _, err := cachedPods(ctx, s.k8sClient, namespace, model.ListQuery{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	pvcList, err := s.k8sClient.ClientSet.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.StatefulSetDetail{
+		StatefulSetInfo:      convertStatefulSet(sts),
+		PodManagementPolicy:  string(sts.Spec.PodManagementPolicy),
+		VolumeClaimTemplates: []string{},
+		Pods:                 statefulSetPods(sts, pods, pvcList.Items),
+	}
+	if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil {
+		detail.Partition = ru.Partition
+	}
+	for _, t := range sts.Spec.VolumeClaimTemplates {
+		detail.VolumeClaimTemplates = append(detail.VolumeClaimTemplates, t.Name)
+	}
+	return detail, nil
+}
+
+// DeleteStatefulSet 删除StatefulSet（PVC 按 persistentVolumeClaimRetentionPolicy 处理，默认保留）
+func (s *StatefulSetService) DeleteStatefulSet(ctx context.Context, namespace, name string, dryRun bool) error {
+	return s.k8sClient.ClientSet.AppsV1().StatefulSets(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(dryRun)})
+}
+
+// ScaleStatefulSet 扩缩容StatefulSet（HPA 管理副本数及 dryRun 的处理同 ScaleDeployment）
+func (s *StatefulSetService) ScaleStatefulSet(ctx context.Context, namespace, name string, replicas int32, force, dryRun bool) (string, error) {
+	warning, err := hpaScaleGuard(ctx, s.k8sClient, namespace, schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, name, force)
+	if err != nil {
+		return "", err
+	}
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	_, err = s.k8sClient.ClientSet.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{DryRun: dryRunOption(dryRun)})
+	return warning, err
+}
+
+// RestartStatefulSet 滚动重启StatefulSet
+func (s *StatefulSetService) RestartStatefulSet(ctx context.Context, namespace, name string) error {
+	_, err := s.k8sClient.ClientSet.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, restartPatch(), metav1.PatchOptions{})
+	return err
+}
+
+// restartPatch 注入 restartedAt 注解触发滚动重启（与 kubectl rollout restart 一致）
+func restartPatch() []byte {
+	return []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`,
+		metav1.Now().Format("2006-01-02T15:04:05Z")))
+}
+
+// convertStatefulSet 转换StatefulSet对象
+func convertStatefulSet(sts *appsv1.StatefulSet) model.StatefulSetInfo {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	return model.StatefulSetInfo{
+		K8sResource: model.K8sResource{
+			Name:              sts.Name,
+			Namespace:         sts.Namespace,
+			Labels:            sts.Labels,
+			Annotations:       sts.Annotations,
+			CreationTimestamp: sts.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			ResourceVersion:   sts.ResourceVersion,
+		},
+		Replicas:          replicas,
+		ReadyReplicas:     sts.Status.ReadyReplicas,
+		CurrentReplicas:   sts.Status.CurrentReplicas,
+		UpdatedReplicas:   sts.Status.UpdatedReplicas,
+		AvailableReplicas: sts.Status.AvailableReplicas,
+		ServiceName:       sts.Spec.ServiceName,
+		UpdateStrategy:    string(sts.Spec.UpdateStrategy.Type),
+		CurrentRevision:   sts.Status.CurrentRevision,
+		UpdateRevision:    sts.Status.UpdateRevision,
+		Images:            podSpecImages(&sts.Spec.Template.Spec),
+	}
+}
+
+// statefulSetPods 按序号列出副本：期望范围内的序号全部列出（Pod 未创建时 exists=false），
+// 范围外仍存在的 Pod（如缩容中）也一并列出
+func statefulSetPods(sts *appsv1.StatefulSet, pods []*corev1.Pod, pvcs []corev1.PersistentVolumeClaim) []model.StatefulSetPod {
+	start := 0
+	if sts.Spec.Ordinals != nil {
+		start = int(sts.Spec.Ordinals.Start)
+	}
+	replicas := 1
+	if sts.Spec.Replicas != nil {
+		replicas = int(*sts.Spec.Replicas)
+	}
+
+	byOrdinal := map[int]*corev1.Pod{}
+	for _, pod := range pods {
+		if !metav1.IsControlledBy(pod, sts) {
+			continue
+		}
+		if ordinal, ok := statefulSetOrdinal(sts.Name, pod.Name); ok {
+			byOrdinal[ordinal] = pod
+		}
+	}
+	ordinals := map[int]bool{}
+	for i := start; i < start+replicas; i++ {
+		ordinals[i] = true
+	}
+	for ordinal := range byOrdinal {
+		ordinals[ordinal] = true
+	}
+	sorted := make([]int, 0, len(ordinals))
+	for ordinal := range ordinals {
+		sorted = append(sorted, ordinal)
+	}
+	sort.Ints(sorted)
+
+	pvcByName := make(map[string]*corev1.PersistentVolumeClaim, len(pvcs))
+	for i := range pvcs {
+		pvcByName[pvcs[i].Name] = &pvcs[i]
+	}
+
+	result := make([]model.StatefulSetPod, 0, len(sorted))
+	for _, ordinal := range sorted {
+		sp := model.StatefulSetPod{
+			Ordinal: ordinal,
+			Name:    fmt.Sprintf("%s-%d", sts.Name, ordinal),
+			Claims:  []model.StatefulSetPVC{},
+		}
+		if pod := byOrdinal[ordinal]; pod != nil {
+			sp.Exists = true
+			sp.Phase = string(pod.Status.Phase)
+			sp.Ready = podReady(pod)
+			sp.Revision = pod.Labels[appsv1.ControllerRevisionHashLabelKey]
+			sp.Updated = sp.Revision != "" && sp.Revision == sts.Status.UpdateRevision
+			sp.Node = pod.Spec.NodeName
+		}
+		// PVC 命名规则：<template>-<statefulset>-<ordinal>
+		for _, t := range sts.Spec.VolumeClaimTemplates {
+			claim := model.StatefulSetPVC{Name: fmt.Sprintf("%s-%s", t.Name, sp.Name), Template: t.Name, Status: "Missing"}
+			if pvc := pvcByName[claim.Name]; pvc != nil {
+				claim.Status = string(pvc.Status.Phase)
+				if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+					claim.Capacity = capacity.String()
+				}
+				if pvc.Spec.StorageClassName != nil {
+					claim.StorageClass = *pvc.Spec.StorageClassName
+				}
+			}
+			sp.Claims = append(sp.Claims, claim)
+		}
+		result = append(result, sp)
+	}
+	return result
+}
+
+// statefulSetOrdinal 从 Pod 名（<statefulset>-<ordinal>）解析序号
+func statefulSetOrdinal(stsName, podName string) (int, bool) {
+	suffix, ok := strings.CutPrefix(podName, stsName+"-")
+	if !ok {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil || ordinal < 0 {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// podReady Pod 的 Ready 条件是否为 True
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
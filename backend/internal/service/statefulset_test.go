@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestStatefulSetOrdinal 从 Pod 名解析 StatefulSet 序号，前缀不符或非数字时失败
+func TestStatefulSetOrdinal(t *testing.T) {
+	cases := map[string]int{"db-0": 0, "db-12": 12, "db-x": -1, "dbx-1": -1, "db-backup-0": -1}
+	for pod, want := range cases {
+		got, ok := statefulSetOrdinal("db", pod)
+		if (want >= 0) != ok || (ok && got != want) {
+			t.Errorf("%s: got %d ok=%v, want %d", pod, got, ok, want)
+		}
+	}
+}
+
+// TestStatefulSetPods 按序号列出期望与残留的副本，标出缺失的 Pod 与 PVC 及是否已更新
+func TestStatefulSetPods(t *testing.T) {
+	replicas := int32(2)
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: types.UID("sts-uid")},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             &replicas,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+		},
+		Status: appsv1.StatefulSetStatus{UpdateRevision: "db-2"},
+	}
+	owned := func(name, revision string) *corev1.Pod {
+		controller := true
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Labels:          map[string]string{appsv1.ControllerRevisionHashLabelKey: revision},
+				OwnerReferences: []metav1.OwnerReference{{UID: sts.UID, Controller: &controller}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+	// db-1 尚未创建，db-2 为缩容中残留的副本
+	pods := []*corev1.Pod{owned("db-0", "db-2"), owned("db-2", "db-1"), {ObjectMeta: metav1.ObjectMeta{Name: "db-1"}}}
+	pvcs := []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data-db-0"}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}}}
+
+	got := statefulSetPods(sts, pods, pvcs)
+	if len(got) != 3 {
+		t.Fatalf("got %d pods, want 3", len(got))
+	}
+	if p := got[0]; !p.Exists || !p.Ready || !p.Updated || p.Claims[0].Status != "Bound" {
+		t.Errorf("db-0: %+v", p)
+	}
+	if p := got[1]; p.Exists || p.Name != "db-1" || p.Claims[0].Status != "Missing" {
+		t.Errorf("db-1: %+v", p)
+	}
+	if p := got[2]; !p.Exists || p.Ordinal != 2 || p.Updated {
+		t.Errorf("db-2: %+v", p)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+// typedWatchResources 可按类型化结构（与列表接口一致的 XxxInfo）推送的资源
+var typedWatchResources = map[string]schema.GroupVersionResource{
+	"pods":        {Version: "v1", Resource: "pods"},
+	"services":    {Version: "v1", Resource: "services"},
+	"configmaps":  {Version: "v1", Resource: "configmaps"},
+	"secrets":     {Version: "v1", Resource: "secrets"},
+	"nodes":       {Version: "v1", Resource: "nodes"},
+	"namespaces":  {Version: "v1", Resource: "namespaces"},
+	"events":      {Version: "v1", Resource: "events"},
+	"deployments": {Group: "apps", Version: "v1", Resource: "deployments"},
+}
+
+// TypedWatchGVR 查找类型化 watch 资源对应的 GVR
+func TypedWatchGVR(resource string) (schema.GroupVersionResource, bool) {
+	gvr, ok := typedWatchResources[resource]
+	return gvr, ok
+}
+
+// ValidateWatchNamespace 建连时校验指定的命名空间存在，不存在时返回 NotFound。
+// 不做按调用者的权限检查：watch 与列表接口的可见范围一致，所有角色都可读取全部命名空间
+func (s *ResourceService) ValidateWatchNamespace(ctx context.Context, namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	_, err := s.k8sClient.ClientSet.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	return err
+}
+
+// watchConverter 将 watch 到的对象转换为推送结构
+type watchConverter func(obj *unstructured.Unstructured) (interface{}, error)
+
+// Watch 持续 watch 资源并逐条回调 send，直至 ctx 取消或 send 返回错误。
+// resourceVersion 为空时先 LIST 下发现有对象（ADDED），再以 BOOKMARK 标记初始同步完成；
+// 否则从该版本续接。apiserver 侧 watch 超时断开由 RetryWatcher 按最新版本自动重连，
+// 版本过旧（410 Gone）时推送 ERROR 并结束，调用方应不带版本重新连接。
+// typed=true 时对象转换为与列表接口一致的结构，否则推送原始对象。
+func (s *ResourceService) Watch(ctx context.Context, gvr schema.GroupVersionResource, namespace string, q model.ListQuery, resourceVersion string, typed bool, send func(model.WatchEvent) error) error {
+	// watch 为长连接，不能沿用整体请求超时
+	dyn, err := dynamic.NewForConfig(s.k8sClient.StreamConfig())
+	if err != nil {
+		return err
+	}
+	var iface dynamic.ResourceInterface = dyn.Resource(gvr)
+	if namespace != "" {
+		iface = dyn.Resource(gvr).Namespace(namespace)
+	}
+
+	convert := rawWatchObject
+	if typed {
+		convert = s.typedWatchConverter(ctx, gvr)
+	}
+	emit := func(eventType string, obj *unstructured.Unstructured) error {
+		// 连接授权范围固定为建连时的 namespace，防御性丢弃范围外对象
+		if namespace != "" && obj.GetNamespace() != "" && obj.GetNamespace() != namespace {
+			return nil
+		}
+		out, err := convert(obj)
+		if err != nil {
+			return err
+		}
+		return send(model.WatchEvent{Type: eventType, Object: out, ResourceVersion: obj.GetResourceVersion()})
+	}
+
+	if resourceVersion == "" {
+		list, err := iface.List(ctx, metav1.ListOptions{LabelSelector: q.LabelSelector, FieldSelector: q.FieldSelector})
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			if err := emit(string(watch.Added), &list.Items[i]); err != nil {
+				return err
+			}
+		}
+		resourceVersion = list.GetResourceVersion()
+		if err := send(model.WatchEvent{Type: string(watch.Bookmark), ResourceVersion: resourceVersion}); err != nil {
+			return err
+		}
+	}
+
+	watcher, err := watchtools.NewRetryWatcher(resourceVersion, &cache.ListWatch{
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = q.LabelSelector
+			opts.FieldSelector = q.FieldSelector
+			return iface.Watch(ctx, opts)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			switch event.Type {
+			case watch.Error:
+				status := metav1.Status{Message: "watch 失败"}
+				if st, ok := event.Object.(*metav1.Status); ok {
+					status = *st
+				}
+				return send(model.WatchEvent{Type: string(watch.Error), Object: status})
+			case watch.Bookmark:
+				if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+					if err := send(model.WatchEvent{Type: string(watch.Bookmark), ResourceVersion: obj.GetResourceVersion()}); err != nil {
+						return err
+					}
+				}
+			default:
+				obj, ok := event.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				if err := emit(string(event.Type), obj); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// rawWatchObject 原样推送
+func rawWatchObject(obj *unstructured.Unstructured) (interface{}, error) {
+	return obj.Object, nil
+}
+
+// typedWatchConverter 按 GVR 选择类型化转换；非内置类型回退原始对象
+func (s *ResourceService) typedWatchConverter(ctx context.Context, gvr schema.GroupVersionResource) watchConverter {
+	switch gvr.Resource {
+	case "pods":
+		podService := NewPodService(s.k8sClient)
+		return typedConverter(func(pod *corev1.Pod) interface{} {
+			// 归属链按集群缓存，逐事件解析不会重复查询
+			return podService.convertPod(pod, nil, podService.podOwner(ctx, pod))
+		})
+	case "deployments":
+		return typedConverter(func(d *appsv1.Deployment) interface{} {
+			return NewDeploymentService(s.k8sClient).convertDeployment(d)
+		})
+	case "services":
+		return typedConverter(func(svc *corev1.Service) interface{} {
+			return NewServiceService(s.k8sClient).convertService(svc)
+		})
+	case "configmaps":
+		return typedConverter(func(cm *corev1.ConfigMap) interface{} {
+			return NewConfigMapService(s.k8sClient).convertConfigMap(cm)
+		})
+	case "secrets":
+		return typedConverter(func(secret *corev1.Secret) interface{} {
+			return NewSecretService(s.k8sClient).convertSecret(secret, false)
+		})
+	case "nodes":
+		return typedConverter(func(node *corev1.Node) interface{} {
+			return NewNodeService(s.k8sClient).convertNode(node)
+		})
+	case "namespaces":
+		return typedConverter(func(ns *corev1.Namespace) interface{} {
+			return convertNamespace(ns)
+		})
+	case "events":
+		return typedConverter(func(e *corev1.Event) interface{} {
+			return convertEvent(e)
+		})
+	}
+	return rawWatchObject
+}
+
+// typedConverter 先将 unstructured 转为具体类型，再套用列表接口的转换函数
+func typedConverter[T any](convert func(*T) interface{}) watchConverter {
+	return func(obj *unstructured.Unstructured) (interface{}, error) {
+		typed := new(T)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+			return nil, fmt.Errorf("转换 %s 失败: %w", obj.GetKind(), err)
+		}
+		return convert(typed), nil
+	}
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"github.com/kube-admin/kube-admin/backend/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// rolloutPollInterval 流式 rollout 状态的刷新间隔
+	rolloutPollInterval = 2 * time.Second
+	// maxBlockingPods 单次返回的阻塞 Pod 上限
+	maxBlockingPods = 20
+	// blockingPodEvents 每个阻塞 Pod 附带的最近事件数
+	blockingPodEvents = 3
+)
+
+// WorkloadKinds 支持 rollout 操作的工作负载（URL 中的 kind 参数）
+var WorkloadKinds = map[string]string{
+	"deployments":  "Deployment",
+	"statefulsets": "StatefulSet",
+	"daemonsets":   "DaemonSet",
+}
+
+// WorkloadService 工作负载（Deployment/StatefulSet/DaemonSet）的 rollout 相关操作
+type WorkloadService struct {
+	k8sClient *k8s.Client
+}
+
+// NewWorkloadService 创建工作负载服务
+func NewWorkloadService(k8sClient *k8s.Client) *WorkloadService {
+	return &WorkloadService{k8sClient: k8sClient}
+}
+
+// RolloutStatus 获取滚动更新状态。判定逻辑与 kubectl rollout status 一致；
+// 未完成时附带阻塞进度的 Pod（未就绪或终止中）及其最近事件。
+func (s *WorkloadService) RolloutStatus(ctx context.Context, kind, namespace, name string) (*model.RolloutStatus, error) {
+	status, selector, err := s.workloadStatus(ctx, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	status.BlockingPods = []model.BlockingPod{}
+	if !status.Done {
+		pods, err := s.blockingPods(ctx, namespace, selector)
+		if err != nil {
+			return nil, err
+		}
+		status.BlockingPods = pods
+	}
+	return status, nil
+}
+
+// WatchRolloutStatus 持续推送 rollout 状态：有变化时推送，完成或超出进度期限后结束
+func (s *WorkloadService) WatchRolloutStatus(ctx context.Context, kind, namespace, name string, send func(*model.RolloutStatus) error) error {
+	ticker := time.NewTicker(rolloutPollInterval)
+	defer ticker.Stop()
+	var last *model.RolloutStatus
+	for {
+		status, err := s.RolloutStatus(ctx, kind, namespace, name)
+		if err != nil {
+			return err
+		}
+		if last == nil || !reflect.DeepEqual(rolloutStatusWithoutVersion(last), rolloutStatusWithoutVersion(status)) {
+			if err := send(status); err != nil {
+				return err
+			}
+			last = status
+		}
+		if status.Done || status.ProgressDeadlineExceeded {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rolloutStatusWithoutVersion 去掉 resourceVersion 的副本，用于判断状态是否有实质变化
+func rolloutStatusWithoutVersion(st *model.RolloutStatus) model.RolloutStatus {
+	out := *st
+	out.ResourceVersion = ""
+	return out
+}
+
+// workloadStatus 读取工作负载并计算状态，同时返回其 Pod 选择器
+func (s *WorkloadService) workloadStatus(ctx context.Context, kind, namespace, name string) (*model.RolloutStatus, labels.Selector, error) {
+	apps := s.k8sClient.ClientSet.AppsV1()
+	var (
+		status   *model.RolloutStatus
+		selector *metav1.LabelSelector
+		err      error
+	)
+	switch kind {
+	case "deployments":
+		var d *appsv1.Deployment
+		if d, err = apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return nil, nil, err
+		}
+		status, selector = deploymentRolloutStatus(d), d.Spec.Selector
+	case "statefulsets":
+		var sts *appsv1.StatefulSet
+		if sts, err = apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return nil, nil, err
+		}
+		if status, err = statefulSetRolloutStatus(sts); err != nil {
+			return nil, nil, err
+		}
+		selector = sts.Spec.Selector
+	case "daemonsets":
+		var ds *appsv1.DaemonSet
+		if ds, err = apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return nil, nil, err
+		}
+		if status, err = daemonSetRolloutStatus(ds); err != nil {
+			return nil, nil, err
+		}
+		selector = ds.Spec.Selector
+	default:
+		return nil, nil, fmt.Errorf("不支持的工作负载类型: %s", kind)
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, nil, err
+	}
+	return status, sel, nil
+}
+
+// newRolloutStatus 填充公共元数据
+func newRolloutStatus(kind string, obj metav1.Object, observedGeneration int64) *model.RolloutStatus {
+	return &model.RolloutStatus{
+		Kind:               kind,
+		Namespace:          obj.GetNamespace(),
+		Name:               obj.GetName(),
+		Generation:         obj.GetGeneration(),
+		ObservedGeneration: observedGeneration,
+		ResourceVersion:    obj.GetResourceVersion(),
+	}
+}
+
+// deploymentRolloutStatus 计算 Deployment 的 rollout 状态
+func deploymentRolloutStatus(d *appsv1.Deployment) *model.RolloutStatus {
+	st := newRolloutStatus("Deployment", d, d.Status.ObservedGeneration)
+	st.Replicas = 1
+	if d.Spec.Replicas != nil {
+		st.Replicas = *d.Spec.Replicas
+	}
+	st.UpdatedReplicas = d.Status.UpdatedReplicas
+	st.ReadyReplicas = d.Status.ReadyReplicas
+	st.AvailableReplicas = d.Status.AvailableReplicas
+
+	if d.Generation > d.Status.ObservedGeneration {
+		st.Message = "等待 Deployment 的 spec 变更被控制器观测"
+		return st
+	}
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			st.ProgressDeadlineExceeded = true
+			st.Message = fmt.Sprintf("Deployment %q 超出进度期限: %s", d.Name, cond.Message)
+			return st
+		}
+	}
+	switch {
+	case d.Status.UpdatedReplicas < st.Replicas:
+		st.Message = fmt.Sprintf("等待 rollout 完成：%d/%d 个新副本已更新", d.Status.UpdatedReplicas, st.Replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		st.Message = fmt.Sprintf("等待 rollout 完成：%d 个旧副本待终止", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		st.Message = fmt.Sprintf("等待 rollout 完成：%d/%d 个已更新副本可用", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	default:
+		st.Done = true
+		st.Message = fmt.Sprintf("Deployment %q 已成功 rollout", d.Name)
+	}
+	return st
+}
+
+// statefulSetRolloutStatus 计算 StatefulSet 的 rollout 状态（OnDelete 策略无法跟踪）
+func statefulSetRolloutStatus(sts *appsv1.StatefulSet) (*model.RolloutStatus, error) {
+	if sts.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		return nil, fmt.Errorf("rollout 状态仅支持 RollingUpdate 更新策略，当前为 %s", sts.Spec.UpdateStrategy.Type)
+	}
+	st := newRolloutStatus("StatefulSet", sts, sts.Status.ObservedGeneration)
+	st.Replicas = 1
+	if sts.Spec.Replicas != nil {
+		st.Replicas = *sts.Spec.Replicas
+	}
+	st.UpdatedReplicas = sts.Status.UpdatedReplicas
+	st.ReadyReplicas = sts.Status.ReadyReplicas
+	st.AvailableReplicas = sts.Status.AvailableReplicas
+
+	if sts.Status.ObservedGeneration == 0 || sts.Generation > sts.Status.ObservedGeneration {
+		st.Message = "等待 StatefulSet 的 spec 变更被控制器观测"
+		return st, nil
+	}
+	if sts.Status.ReadyReplicas < st.Replicas {
+		st.Message = fmt.Sprintf("等待 %d 个 Pod 就绪", st.Replicas-sts.Status.ReadyReplicas)
+		return st, nil
+	}
+	if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil && *ru.Partition > 0 {
+		target := st.Replicas - *ru.Partition
+		if sts.Status.UpdatedReplicas < target {
+			st.Message = fmt.Sprintf("等待分区 rollout 完成：%d/%d 个新 Pod 已更新", sts.Status.UpdatedReplicas, target)
+			return st, nil
+		}
+		st.Done = true
+		st.Message = fmt.Sprintf("分区 rollout 完成：%d 个新 Pod 已更新", sts.Status.UpdatedReplicas)
+		return st, nil
+	}
+	if sts.Status.UpdateRevision != sts.Status.CurrentRevision {
+		st.Message = fmt.Sprintf("等待滚动更新完成：%d 个 Pod 已处于版本 %s", sts.Status.UpdatedReplicas, sts.Status.UpdateRevision)
+		return st, nil
+	}
+	st.Done = true
+	st.Message = fmt.Sprintf("StatefulSet %q 已成功 rollout（版本 %s）", sts.Name, sts.Status.CurrentRevision)
+	return st, nil
+}
+
+// daemonSetRolloutStatus 计算 DaemonSet 的 rollout 状态（OnDelete 策略无法跟踪）
+func daemonSetRolloutStatus(ds *appsv1.DaemonSet) (*model.RolloutStatus, error) {
+	if ds.Spec.UpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType {
+		return nil, fmt.Errorf("rollout 状态仅支持 RollingUpdate 更新策略，当前为 %s", ds.Spec.UpdateStrategy.Type)
+	}
+	st := newRolloutStatus("DaemonSet", ds, ds.Status.ObservedGeneration)
+	st.Replicas = ds.Status.DesiredNumberScheduled
+	st.UpdatedReplicas = ds.Status.UpdatedNumberScheduled
+	st.ReadyReplicas = ds.Status.NumberReady
+	st.AvailableReplicas = ds.Status.NumberAvailable
+
+	if ds.Generation > ds.Status.ObservedGeneration {
+		st.Message = "等待 DaemonSet 的 spec 变更被控制器观测"
+		return st, nil
+	}
+	switch {
+	case ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled:
+		st.Message = fmt.Sprintf("等待 rollout 完成：%d/%d 个新 Pod 已更新", ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled)
+	case ds.Status.NumberAvailable < ds.Status.DesiredNumberScheduled:
+		st.Message = fmt.Sprintf("等待 rollout 完成：%d/%d 个已更新 Pod 可用", ds.Status.NumberAvailable, ds.Status.DesiredNumberScheduled)
+	default:
+		st.Done = true
+		st.Message = fmt.Sprintf("DaemonSet %q 已成功 rollout", ds.Name)
+	}
+	return st, nil
+}
+
+// blockingPods 找出选择器范围内未就绪或终止中的 Pod，并附上各自最近的事件
+func (s *WorkloadService) blockingPods(ctx context.Context, namespace string, selector labels.Selector) ([]model.BlockingPod, error) {
+	pods, _, err := cachedPods(ctx, s.k8sClient, namespace, model.ListQuery{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	sortByNamespaceName(pods)
+
+	blocking := []model.BlockingPod{}
+	for _, pod := range pods {
+		bp, ok := blockingPod(pod)
+		if !ok {
+			continue
+		}
+		blocking = append(blocking, bp)
+		if len(blocking) >= maxBlockingPods {
+			break
+		}
+	}
+	if len(blocking) == 0 {
+		return blocking, nil
+	}
+
+	// 一次列出命名空间内 Pod 事件再按名称分组，避免逐 Pod 请求
+	events, err := s.k8sClient.ClientSet.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector:   "involvedObject.kind=Pod",
+		ResourceVersion: "0",
+	})
+	if err != nil {
+		return blocking, nil // 事件只是辅助信息，读取失败不影响状态
+	}
+	byPod := map[string][]*corev1.Event{}
+	for i := range events.Items {
+		e := &events.Items[i]
+		byPod[e.InvolvedObject.Name] = append(byPod[e.InvolvedObject.Name], e)
+	}
+	for i := range blocking {
+		podEvents := byPod[blocking[i].Name]
+		sort.Slice(podEvents, func(a, b int) bool { return eventTime(podEvents[a]).After(eventTime(podEvents[b])) })
+		if len(podEvents) > blockingPodEvents {
+			podEvents = podEvents[:blockingPodEvents]
+		}
+		for _, e := range podEvents {
+			blocking[i].Events = append(blocking[i].Events, convertEvent(e))
+		}
+	}
+	return blocking, nil
+}
+
+// blockingPod 判断 Pod 是否阻塞 rollout（终止中、未就绪），并提取最能说明问题的原因
+func blockingPod(pod *corev1.Pod) (model.BlockingPod, bool) {
+	bp := model.BlockingPod{
+		Name:   pod.Name,
+		Phase:  string(pod.Status.Phase),
+		Node:   pod.Spec.NodeName,
+		Events: []model.EventInfo{},
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		bp.Restarts += cs.RestartCount
+	}
+	if pod.DeletionTimestamp != nil {
+		bp.Reason = "Terminating"
+		return bp, true
+	}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return bp, false
+	}
+	if podReady(pod) {
+		return bp, false
+	}
+
+	// 依次取 init 容器、业务容器的等待/终止原因，最后退回 Pod 条件
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if w := cs.State.Waiting; w != nil && w.Reason != "" {
+			bp.Reason, bp.Message = w.Reason, w.Message
+			return bp, true
+		}
+		if t := cs.State.Terminated; t != nil && t.ExitCode != 0 {
+			bp.Reason, bp.Message = t.Reason, t.Message
+			return bp, true
+		}
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue && cond.Reason != "" {
+			bp.Reason, bp.Message = cond.Reason, cond.Message
+			return bp, true
+		}
+	}
+	bp.Reason = "NotReady"
+	return bp, true
+}
+
+// eventTime 事件的最近发生时间（兼容仅填 EventTime 的新版事件）
+func eventTime(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testDeployment(replicas int32, status appsv1.DeploymentStatus) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     status,
+	}
+}
+
+// TestDeploymentRolloutStatus 按 observedGeneration、副本数与 Progressing 条件判断滚动更新进度
+func TestDeploymentRolloutStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   appsv1.DeploymentStatus
+		done     bool
+		deadline bool
+	}{
+		{name: "not observed", status: appsv1.DeploymentStatus{ObservedGeneration: 1}},
+		{name: "updating", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1}},
+		{name: "old pending termination", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3}},
+		{name: "not available", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}},
+		{name: "done", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}, done: true},
+		{name: "deadline exceeded", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+		}}, deadline: true},
+	}
+	for _, tc := range cases {
+		st := deploymentRolloutStatus(testDeployment(3, tc.status))
+		if st.Done != tc.done || st.ProgressDeadlineExceeded != tc.deadline || st.Message == "" {
+			t.Errorf("%s: done=%v deadline=%v message=%q", tc.name, st.Done, st.ProgressDeadlineExceeded, st.Message)
+		}
+	}
+}
+
+// TestBlockingPod 就绪 Pod 不阻塞，拉取镜像失败或终止中的 Pod 给出阻塞原因
+func TestBlockingPod(t *testing.T) {
+	ready := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+		}},
+	}
+	if _, ok := blockingPod(ready); ok {
+		t.Error("ready pod should not block")
+	}
+
+	pulling := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pulling"},
+		Status: corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "web", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}}},
+		}},
+	}
+	if bp, ok := blockingPod(pulling); !ok || bp.Reason != "ImagePullBackOff" {
+		t.Errorf("pulling pod = %+v, %v", bp, ok)
+	}
+
+	now := metav1.Now()
+	terminating := ready.DeepCopy()
+	terminating.DeletionTimestamp = &now
+	if bp, ok := blockingPod(terminating); !ok || bp.Reason != "Terminating" {
+		t.Errorf("terminating pod = %+v, %v", bp, ok)
+	}
+}
@@ -0,0 +1,255 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	autoscalinglisters "k8s.io/client-go/listers/autoscaling/v2"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// cacheSyncTimeout 首次访问等待 informer 同步的上限，超时由调用方回退实时 LIST
+	cacheSyncTimeout = 30 * time.Second
+	// cacheIdleTimeout 集群缓存闲置超过该时长即停止 informer，释放 watch 连接与内存
+	cacheIdleTimeout = 10 * time.Minute
+	// cacheRetryInterval 同步失败后的冷却期，期间直接走实时 LIST，避免每次请求都等待同步超时
+	cacheRetryInterval = time.Minute
+)
+
+// InformerCache 单个集群的共享 informer 缓存。
+// 按资源类型懒启动：首次读取某类资源时才注册并启动对应 informer；闲置超时后整体停止，下次访问重建。
+type InformerCache struct {
+	config   *rest.Config
+	mu       sync.Mutex
+	factory  informers.SharedInformerFactory
+	stopCh   chan struct{}
+	lastUsed atomic.Int64
+	failedAt sync.Map // 资源类型 -> 最近一次同步失败时间
+}
+
+// newInformerCache 创建缓存（不立即启动任何 informer）。
+// cfg 须不带整体请求超时（见 Client.StreamConfig），否则 watch 每隔数秒被掐断重新 LIST。
+func newInformerCache(cfg *rest.Config) *InformerCache {
+	return &InformerCache{config: cfg}
+}
+
+// Pods 返回已同步的 Pod lister
+func (c *InformerCache) Pods(ctx context.Context) (corelisters.PodLister, error) {
+	f, err := c.ensure(ctx, "pods", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Pods().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Core().V1().Pods().Lister(), nil
+}
+
+// Nodes 返回已同步的 Node lister
+func (c *InformerCache) Nodes(ctx context.Context) (corelisters.NodeLister, error) {
+	f, err := c.ensure(ctx, "nodes", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Nodes().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Core().V1().Nodes().Lister(), nil
+}
+
+// Namespaces 返回已同步的 Namespace lister
+func (c *InformerCache) Namespaces(ctx context.Context) (corelisters.NamespaceLister, error) {
+	f, err := c.ensure(ctx, "namespaces", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Namespaces().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Core().V1().Namespaces().Lister(), nil
+}
+
+// Services 返回已同步的 Service lister
+func (c *InformerCache) Services(ctx context.Context) (corelisters.ServiceLister, error) {
+	f, err := c.ensure(ctx, "services", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Services().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Core().V1().Services().Lister(), nil
+}
+
+// ConfigMaps 返回已同步的 ConfigMap lister
+func (c *InformerCache) ConfigMaps(ctx context.Context) (corelisters.ConfigMapLister, error) {
+	f, err := c.ensure(ctx, "configmaps", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().ConfigMaps().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Core().V1().ConfigMaps().Lister(), nil
+}
+
+// Deployments 返回已同步的 Deployment lister
+func (c *InformerCache) Deployments(ctx context.Context) (appslisters.DeploymentLister, error) {
+	f, err := c.ensure(ctx, "deployments", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().Deployments().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Apps().V1().Deployments().Lister(), nil
+}
+
+// ReplicaSets 返回已同步的 ReplicaSet lister
+func (c *InformerCache) ReplicaSets(ctx context.Context) (appslisters.ReplicaSetLister, error) {
+	f, err := c.ensure(ctx, "replicasets", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().ReplicaSets().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Apps().V1().ReplicaSets().Lister(), nil
+}
+
+// StatefulSets 返回已同步的 StatefulSet lister
+func (c *InformerCache) StatefulSets(ctx context.Context) (appslisters.StatefulSetLister, error) {
+	f, err := c.ensure(ctx, "statefulsets", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().StatefulSets().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Apps().V1().StatefulSets().Lister(), nil
+}
+
+// DaemonSets 返回已同步的 DaemonSet lister
+func (c *InformerCache) DaemonSets(ctx context.Context) (appslisters.DaemonSetLister, error) {
+	f, err := c.ensure(ctx, "daemonsets", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().DaemonSets().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Apps().V1().DaemonSets().Lister(), nil
+}
+
+// Jobs 返回已同步的 Job lister
+func (c *InformerCache) Jobs(ctx context.Context) (batchlisters.JobLister, error) {
+	f, err := c.ensure(ctx, "jobs", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Batch().V1().Jobs().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Batch().V1().Jobs().Lister(), nil
+}
+
+// CronJobs 返回已同步的 CronJob lister
+func (c *InformerCache) CronJobs(ctx context.Context) (batchlisters.CronJobLister, error) {
+	f, err := c.ensure(ctx, "cronjobs", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Batch().V1().CronJobs().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Batch().V1().CronJobs().Lister(), nil
+}
+
+// HorizontalPodAutoscalers 返回已同步的 HPA（autoscaling/v2）lister
+func (c *InformerCache) HorizontalPodAutoscalers(ctx context.Context) (autoscalinglisters.HorizontalPodAutoscalerLister, error) {
+	f, err := c.ensure(ctx, "horizontalpodautoscalers", func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Autoscaling().V2().HorizontalPodAutoscalers().Informer()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.Autoscaling().V2().HorizontalPodAutoscalers().Lister(), nil
+}
+
+// ensure 确保 factory 已创建、目标 informer 已启动并同步（等待同步随 ctx 取消）
+func (c *InformerCache) ensure(ctx context.Context, kind string, get func(informers.SharedInformerFactory) cache.SharedIndexInformer) (informers.SharedInformerFactory, error) {
+	c.lastUsed.Store(time.Now().UnixNano())
+	if t, ok := c.failedAt.Load(kind); ok && time.Since(t.(time.Time)) < cacheRetryInterval {
+		return nil, fmt.Errorf("%s 缓存暂不可用", kind)
+	}
+
+	c.mu.Lock()
+	if c.factory == nil {
+		clientSet, err := kubernetes.NewForConfig(c.config)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		c.factory = informers.NewSharedInformerFactoryWithOptions(clientSet, 0,
+			informers.WithTransform(stripManagedFields))
+		c.stopCh = make(chan struct{})
+		go c.reapIdle(c.stopCh)
+	}
+	factory, stopCh := c.factory, c.stopCh
+	informer := get(factory)
+	factory.Start(stopCh) // 幂等：仅启动新注册的 informer
+	c.mu.Unlock()
+
+	if informer.HasSynced() {
+		return factory, nil
+	}
+	// 等待随调用方 ctx 取消；informer 在后台继续同步，供后续请求使用
+	waitCtx, cancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(waitCtx.Done(), informer.HasSynced) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.failedAt.Store(kind, time.Now())
+		return nil, fmt.Errorf("%s informer 缓存同步超时", kind)
+	}
+	return factory, nil
+}
+
+// reapIdle 周期检查闲置时长，超时停止本轮 factory
+func (c *InformerCache) reapIdle(stopCh chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, c.lastUsed.Load())) > cacheIdleTimeout {
+				c.Stop()
+				return
+			}
+		}
+	}
+}
+
+// Stop 停止所有 informer；之后的访问会重新创建
+func (c *InformerCache) Stop() {
+	c.mu.Lock()
+	factory, stopCh := c.factory, c.stopCh
+	c.factory, c.stopCh = nil, nil
+	c.mu.Unlock()
+	if factory == nil {
+		return
+	}
+	close(stopCh)
+	go factory.Shutdown() // 等待 informer goroutine 退出，不阻塞调用方
+}
+
+// stripManagedFields 丢弃 managedFields，显著降低大集群缓存内存占用
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
@@ -3,9 +3,12 @@ package k8s
 import (
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/kube-admin/kube-admin/backend/config"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
@@ -17,7 +20,61 @@ type Client struct {
 	ClientSet        *kubernetes.Clientset
 	MetricsClientSet *versioned.Clientset
 	AggregatorClient *clientset.Clientset
+	DynamicClient    dynamic.Interface
+	MetadataClient   metadata.Interface // 仅元数据（PartialObjectMetadata）的列表/计数
 	Config           *rest.Config
+
+	cacheMu sync.Mutex
+	cache   *InformerCache // 懒创建，见 Cache()
+
+	discoveryOnce  sync.Once
+	discoveryCache *discoveryCache // 懒创建，见 RESTMapper()
+
+	sharedMu sync.Mutex
+	shared   map[interface{}]interface{} // 上层按集群缓存的数据，见 Shared()
+}
+
+// Cache 返回该集群的共享 informer 缓存（懒创建，首次读取某类资源时才启动对应 informer）
+func (c *Client) Cache() *InformerCache {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if c.cache == nil {
+		c.cache = newInformerCache(c.StreamConfig())
+	}
+	return c.cache
+}
+
+// Shared 返回挂在该客户端上、以 key 区分的共享对象，首次调用时由 create 创建。
+// 供上层（服务按请求创建）按集群缓存数据，随客户端一起被替换或移除，不必自行清理
+func (c *Client) Shared(key interface{}, create func() interface{}) interface{} {
+	c.sharedMu.Lock()
+	defer c.sharedMu.Unlock()
+	if v, ok := c.shared[key]; ok {
+		return v
+	}
+	if c.shared == nil {
+		c.shared = map[interface{}]interface{}{}
+	}
+	v := create()
+	c.shared[key] = v
+	return v
+}
+
+// StreamConfig 返回去掉整体请求超时的配置副本，用于 watch、日志跟随等长连接（生命周期由 ctx 控制）
+func (c *Client) StreamConfig() *rest.Config {
+	cfg := rest.CopyConfig(c.Config)
+	cfg.Timeout = 0
+	return cfg
+}
+
+// Close 释放客户端持有的后台资源（informer 缓存）
+func (c *Client) Close() {
+	c.cacheMu.Lock()
+	cache := c.cache
+	c.cacheMu.Unlock()
+	if cache != nil {
+		cache.Stop()
+	}
 }
 
 // applyConfigDefaults 统一为 rest.Config 注入全局默认值（请求超时）。
@@ -72,10 +129,22 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 		return nil, err
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataClient, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
 		ClientSet:        clientSet,
 		MetricsClientSet: metricsClientSet,
 		AggregatorClient: aggregatorClient,
+		DynamicClient:    dynamicClient,
+		MetadataClient:   metadataClient,
 		Config:           config,
 	}, nil
 }
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+)
+
+// discoveryTTL 发现缓存的最长有效期，到期后下次访问重新拉取（兜底感知 CRD 增删）
+const discoveryTTL = 10 * time.Minute
+
+// discoveryCache 集群级共享的发现缓存与 RESTMapper
+type discoveryCache struct {
+	mu        sync.Mutex
+	client    discovery.CachedDiscoveryInterface
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+	refreshed time.Time
+}
+
+// discovery 返回（必要时创建或按 TTL 失效）共享发现缓存
+func (c *Client) discovery() *discoveryCache {
+	c.discoveryOnce.Do(func() {
+		cached := memory.NewMemCacheClient(c.ClientSet.Discovery())
+		c.discoveryCache = &discoveryCache{
+			client:    cached,
+			mapper:    restmapper.NewDeferredDiscoveryRESTMapper(cached),
+			refreshed: time.Now(),
+		}
+	})
+	d := c.discoveryCache
+	d.mu.Lock()
+	if time.Since(d.refreshed) > discoveryTTL {
+		d.mapper.Reset() // 同时失效底层 discovery 缓存
+		d.refreshed = time.Now()
+	}
+	d.mu.Unlock()
+	return d
+}
+
+// CachedDiscovery 返回共享的缓存发现客户端
+func (c *Client) CachedDiscovery() discovery.CachedDiscoveryInterface {
+	return c.discovery().client
+}
+
+// RESTMapper 返回共享的 RESTMapper
+func (c *Client) RESTMapper() meta.RESTMapper {
+	return c.discovery().mapper
+}
+
+// RESTMapping 将 GroupKind 映射为 REST 资源。未命中时（如刚安装的 CRD）失效缓存后重试一次。
+func (c *Client) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	d := c.discovery()
+	mapping, err := d.mapper.RESTMapping(gk, versions...)
+	if err != nil && meta.IsNoMatchError(err) {
+		d.mu.Lock()
+		d.mapper.Reset()
+		d.refreshed = time.Now()
+		d.mu.Unlock()
+		mapping, err = d.mapper.RESTMapping(gk, versions...)
+	}
+	return mapping, err
+}
+
+// InvalidateDiscovery 主动失效发现缓存（如安装/删除 CRD 之后）
+func (c *Client) InvalidateDiscovery() {
+	d := c.discovery()
+	d.mu.Lock()
+	d.mapper.Reset()
+	d.refreshed = time.Now()
+	d.mu.Unlock()
+}
@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// restClientGetter 以集群已有的 rest.Config 与共享发现缓存实现 RESTClientGetter，
+// 供 Helm SDK 等基于 kubectl 工厂的库使用（无需 kubeconfig 文件，agent 隧道集群同样适用）
+type restClientGetter struct {
+	client    *Client
+	namespace string
+}
+
+// RESTClientGetter 返回默认命名空间为 namespace 的 RESTClientGetter。
+// 使用不带整体超时的配置，安装/升级等待资源就绪时的 watch 由调用方的超时控制。
+func (c *Client) RESTClientGetter(namespace string) genericclioptions.RESTClientGetter {
+	return &restClientGetter{client: c, namespace: namespace}
+}
+
+func (g *restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.client.StreamConfig(), nil
+}
+
+func (g *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return g.client.CachedDiscovery(), nil
+}
+
+func (g *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	return g.client.RESTMapper(), nil
+}
+
+func (g *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return &namespaceClientConfig{getter: g}
+}
+
+// namespaceClientConfig 只提供命名空间与 rest.Config 的 ClientConfig（没有 kubeconfig 文件可读）
+type namespaceClientConfig struct {
+	getter *restClientGetter
+}
+
+func (n *namespaceClientConfig) RawConfig() (clientcmdapi.Config, error) {
+	return clientcmdapi.Config{}, nil
+}
+
+func (n *namespaceClientConfig) ClientConfig() (*rest.Config, error) {
+	return n.getter.ToRESTConfig()
+}
+
+func (n *namespaceClientConfig) Namespace() (string, bool, error) {
+	if n.getter.namespace == "" {
+		return "default", false, nil
+	}
+	return n.getter.namespace, true, nil
+}
+
+func (n *namespaceClientConfig) ConfigAccess() clientcmd.ConfigAccess {
+	return clientcmd.NewDefaultClientConfigLoadingRules()
+}
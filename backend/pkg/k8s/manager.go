@@ -6,7 +6,9 @@ import (
 
 	"github.com/kube-admin/kube-admin/backend/config"
 	"github.com/kube-admin/kube-admin/backend/internal/model"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientset "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
@@ -16,6 +18,7 @@ import (
 // Manager 多集群管理器
 type Manager struct {
 	clusters map[uint]*Client
+	tunnels  map[uint]*agentTunnel // agent 模式集群的反向隧道
 	mutex    sync.RWMutex
 }
 
@@ -23,6 +26,7 @@ type Manager struct {
 func NewManager() *Manager {
 	return &Manager{
 		clusters: make(map[uint]*Client),
+		tunnels:  make(map[uint]*agentTunnel),
 	}
 }
 
@@ -42,8 +46,13 @@ func (m *Manager) GetClient(clusterID uint, cluster *model.Cluster) (*Client, er
 		return nil, err
 	}
 
-	// 存储客户端
+	// 存储客户端（并发创建时保留先到者，关闭多余的一份）
 	m.mutex.Lock()
+	if existing, ok := m.clusters[clusterID]; ok {
+		m.mutex.Unlock()
+		newClient.Close()
+		return existing, nil
+	}
 	m.clusters[clusterID] = newClient
 	m.mutex.Unlock()
 
@@ -55,8 +64,14 @@ func (m *Manager) createClient(cluster *model.Cluster) (*Client, error) {
 	var restConfig *rest.Config
 	var err error
 
-	// 优先使用配置内容
-	if cluster.ConfigContent != "" {
+	// agent 模式：经反向隧道访问，忽略直连凭据
+	if cluster.IsAgent() {
+		restConfig, err = m.tunnelRestConfig(cluster.ID)
+		if err != nil {
+			return nil, err
+		}
+	} else if cluster.ConfigContent != "" {
+		// 优先使用配置内容
 		clientConfig, cerr := clientcmd.NewClientConfigFromBytes([]byte(cluster.ConfigContent))
 		if cerr != nil {
 			return nil, fmt.Errorf("failed to build config from content: %v", cerr)
@@ -101,17 +116,33 @@ func (m *Manager) createClient(cluster *model.Cluster) (*Client, error) {
 		return nil, fmt.Errorf("failed to create aggregator client: %v", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
+	}
+
+	metadataClient, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %v", err)
+	}
+
 	return &Client{
 		ClientSet:        clientSet,
 		MetricsClientSet: metricsClientSet,
 		AggregatorClient: aggregatorClient,
+		DynamicClient:    dynamicClient,
+		MetadataClient:   metadataClient,
 		Config:           restConfig,
 	}, nil
 }
 
-// RemoveClient 移除指定集群的客户端
+// RemoveClient 移除指定集群的客户端并停止其 informer 缓存
 func (m *Manager) RemoveClient(clusterID uint) {
 	m.mutex.Lock()
+	client := m.clusters[clusterID]
 	delete(m.clusters, clusterID)
 	m.mutex.Unlock()
+	if client != nil {
+		client.Close()
+	}
 }
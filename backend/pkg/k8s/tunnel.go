@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/yamux"
+	"k8s.io/client-go/rest"
+)
+
+// agentTunnel 一个已连接 agent 的隧道。
+// 会话之上开本机回环监听：client-go（含 SPDY exec / 日志流）照常拨 TCP 地址，
+// 每个连接被转成一个 yamux 流交给 agent，agent 再以自身 ServiceAccount 身份转发到 apiserver。
+type agentTunnel struct {
+	session     *yamux.Session
+	listener    net.Listener
+	token       string // 会话令牌：经隧道的请求须携带，防止本机其他进程借用回环端口
+	remoteAddr  string
+	connectedAt time.Time
+}
+
+// serve 接受回环连接并桥接到 yamux 流，监听关闭即退出
+func (t *agentTunnel) serve() {
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			return
+		}
+		stream, err := t.session.Open()
+		if err != nil {
+			conn.Close()
+			continue
+		}
+		go bridge(conn, stream)
+	}
+}
+
+// close 关闭回环监听与会话
+func (t *agentTunnel) close() {
+	t.listener.Close()
+	t.session.Close()
+}
+
+// bridge 双向拷贝，任一方向结束即关闭两端
+func bridge(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+	go func() {
+		_, _ = io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	_, _ = io.Copy(b, a)
+	once.Do(closeBoth)
+}
+
+// RegisterTunnel 登记 agent 隧道。同一集群重复连接时替换旧隧道；
+// 会话断开后自动注销。登记/注销都会丢弃该集群已缓存的客户端，下次请求按新地址重建。
+func (m *Manager) RegisterTunnel(clusterID uint, session *yamux.Session, token, remoteAddr string) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("创建隧道回环监听失败: %v", err)
+	}
+	t := &agentTunnel{
+		session:     session,
+		listener:    listener,
+		token:       token,
+		remoteAddr:  remoteAddr,
+		connectedAt: time.Now(),
+	}
+
+	m.mutex.Lock()
+	old := m.tunnels[clusterID]
+	m.tunnels[clusterID] = t
+	m.mutex.Unlock()
+	m.RemoveClient(clusterID)
+	if old != nil {
+		old.close()
+	}
+
+	go t.serve()
+	go func() {
+		<-session.CloseChan()
+		m.unregisterTunnel(clusterID, t)
+	}()
+	log.Printf("agent tunnel connected: cluster=%d remote=%s", clusterID, remoteAddr)
+	return nil
+}
+
+// unregisterTunnel 注销隧道（仅当仍是当前隧道时，避免误删重连后的新隧道）
+func (m *Manager) unregisterTunnel(clusterID uint, t *agentTunnel) {
+	m.mutex.Lock()
+	current := m.tunnels[clusterID] == t
+	if current {
+		delete(m.tunnels, clusterID)
+	}
+	m.mutex.Unlock()
+	t.close()
+	if current {
+		m.RemoveClient(clusterID)
+		log.Printf("agent tunnel disconnected: cluster=%d", clusterID)
+	}
+}
+
+// CloseTunnel 断开集群的 agent 隧道（集群删除、改为直连或 agent 凭据重新签发时调用），
+// 未连接时不做任何事。缓存的客户端由调用方通过 RemoveClient 丢弃
+func (m *Manager) CloseTunnel(clusterID uint) {
+	m.mutex.Lock()
+	t, ok := m.tunnels[clusterID]
+	delete(m.tunnels, clusterID)
+	m.mutex.Unlock()
+	if ok {
+		t.close()
+		log.Printf("agent tunnel closed: cluster=%d", clusterID)
+	}
+}
+
+// TunnelStatus 返回集群 agent 隧道状态：是否在线、连接时间、agent 远端地址
+func (m *Manager) TunnelStatus(clusterID uint) (bool, time.Time, string) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	t, ok := m.tunnels[clusterID]
+	if !ok {
+		return false, time.Time{}, ""
+	}
+	return true, t.connectedAt, t.remoteAddr
+}
+
+// tunnelRestConfig 构造经隧道访问 apiserver 的 rest.Config
+func (m *Manager) tunnelRestConfig(clusterID uint) (*rest.Config, error) {
+	m.mutex.RLock()
+	t, ok := m.tunnels[clusterID]
+	m.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("集群 agent 未连接")
+	}
+	return &rest.Config{
+		Host:        "http://" + t.listener.Addr().String(),
+		BearerToken: t.token,
+	}, nil
+}
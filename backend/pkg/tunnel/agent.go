@@ -0,0 +1,263 @@
+package tunnel
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// errUnauthorized kube-admin 拒绝了 agent 凭据（集群已删除或凭据被重置）
+var errUnauthorized = errors.New("kube-admin 拒绝了 agent 凭据")
+
+// AgentConfig agent 运行参数
+type AgentConfig struct {
+	ServerURL          string // kube-admin 对外地址，如 https://kube-admin.example.com
+	JoinToken          string // 一次性 join token，仅首次注册需要
+	Namespace          string // agent 所在 namespace，长期凭据 Secret 存放于此
+	CredentialSecret   string // 长期凭据 Secret 名
+	InsecureSkipVerify bool   // 跳过 kube-admin 证书校验（仅开发环境）
+}
+
+// Agent 集群内 agent：持有 in-cluster ServiceAccount 凭据，将隧道内的请求反向代理到本集群 apiserver
+type Agent struct {
+	cfg       AgentConfig
+	clientSet kubernetes.Interface
+	proxy     *httputil.ReverseProxy
+}
+
+// NewAgent 基于 in-cluster 配置创建 agent
+func NewAgent(cfg AgentConfig) (*Agent, error) {
+	if cfg.ServerURL == "" {
+		return nil, fmt.Errorf("ServerURL 不能为空")
+	}
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("读取 in-cluster 配置失败: %w", err)
+	}
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// 代理侧固定 HTTP/1.1：exec/attach/port-forward 依赖 Upgrade，HTTP/2 不支持
+	proxyConfig := rest.CopyConfig(restConfig)
+	proxyConfig.NextProtos = []string{"http/1.1"}
+	transport, err := rest.TransportFor(proxyConfig)
+	if err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(restConfig.Host)
+	if err != nil {
+		return nil, err
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Host = target.Host
+	}
+	proxy.Transport = transport
+	proxy.FlushInterval = -1 // watch / 日志流需即时刷新
+
+	return &Agent{cfg: cfg, clientSet: clientSet, proxy: proxy}, nil
+}
+
+// Run 注册（如需）并保持隧道连接，断线后指数退避重连，直到 ctx 取消
+func (a *Agent) Run(ctx context.Context) error {
+	credential, err := a.credential(ctx)
+	if err != nil {
+		return err
+	}
+
+	backoff := 2 * time.Second
+	for {
+		start := time.Now()
+		err := a.connect(ctx, credential)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, errUnauthorized) {
+			log.Printf("[ERROR] %v，请在 kube-admin 重新签发 join token 并删除 Secret %s/%s 后重启 agent",
+				err, a.cfg.Namespace, a.cfg.CredentialSecret)
+		} else {
+			log.Printf("[WARN] 隧道断开: %v", err)
+		}
+		// 稳定连接过一段时间后断开，退避从头开始
+		if time.Since(start) > time.Minute {
+			backoff = 2 * time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// credential 读取已保存的长期凭据；不存在时用 join token 注册并保存到 Secret
+func (a *Agent) credential(ctx context.Context) (string, error) {
+	secrets := a.clientSet.CoreV1().Secrets(a.cfg.Namespace)
+	secret, err := secrets.Get(ctx, a.cfg.CredentialSecret, metav1.GetOptions{})
+	if err == nil {
+		if cred := string(secret.Data["credential"]); cred != "" {
+			return cred, nil
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("读取凭据 Secret 失败: %w", err)
+	}
+
+	if a.cfg.JoinToken == "" {
+		return "", fmt.Errorf("未找到已保存的凭据，且未提供 join token")
+	}
+	cred, err := a.register(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = secrets.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: a.cfg.CredentialSecret, Namespace: a.cfg.Namespace},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"credential": []byte(cred)},
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		secret, gerr := secrets.Get(ctx, a.cfg.CredentialSecret, metav1.GetOptions{})
+		if gerr != nil {
+			return "", gerr
+		}
+		secret.Data = map[string][]byte{"credential": []byte(cred)}
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		// join token 已被消费，凭据未保存则重启后无法再注册，必须显式报错
+		return "", fmt.Errorf("保存凭据 Secret 失败: %w", err)
+	}
+	log.Printf("agent 注册成功，凭据已保存到 Secret %s/%s", a.cfg.Namespace, a.cfg.CredentialSecret)
+	return cred, nil
+}
+
+// register 用一次性 join token 换取长期凭据
+func (a *Agent) register(ctx context.Context) (string, error) {
+	body, _ := json.Marshal(map[string]string{"token": a.cfg.JoinToken})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimRight(a.cfg.ServerURL, "/")+RegisterPath, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: a.tlsConfig()},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("注册请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Credential string `json:"credential"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("解析注册响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Data.Credential == "" {
+		return "", fmt.Errorf("注册失败: %s", result.Message)
+	}
+	return result.Data.Credential, nil
+}
+
+// connect 建立一次隧道会话并阻塞服务，直到会话断开
+func (a *Agent) connect(ctx context.Context, credential string) error {
+	wsURL, err := url.Parse(strings.TrimRight(a.cfg.ServerURL, "/") + ConnectPath)
+	if err != nil {
+		return err
+	}
+	switch wsURL.Scheme {
+	case "https":
+		wsURL.Scheme = "wss"
+	case "http":
+		wsURL.Scheme = "ws"
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 15 * time.Second,
+		TLSClientConfig:  a.tlsConfig(),
+		Proxy:            http.ProxyFromEnvironment,
+	}
+	header := http.Header{"Authorization": []string{"Bearer " + credential}}
+	ws, resp, err := dialer.DialContext(ctx, wsURL.String(), header)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return errUnauthorized
+		}
+		return err
+	}
+	sessionToken := resp.Header.Get(SessionTokenHeader)
+	if sessionToken == "" {
+		ws.Close()
+		return fmt.Errorf("握手响应缺少会话令牌")
+	}
+
+	session, err := NewAgentSession(ws)
+	if err != nil {
+		ws.Close()
+		return err
+	}
+	defer session.Close()
+	go func() {
+		select {
+		case <-ctx.Done():
+			session.Close()
+		case <-session.CloseChan():
+		}
+	}()
+	log.Printf("隧道已建立: %s", a.cfg.ServerURL)
+
+	srv := &http.Server{
+		Handler:           a.handler(sessionToken),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	return srv.Serve(session)
+}
+
+// handler 校验会话令牌后，去掉 kube-admin 的令牌，以 agent 自身 ServiceAccount 身份转发到 apiserver
+func (a *Agent) handler(sessionToken string) http.Handler {
+	expected := []byte("Bearer " + sessionToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "invalid tunnel token", http.StatusUnauthorized)
+			return
+		}
+		r.Header.Del("Authorization")
+		a.proxy.ServeHTTP(w, r)
+	})
+}
+
+func (a *Agent) tlsConfig() *tls.Config {
+	return &tls.Config{InsecureSkipVerify: a.cfg.InsecureSkipVerify} //nolint:gosec // 由 TLS_SKIP_VERIFY 显式开启，仅开发环境
+}
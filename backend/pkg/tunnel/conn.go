@@ -0,0 +1,97 @@
+// Package tunnel 实现 agent 反向隧道：集群内 agent 主动向 kube-admin 建立 WebSocket，
+// 其上承载 yamux 多路复用会话，kube-admin 经会话内的流访问该集群的 apiserver。
+// 适用于 NAT / 内网等 kube-admin 无法直连 apiserver 的集群。
+package tunnel
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/yamux"
+)
+
+const (
+	// SessionTokenHeader 握手响应头：kube-admin 为本次会话签发的随机令牌。
+	// kube-admin 经隧道发出的每个请求都携带该令牌（Bearer），agent 校验后替换为自身 ServiceAccount 凭据。
+	SessionTokenHeader = "X-Kube-Admin-Tunnel-Token"
+
+	// ConnectPath agent 建立隧道的 WebSocket 路径
+	ConnectPath = "/api/v1/agent/connect"
+	// RegisterPath agent 凭 join token 换取长期凭据的路径
+	RegisterPath = "/api/v1/agent/register"
+)
+
+// wsConn 将 WebSocket 适配为 yamux 需要的字节流（io.ReadWriteCloser）。
+// 每次 Write 发送一个二进制帧；Read 跨帧连续读取。
+type wsConn struct {
+	ws     *websocket.Conn
+	reader io.Reader
+	rmu    sync.Mutex
+	wmu    sync.Mutex
+}
+
+// NewConn 包装 WebSocket 连接为字节流
+func NewConn(ws *websocket.Conn) io.ReadWriteCloser {
+	return &wsConn{ws: ws}
+}
+
+// Read 读取字节流，当前帧读完后自动切换到下一帧
+func (c *wsConn) Read(p []byte) (int, error) {
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+	for {
+		if c.reader == nil {
+			_, r, err := c.ws.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			c.reader = r
+		}
+		n, err := c.reader.Read(p)
+		if err == io.EOF {
+			c.reader = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Write 以二进制帧写出（gorilla websocket 不允许并发写，需加锁）
+func (c *wsConn) Write(p []byte) (int, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if err := c.ws.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close 关闭底层 WebSocket
+func (c *wsConn) Close() error {
+	return c.ws.Close()
+}
+
+// yamuxConfig 隧道会话配置：开启 keepalive 以便及时发现断连
+func yamuxConfig() *yamux.Config {
+	cfg := yamux.DefaultConfig()
+	cfg.EnableKeepAlive = true
+	cfg.KeepAliveInterval = 15 * time.Second
+	cfg.ConnectionWriteTimeout = 30 * time.Second
+	cfg.LogOutput = io.Discard
+	return cfg
+}
+
+// NewServerSession 在 kube-admin 侧基于 agent 的 WebSocket 建立会话（由 kube-admin 发起流）
+func NewServerSession(ws *websocket.Conn) (*yamux.Session, error) {
+	return yamux.Client(NewConn(ws), yamuxConfig())
+}
+
+// NewAgentSession 在 agent 侧建立会话（agent 接受 kube-admin 发起的流）
+func NewAgentSession(ws *websocket.Conn) (*yamux.Session, error) {
+	return yamux.Server(NewConn(ws), yamuxConfig())
+}
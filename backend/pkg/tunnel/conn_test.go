@@ -0,0 +1,65 @@
+package tunnel
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestSessionRoundTrip 经 WebSocket 上的 yamux 会话打开流，数据应原样往返
+func TestSessionRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		// agent 侧：回显每个流
+		session, err := NewAgentSession(ws)
+		if err != nil {
+			return
+		}
+		for {
+			stream, err := session.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer stream.Close()
+				_, _ = io.Copy(stream, stream)
+			}()
+		}
+	}))
+	defer srv.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	session, err := NewServerSession(ws)
+	if err != nil {
+		t.Fatalf("session: %v", err)
+	}
+	defer session.Close()
+
+	for _, msg := range []string{"hello", strings.Repeat("x", 100000)} {
+		stream, err := session.Open()
+		if err != nil {
+			t.Fatalf("open stream: %v", err)
+		}
+		if _, err := stream.Write([]byte(msg)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		buf := make([]byte, len(msg))
+		if _, err := io.ReadFull(stream, buf); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if string(buf) != msg {
+			t.Fatalf("往返数据不一致，长度 %d", len(msg))
+		}
+		stream.Close()
+	}
+}
@@ -485,10 +485,36 @@ export const restartResource = (gvr: GVR, namespace: string, name: string) => {
   return request.put(`/api/v1/resources/${name}/restart`, null, { params })
 }
 
-// 应用 YAML（创建或更新任意资源）
-export const applyResource = (yaml: string) => {
+// 应用 YAML（server-side apply，可含多个文档；force 强制接管其他 manager 的字段）
+export const applyResource = (yaml: string, options: { force?: boolean; continue_on_error?: boolean; namespace?: string; dry_run?: boolean } = {}) => {
   const clusterId = getCurrentClusterId()
   const params: any = {}
   if (clusterId) params.cluster_id = clusterId
-  return request.post('/api/v1/resources/apply', { yaml }, { params })
-}
\ No newline at end of file
+  return request.post('/api/v1/resources/apply', { yaml, ...options }, { params })
+}
+
+// 预览 apply 会产生的变更（服务端试运行，不修改任何对象）
+export const diffResource = (yaml: string, options: { force?: boolean; namespace?: string } = {}) => {
+  const clusterId = getCurrentClusterId()
+  const params: any = {}
+  if (clusterId) params.cluster_id = clusterId
+  return request.post('/api/v1/resources/diff', { yaml, ...options }, { params })
+}
+// 导出为可直接 apply 的清理后 YAML（format: yaml 多文档 | zip 每个对象一个文件）
+export const exportResource = (gvr: GVR, namespace: string, name: string, format: 'yaml' | 'zip' = 'yaml') => {
+  const clusterId = getCurrentClusterId()
+  const params = gvrParams(gvr, { format })
+  if (namespace) params.namespace = namespace
+  if (clusterId) params.cluster_id = clusterId
+  return request.get(`/api/v1/resources/${name}/export`, { params, responseType: 'blob' })
+}
+
+// 导出整个命名空间（kinds 缺省为全部可导出资源；include_owned 包含控制器生成的对象）
+export const exportNamespace = (namespace: string, options: { kinds?: string[]; include_owned?: boolean; format?: 'yaml' | 'zip' } = {}) => {
+  const clusterId = getCurrentClusterId()
+  const params: any = { format: options.format || 'yaml' }
+  if (options.kinds?.length) params.kinds = options.kinds.join(',')
+  if (options.include_owned) params.include_owned = true
+  if (clusterId) params.cluster_id = clusterId
+  return request.get(`/api/v1/namespaces/${namespace}/export`, { params, responseType: 'blob' })
+}